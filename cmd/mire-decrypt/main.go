@@ -0,0 +1,93 @@
+// Command mire-decrypt decrypts the field values encrypted by the encrypt
+// package in log files, for operators holding the keys.
+//
+// Usage:
+//
+//	mire-decrypt -keys keys.txt [file ...]
+//
+// The key file holds one key per line: the key ID and the base64 key. Logs are
+// read from the files, or from standard input, and written to standard output
+// with every encrypted value replaced by its plaintext, escaped as
+// encrypt.DecryptText does so that JSON lines stay valid and every entry stays
+// on one line. Values that cannot be decrypted are left as they are and make
+// the command exit with status 1.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Lunar-Chipter/mire/encrypt"
+)
+
+func main() {
+	keys := flag.String("keys", "", "path of the key file")
+	flag.Parse()
+	if *keys == "" {
+		fmt.Fprintln(os.Stderr, "mire-decrypt: -keys is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	f, err := os.Open(*keys)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "mire-decrypt:", err)
+		os.Exit(2)
+	}
+	ring, err := encrypt.ReadKeyring(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mire-decrypt: %s: %v\n", *keys, err)
+		os.Exit(2)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	failed := false
+	if flag.NArg() == 0 {
+		failed = decrypt(ring, os.Stdin, out, "stdin")
+	}
+	for _, name := range flag.Args() {
+		in, err := os.Open(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "mire-decrypt:", err)
+			failed = true
+			continue
+		}
+		if decrypt(ring, in, out, name) {
+			failed = true
+		}
+		in.Close()
+	}
+	if err := out.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, "mire-decrypt:", err)
+		failed = true
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// decrypt copies the lines of in to out with their encrypted values decrypted,
+// and reports whether some values could not be decrypted
+func decrypt(ring encrypt.Keyring, in io.Reader, out io.Writer, name string) bool {
+	failed := false
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text, err := encrypt.DecryptText(ring, scanner.Bytes())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "mire-decrypt: %s:%d: %v\n", name, line, err)
+			failed = true
+		}
+		out.Write(text)
+		out.Write([]byte{'\n'})
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "mire-decrypt: %s: %v\n", name, err)
+		failed = true
+	}
+	return failed
+}
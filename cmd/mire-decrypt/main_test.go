@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/encrypt"
+	"github.com/Lunar-Chipter/mire/formatter"
+	"github.com/Lunar-Chipter/mire/logger"
+)
+
+// TestDecryptJSON tests that decrypted JSON lines stay valid JSON, one per
+// line, whatever the plaintext holds
+func TestDecryptJSON(t *testing.T) {
+	ring := encrypt.NewStaticKeyring()
+	if err := ring.Add("k1", bytes.Repeat([]byte{1}, 32)); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	e, err := encrypt.New(encrypt.Config{Keyring: ring, Fields: []string{"note"}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	var logs bytes.Buffer
+	l := logger.New(logger.LoggerConfig{Level: core.INFO, Output: &logs, Formatter: formatter.NewJSON(), Encryptor: e})
+	note := "line 1\nline 2 \"quoted\" \\ \t\x01"
+	l.InfoT("support", logger.String("note", note))
+	l.InfoT("plain", logger.String("other", "value"))
+	l.Close()
+	if strings.Contains(logs.String(), "line 1") {
+		t.Fatalf("Expected the note to be encrypted, got %s", logs.String())
+	}
+
+	var out bytes.Buffer
+	if failed := decrypt(ring, &logs, &out, "test"); failed {
+		t.Fatal("Expected every value to be decrypted")
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d: %q", len(lines), out.String())
+	}
+	var decoded struct {
+		Fields map[string]string `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &decoded); err != nil {
+		t.Fatalf("Decrypted line is not valid JSON: %v\n%s", err, lines[0])
+	}
+	if decoded.Fields["note"] != note {
+		t.Errorf("Expected note %q, got %q", note, decoded.Fields["note"])
+	}
+}
@@ -6,11 +6,11 @@ import (
 
 // RotationConfig holds configuration for log rotation
 type RotationConfig struct {
-	MaxSize         int64
-	MaxAge          time.Duration
-	MaxBackups      int
-	LocalTime       bool
-	Compress        bool
-	RotateInterval  time.Duration
-	FilenamePattern string
+	MaxSize         int64         // Maximum size in bytes before the file is rotated (0 disables size rotation)
+	MaxAge          time.Duration // Maximum age of rotated files before they are removed (0 keeps them)
+	MaxBackups      int           // Maximum number of rotated files to keep (0 keeps all)
+	LocalTime       bool          // Use local time instead of UTC in backup file names
+	Compress        bool          // Gzip rotated files
+	RotateInterval  time.Duration // Rotate the file after this much time has elapsed (0 disables time rotation)
+	FilenamePattern string        // Time layout used in backup file names (defaults to writer.DefaultBackupTimeFormat)
 }
@@ -18,10 +18,12 @@ type LogEntry struct {
 	Message          []byte                                   `json:"message"`                  // Log message
 	Caller           *Caller                                  `json:"caller,omitempty"`         // Caller information
 	Fields           map[string][]byte                        `json:"fields,omitempty"`         // Additional fields as []byte for zero allocation
+	FieldKeys        []string                                 `json:"-"`                        // Keys of Fields in insertion order, maintained by SetField
 	PID              int                                      `json:"pid"`                      // Process ID
 	GoroutineID      []byte                                   `json:"goroutine_id,omitempty"`   // Goroutine ID as byte slice
 	TraceID          []byte                                   `json:"trace_id,omitempty"`       // Trace ID for distributed tracing as byte slice
 	SpanID           []byte                                   `json:"span_id,omitempty"`        // Span ID for distributed tracing as byte slice
+	TraceFlags       []byte                                   `json:"trace_flags,omitempty"`    // W3C trace flags as two hex digits
 	UserID           []byte                                   `json:"user_id,omitempty"`        // User ID as byte slice
 	SessionID        []byte                                   `json:"session_id,omitempty"`     // Session ID as byte slice
 	RequestID        []byte                                   `json:"request_id,omitempty"`     // Request ID as byte slice
@@ -36,6 +38,7 @@ type LogEntry struct {
 	CustomMetrics    map[string]float64                       `json:"custom_metrics,omitempty"` // Custom metrics
 	Tags             [][]byte                                 `json:"tags,omitempty"`           // Tags for categorization as byte slices
 	KeyVals          [][]byte                                 `json:"keyvals,omitempty"`        // Zero-allocation key-value pairs
+	TypedFields      []Field                                  `json:"-"`                        // Strongly typed fields encoded natively by formatters
 	_                [64 - unsafe.Sizeof(time.Time{})%64]byte // Padding for cache alignment
 }
 
@@ -46,9 +49,12 @@ func (e *LogEntry) Reset() {
 	e.Timestamp = time.Time{}
 	e.Caller = nil
 	e.Fields = nil
+	e.FieldKeys = nil
 	e.KeyVals = nil
+	e.TypedFields = nil
 	e.TraceID = nil
 	e.SpanID = nil
+	e.TraceFlags = nil
 	e.UserID = nil
 	e.RequestID = nil
 	e.SessionID = nil
@@ -67,6 +73,83 @@ func (e *LogEntry) Reset() {
 	e.StackTraceBufPtr = nil
 }
 
+// SetField sets a field of the entry. A new key is appended to FieldKeys;
+// an existing key keeps its position and takes the new value.
+func (e *LogEntry) SetField(key string, value []byte) {
+	if e.Fields == nil {
+		e.Fields = make(map[string][]byte, FieldsMapCapacity)
+	}
+	if _, ok := e.Fields[key]; !ok {
+		e.FieldKeys = append(e.FieldKeys, key)
+	}
+	e.Fields[key] = value
+}
+
+// SetFields sets the fields of a map in key order, so that fields coming from
+// a map, whose iteration order is random, get a deterministic position
+func (e *LogEntry) SetFields(fields map[string][]byte) {
+	if len(fields) == 1 {
+		for k, v := range fields {
+			e.SetField(k, v)
+		}
+		return
+	}
+	var stack [32]string
+	for _, k := range SortedKeys(stack[:0], fields) {
+		e.SetField(k, fields[k])
+	}
+}
+
+// DeleteField removes a field and its key from FieldKeys
+func (e *LogEntry) DeleteField(key string) {
+	if _, ok := e.Fields[key]; !ok {
+		return
+	}
+	delete(e.Fields, key)
+	for i, k := range e.FieldKeys {
+		if k == key {
+			e.FieldKeys = append(e.FieldKeys[:i], e.FieldKeys[i+1:]...)
+			break
+		}
+	}
+}
+
+// OrderedFieldKeys appends the keys of Fields to dst in insertion order. When
+// FieldKeys is out of step with Fields, because fields were set directly in
+// the map, the keys are appended in sorted order instead.
+func (e *LogEntry) OrderedFieldKeys(dst []string) []string {
+	if len(e.FieldKeys) == len(e.Fields) {
+		ordered := true
+		for _, k := range e.FieldKeys {
+			if _, ok := e.Fields[k]; !ok {
+				ordered = false
+				break
+			}
+		}
+		if ordered {
+			return append(dst, e.FieldKeys...)
+		}
+	}
+	return SortedKeys(dst, e.Fields)
+}
+
+// SortedKeys appends the keys of m to dst in sorted order. It sorts in place
+// with an insertion sort, so a dst with enough capacity on the caller's stack
+// keeps the keys off the heap for the small maps of log entries.
+func SortedKeys[V any](dst []string, m map[string]V) []string {
+	start := len(dst)
+	for k := range m {
+		dst = append(dst, k)
+	}
+	keys := dst[start:]
+	for i := 1; i < len(keys); i++ {
+		for j := i; j > 0 && keys[j] < keys[j-1]; j-- {
+			keys[j], keys[j-1] = keys[j-1], keys[j]
+		}
+	}
+	return dst
+}
+
 // Caller contains information about the code location where the log was created
 type Caller struct {
 	File     string `json:"file"`     // Source file name
@@ -287,12 +370,16 @@ func GetGlobalEntry() *LogEntry {
 	entry.Message = nil
 	entry.Caller = nil
 	clearMap(entry.Fields)
+	entry.FieldKeys = entry.FieldKeys[:0]
 	clearFloatMap(entry.CustomMetrics)
 	entry.Tags = clearByteSliceSlice(entry.Tags)
+	entry.KeyVals = nil
+	entry.TypedFields = nil
 	entry.PID = 0
 	entry.GoroutineID = nil
 	entry.TraceID = nil
 	entry.SpanID = nil
+	entry.TraceFlags = nil
 	entry.UserID = nil
 	entry.SessionID = nil
 	entry.RequestID = nil
@@ -397,6 +484,27 @@ func getGoroutineID() uint64 {
 	return id
 }
 
+// AppendGoroutineID appends the decimal ID of the calling goroutine to dst.
+// dst is returned unchanged if the ID cannot be determined.
+func AppendGoroutineID(dst []byte) []byte {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+
+	// Stack output starts with "goroutine 123 [running]:"
+	b := buf[:n]
+	if !bytes.HasPrefix(b, goroutinePrefix) {
+		return dst
+	}
+	b = b[len(goroutinePrefix):]
+	end := 0
+	for end < len(b) && b[end] >= '0' && b[end] <= '9' {
+		end++
+	}
+	return append(dst, b[:end]...)
+}
+
+var goroutinePrefix = []byte("goroutine ")
+
 // GetGoroutineLocalEntryPool gets entry pool for current goroutine
 func GetGoroutineLocalEntryPool() *LocalPool {
 	gid := getGoroutineID()
@@ -429,12 +537,16 @@ func (g *LocalPool) GetLocalEntry() *LogEntry {
 		entry.Message = nil
 		entry.Caller = nil
 		clearMap(entry.Fields)
+		entry.FieldKeys = entry.FieldKeys[:0]
 		clearFloatMap(entry.CustomMetrics)
 		entry.Tags = clearByteSliceSlice(entry.Tags)
+		entry.KeyVals = nil
+		entry.TypedFields = nil
 		entry.PID = 0
 		entry.GoroutineID = nil
 		entry.TraceID = nil
 		entry.SpanID = nil
+		entry.TraceFlags = nil
 		entry.UserID = nil
 		entry.SessionID = nil
 		entry.RequestID = nil
@@ -606,17 +718,17 @@ func (le *LogEntry) formatLogToBytes(buf []byte) []byte {
 	// Write fields if any
 	if len(le.Fields) > 0 {
 		buf = append(buf, '[')
-		first := true
-		for k, v := range le.Fields {
-			if !first {
+		var stack [32]string
+		for i, k := range le.OrderedFieldKeys(stack[:0]) {
+			if i > 0 {
 				buf = append(buf, ',')
 			}
+			v := le.Fields[k]
 			buf = append(buf, k...)
 			buf = append(buf, ':')
 
 			// Since v is []byte, we can directly append it
 			buf = append(buf, v...)
-			first = false
 		}
 		buf = append(buf, ']')
 		buf = append(buf, ' ')
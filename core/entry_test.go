@@ -2,6 +2,8 @@ package core
 
 import (
 	"context"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -557,3 +559,82 @@ func TestLogEntryFloatConversion(t *testing.T) {
 	}
 	// This is harder to test exactly due to floating point precision, but we can at least verify it's not empty
 }
+
+// TestAppendGoroutineID tests capturing the ID of the calling goroutine
+func TestAppendGoroutineID(t *testing.T) {
+	id := AppendGoroutineID([]byte("gid="))
+	if len(id) <= len("gid=") {
+		t.Fatalf("Expected a goroutine ID to be appended, got %q", id)
+	}
+
+	want := strconv.FormatUint(getGoroutineID(), 10)
+	if string(id[len("gid="):]) != want {
+		t.Errorf("Expected ID %s, got %q", want, id)
+	}
+
+	other := make(chan []byte)
+	go func() { other <- AppendGoroutineID(nil) }()
+	if string(<-other) == want {
+		t.Error("Expected a different ID on another goroutine")
+	}
+}
+
+// TestLogEntrySetField tests that fields keep their insertion order and that
+// setting a key again replaces the value in place
+func TestLogEntrySetField(t *testing.T) {
+	entry := GetEntryFromPool()
+	defer PutEntryToPool(entry)
+
+	entry.SetField("zeta", []byte("1"))
+	entry.SetField("alpha", []byte("2"))
+	entry.SetField("mid", []byte("3"))
+	entry.SetField("zeta", []byte("4"))
+
+	keys := entry.OrderedFieldKeys(nil)
+	if got := strings.Join(keys, ","); got != "zeta,alpha,mid" {
+		t.Errorf("Expected insertion order zeta,alpha,mid, got %s", got)
+	}
+	if string(entry.Fields["zeta"]) != "4" {
+		t.Errorf("Expected the last value to win, got %s", entry.Fields["zeta"])
+	}
+
+	entry.DeleteField("alpha")
+	entry.DeleteField("missing")
+	if got := strings.Join(entry.OrderedFieldKeys(nil), ","); got != "zeta,mid" {
+		t.Errorf("Expected zeta,mid after delete, got %s", got)
+	}
+
+	entry.SetFields(map[string][]byte{"c": nil, "b": nil, "a": nil})
+	if got := strings.Join(entry.OrderedFieldKeys(nil), ","); got != "zeta,mid,a,b,c" {
+		t.Errorf("Expected map fields appended in key order, got %s", got)
+	}
+
+	// Writing to the map directly bypasses FieldKeys; the keys are then sorted
+	entry.Fields["direct"] = nil
+	if got := strings.Join(entry.OrderedFieldKeys(nil), ","); got != "a,b,c,direct,mid,zeta" {
+		t.Errorf("Expected sorted fallback, got %s", got)
+	}
+}
+
+// TestLogEntryFieldKeysReset tests that pooled entries start without field keys
+func TestLogEntryFieldKeysReset(t *testing.T) {
+	entry := GetEntryFromPool()
+	entry.SetField("key", []byte("value"))
+	PutEntryToPool(entry)
+
+	for i := 0; i < PreallocatedPoolSize; i++ {
+		reused := GetEntryFromPool()
+		if len(reused.FieldKeys) != 0 || len(reused.Fields) != 0 {
+			t.Fatalf("Expected a reset entry, got keys %v and fields %v", reused.FieldKeys, reused.Fields)
+		}
+		defer PutEntryToPool(reused)
+	}
+}
+
+// TestSortedKeys tests sorting map keys into a caller-provided slice
+func TestSortedKeys(t *testing.T) {
+	keys := SortedKeys([]string{"first"}, map[string]int{"b": 1, "c": 2, "a": 3})
+	if got := strings.Join(keys, ","); got != "first,a,b,c" {
+		t.Errorf("Expected first,a,b,c, got %s", got)
+	}
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"bytes"
+	"reflect"
+)
+
+// maxErrorChain bounds the number of errors returned by ErrorChain
+const maxErrorChain = 32
+
+// StackTracer is implemented by errors that carry the stack trace of where they were created.
+// Loggers prefer it over the stack of the logging call site.
+type StackTracer interface {
+	StackTrace() []byte
+}
+
+// ErrorChain returns err followed by its causes in depth-first order.
+// It follows both Unwrap() error and Unwrap() []error, as produced by fmt.Errorf
+// with %w and by errors.Join. At most 32 errors are returned.
+func ErrorChain(err error) []error {
+	if err == nil {
+		return nil
+	}
+	chain := make([]error, 0, 4)
+	return appendErrorChain(chain, err)
+}
+
+func appendErrorChain(chain []error, err error) []error {
+	for err != nil && len(chain) < maxErrorChain {
+		chain = append(chain, err)
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, cause := range e.Unwrap() {
+				chain = appendErrorChain(chain, cause)
+			}
+			return chain
+		default:
+			return chain
+		}
+	}
+	return chain
+}
+
+// ErrorStack returns the stack trace of the first error in the chain of err that carries one
+func ErrorStack(err error) []byte {
+	for _, e := range ErrorChain(err) {
+		if st, ok := e.(StackTracer); ok {
+			if stack := st.StackTrace(); len(stack) > 0 {
+				return stack
+			}
+		}
+	}
+	return nil
+}
+
+// ErrorTypeName returns the dynamic type of err, for example "*fs.PathError"
+func ErrorTypeName(err error) string {
+	if err == nil {
+		return ""
+	}
+	return reflect.TypeOf(err).String()
+}
+
+// WriteError writes the message of err to buf, using ErrAppend when available
+func WriteError(buf *bytes.Buffer, err error) {
+	if appender, ok := err.(ErrAppend); ok {
+		appender.AppendError(buf)
+		return
+	}
+	buf.WriteString(err.Error())
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type stackError struct {
+	msg   string
+	stack []byte
+}
+
+func (e *stackError) Error() string      { return e.msg }
+func (e *stackError) StackTrace() []byte { return e.stack }
+
+// TestErrorChain tests walking wrapped and joined errors
+func TestErrorChain(t *testing.T) {
+	if ErrorChain(nil) != nil {
+		t.Error("Expected nil chain for nil error")
+	}
+
+	root := errors.New("root")
+	other := errors.New("other")
+	wrapped := fmt.Errorf("query: %w", root)
+	joined := errors.Join(wrapped, other)
+	top := fmt.Errorf("request: %w", joined)
+
+	chain := ErrorChain(top)
+	want := []error{top, joined, wrapped, root, other}
+	if len(chain) != len(want) {
+		t.Fatalf("Expected %d errors, got %d: %v", len(want), len(chain), chain)
+	}
+	for i := range want {
+		if chain[i] != want[i] {
+			t.Errorf("Chain[%d] = %v, want %v", i, chain[i], want[i])
+		}
+	}
+}
+
+// TestErrorChainBounded tests that very deep chains are truncated
+func TestErrorChainBounded(t *testing.T) {
+	err := errors.New("base")
+	for i := 0; i < 100; i++ {
+		err = fmt.Errorf("wrap: %w", err)
+	}
+	if n := len(ErrorChain(err)); n != maxErrorChain {
+		t.Errorf("Expected chain of %d, got %d", maxErrorChain, n)
+	}
+}
+
+// TestErrorStack tests finding the stack trace carried by a cause
+func TestErrorStack(t *testing.T) {
+	if ErrorStack(errors.New("plain")) != nil {
+		t.Error("Expected no stack for a plain error")
+	}
+
+	cause := &stackError{msg: "cause", stack: []byte("main.go:10")}
+	err := fmt.Errorf("outer: %w", cause)
+	if string(ErrorStack(err)) != "main.go:10" {
+		t.Errorf("Expected stack of the cause, got %q", ErrorStack(err))
+	}
+}
+
+// TestErrorTypeName tests reporting the dynamic error type
+func TestErrorTypeName(t *testing.T) {
+	if name := ErrorTypeName(&stackError{}); name != "*core.stackError" {
+		t.Errorf("Expected *core.stackError, got %s", name)
+	}
+	if name := ErrorTypeName(nil); name != "" {
+		t.Errorf("Expected empty name for nil, got %s", name)
+	}
+}
+
+// TestWriteError tests writing error messages
+func TestWriteError(t *testing.T) {
+	var buf bytes.Buffer
+	WriteError(&buf, errors.New("boom"))
+	if buf.String() != "boom" {
+		t.Errorf("Expected boom, got %s", buf.String())
+	}
+}
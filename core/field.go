@@ -0,0 +1,200 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// FieldType identifies how the value of a Field is stored and encoded
+type FieldType uint8
+
+const (
+	// StringField holds a string in Field.Str
+	StringField FieldType = iota + 1
+	// Int64Field holds a signed integer in Field.Int
+	Int64Field
+	// Uint64Field holds an unsigned integer in Field.Int
+	Uint64Field
+	// Float64Field holds the IEEE 754 bits of a float in Field.Int
+	Float64Field
+	// BoolField holds 1 or 0 in Field.Int
+	BoolField
+	// DurationField holds nanoseconds in Field.Int
+	DurationField
+	// TimeField holds Unix nanoseconds in Field.Int and the *time.Location in Field.Iface,
+	// or the time.Time in Field.Iface for times Unix nanoseconds cannot hold (before 1678,
+	// after 2262 or the zero time)
+	TimeField
+	// ErrorField holds an error in Field.Iface
+	ErrorField
+	// BytesField holds a []byte in Field.Iface
+	BytesField
+	// StringerField holds a Stringer in Field.Iface, resolved when the entry is formatted
+	StringerField
+	// AnyField holds an arbitrary value in Field.Iface, encoded as JSON
+	AnyField
+)
+
+// Stringer is implemented by values with a String method, like fmt.Stringer
+type Stringer interface {
+	String() string
+}
+
+// Field is a strongly typed key-value pair.
+// Scalar values are stored inline so building a Field does not allocate.
+type Field struct {
+	Key   string
+	Type  FieldType
+	Int   int64       // Integer, float bits, bool, duration or time value
+	Str   string      // String value
+	Iface interface{} // Error, Stringer, []byte, *time.Location, time.Time or arbitrary value
+}
+
+// Float returns the value of a Float64Field
+func (f Field) Float() float64 {
+	return math.Float64frombits(uint64(f.Int))
+}
+
+// Time returns the value of a TimeField
+func (f Field) Time() time.Time {
+	if t, ok := f.Iface.(time.Time); ok {
+		return t
+	}
+	t := time.Unix(0, f.Int)
+	if loc, ok := f.Iface.(*time.Location); ok && loc != nil {
+		return t.In(loc)
+	}
+	return t
+}
+
+// Value returns the field value as a Go value
+func (f Field) Value() interface{} {
+	switch f.Type {
+	case StringField:
+		return f.Str
+	case Int64Field:
+		return f.Int
+	case Uint64Field:
+		return uint64(f.Int)
+	case Float64Field:
+		return f.Float()
+	case BoolField:
+		return f.Int == 1
+	case DurationField:
+		return time.Duration(f.Int)
+	case TimeField:
+		return f.Time()
+	default:
+		return f.Iface
+	}
+}
+
+// IsJSONLiteral reports whether the value is encoded without quotes in JSON
+func (f Field) IsJSONLiteral() bool {
+	switch f.Type {
+	case Int64Field, Uint64Field, BoolField, DurationField:
+		return true
+	case Float64Field:
+		v := f.Float()
+		return !math.IsNaN(v) && !math.IsInf(v, 0)
+	case AnyField:
+		return f.Iface != nil
+	case ErrorField, StringerField:
+		return f.Iface == nil // Encoded as null
+	default:
+		return false
+	}
+}
+
+// AppendText appends the human-readable form of the value to dst
+func (f Field) AppendText(dst []byte) []byte {
+	switch f.Type {
+	case StringField:
+		return append(dst, f.Str...)
+	case Int64Field:
+		return strconv.AppendInt(dst, f.Int, 10)
+	case Uint64Field:
+		return strconv.AppendUint(dst, uint64(f.Int), 10)
+	case Float64Field:
+		return strconv.AppendFloat(dst, f.Float(), 'g', -1, 64)
+	case BoolField:
+		return strconv.AppendBool(dst, f.Int == 1)
+	case DurationField:
+		return append(dst, time.Duration(f.Int).String()...)
+	case TimeField:
+		return f.Time().AppendFormat(dst, time.RFC3339Nano)
+	case ErrorField:
+		if err, ok := f.Iface.(error); ok && err != nil {
+			if appender, ok := err.(ErrAppend); ok {
+				buf := bytes.NewBuffer(dst)
+				appender.AppendError(buf)
+				return buf.Bytes()
+			}
+			return append(dst, err.Error()...)
+		}
+		return append(dst, "<nil>"...)
+	case BytesField:
+		b, _ := f.Iface.([]byte)
+		return append(dst, b...)
+	case StringerField:
+		if s, ok := f.Iface.(Stringer); ok && s != nil {
+			return appendString(dst, s)
+		}
+		return append(dst, "<nil>"...)
+	default:
+		return f.appendAny(dst, "<unsupported>")
+	}
+}
+
+// appendString appends s.String() to dst. A panic in String is recovered as
+// fmt does: a nil pointer receiver is written as <nil>, anything else as a
+// PANIC marker, so a bad Stringer cannot crash the goroutine formatting it.
+func appendString(dst []byte, s Stringer) (out []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			if v := reflect.ValueOf(s); v.Kind() == reflect.Pointer && v.IsNil() {
+				out = append(dst, "<nil>"...)
+				return
+			}
+			out = fmt.Appendf(dst, "<PANIC=String method: %v>", r)
+		}
+	}()
+	return append(dst, s.String()...)
+}
+
+// AppendJSON appends the value encoded as a JSON literal to dst.
+// When IsJSONLiteral is false the raw text is appended, and callers quote
+// and escape it with their own JSON string encoder. NaN and infinite floats
+// are not valid JSON numbers and are therefore treated as strings.
+func (f Field) AppendJSON(dst []byte) []byte {
+	switch f.Type {
+	case DurationField:
+		return strconv.AppendInt(dst, f.Int, 10)
+	case ErrorField, StringerField:
+		if f.Iface == nil {
+			return append(dst, "null"...)
+		}
+		return f.AppendText(dst)
+	case AnyField:
+		return f.appendAny(dst, "null")
+	default:
+		return f.AppendText(dst)
+	}
+}
+
+// appendAny appends an arbitrary value encoded as JSON, or fallback if it cannot be encoded
+func (f Field) appendAny(dst []byte, fallback string) []byte {
+	if f.Iface == nil {
+		return append(dst, "null"...)
+	}
+	data, err := json.Marshal(f.Iface)
+	if err != nil {
+		return append(dst, fallback...)
+	}
+	return append(dst, data...)
+}
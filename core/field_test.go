@@ -0,0 +1,117 @@
+package core
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+type testStringer struct{ s string }
+
+func (t testStringer) String() string { return t.s }
+
+// ptrStringer dereferences its receiver, so a nil pointer panics
+type ptrStringer struct{ s string }
+
+func (p *ptrStringer) String() string { return p.s }
+
+// panicStringer always panics
+type panicStringer struct{}
+
+func (panicStringer) String() string { panic("bad stringer") }
+
+func floatField(v float64) Field {
+	return Field{Key: "f", Type: Float64Field, Int: int64(math.Float64bits(v))}
+}
+
+// TestFieldAppendText tests the text encoding of each field type
+func TestFieldAppendText(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 12, 30, 0, 5, time.UTC)
+	tests := []struct {
+		name  string
+		field Field
+		want  string
+	}{
+		{"string", Field{Type: StringField, Str: "hello"}, "hello"},
+		{"int64", Field{Type: Int64Field, Int: -42}, "-42"},
+		{"uint64", Field{Type: Uint64Field, Int: -1}, "18446744073709551615"},
+		{"float64", floatField(1.5), "1.5"},
+		{"bool true", Field{Type: BoolField, Int: 1}, "true"},
+		{"bool false", Field{Type: BoolField}, "false"},
+		{"duration", Field{Type: DurationField, Int: int64(1500 * time.Millisecond)}, "1.5s"},
+		{"time", Field{Type: TimeField, Int: ts.UnixNano(), Iface: time.UTC}, "2024-03-01T12:30:00.000000005Z"},
+		{"error", Field{Type: ErrorField, Iface: errors.New("boom")}, "boom"},
+		{"nil error", Field{Type: ErrorField}, "<nil>"},
+		{"bytes", Field{Type: BytesField, Iface: []byte("raw")}, "raw"},
+		{"stringer", Field{Type: StringerField, Iface: testStringer{"str"}}, "str"},
+		{"typed nil stringer", Field{Type: StringerField, Iface: (*ptrStringer)(nil)}, "<nil>"},
+		{"panicking stringer", Field{Type: StringerField, Iface: panicStringer{}}, "<PANIC=String method: bad stringer>"},
+		{"any", Field{Type: AnyField, Iface: map[string]int{"a": 1}}, `{"a":1}`},
+		{"unsupported", Field{Type: AnyField, Iface: make(chan int)}, "<unsupported>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(tt.field.AppendText(nil)); got != tt.want {
+				t.Errorf("AppendText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFieldAppendJSON tests the JSON encoding and literal detection of each field type
+func TestFieldAppendJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   Field
+		want    string
+		literal bool
+	}{
+		{"string", Field{Type: StringField, Str: "hello"}, "hello", false},
+		{"int64", Field{Type: Int64Field, Int: 7}, "7", true},
+		{"float64", floatField(0.25), "0.25", true},
+		{"NaN", floatField(math.NaN()), "NaN", false},
+		{"Inf", floatField(math.Inf(1)), "+Inf", false},
+		{"bool", Field{Type: BoolField, Int: 1}, "true", true},
+		{"duration", Field{Type: DurationField, Int: 1500}, "1500", true},
+		{"error", Field{Type: ErrorField, Iface: errors.New("boom")}, "boom", false},
+		{"nil error", Field{Type: ErrorField}, "null", true},
+		{"nil stringer", Field{Type: StringerField}, "null", true},
+		{"any", Field{Type: AnyField, Iface: []int{1, 2}}, "[1,2]", true},
+		{"nil any", Field{Type: AnyField}, "null", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(tt.field.AppendJSON(nil)); got != tt.want {
+				t.Errorf("AppendJSON() = %q, want %q", got, tt.want)
+			}
+			if got := tt.field.IsJSONLiteral(); got != tt.literal {
+				t.Errorf("IsJSONLiteral() = %v, want %v", got, tt.literal)
+			}
+		})
+	}
+}
+
+// TestFieldValue tests converting fields back to Go values
+func TestFieldValue(t *testing.T) {
+	if v := floatField(2.5).Value(); v != 2.5 {
+		t.Errorf("Expected 2.5, got %v", v)
+	}
+	if v := (Field{Type: BoolField, Int: 1}).Value(); v != true {
+		t.Errorf("Expected true, got %v", v)
+	}
+	if v := (Field{Type: DurationField, Int: int64(time.Second)}).Value(); v != time.Second {
+		t.Errorf("Expected 1s, got %v", v)
+	}
+	loc := time.FixedZone("X", 3600)
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, loc)
+	got := Field{Type: TimeField, Int: ts.UnixNano(), Iface: loc}.Time()
+	if !got.Equal(ts) || got.Location() != loc {
+		t.Errorf("Expected %v, got %v", ts, got)
+	}
+	if got := (Field{Type: TimeField, Iface: time.Time{}}).Time(); !got.IsZero() {
+		t.Errorf("Expected the zero time, got %v", got)
+	}
+}
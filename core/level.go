@@ -3,6 +3,7 @@ package core
 import (
 	"bytes" // Added for bytes.EqualFold
 	"strings"
+	"sync/atomic"
 
 	"github.com/Lunar-Chipter/mire/errors"
 	"unsafe"
@@ -175,3 +176,31 @@ func ParseLevel(levelStr string) (Level, error) {
 
 	return INFO, errors.NewInvalidLevel(levelStr)
 }
+
+// AtomicLevel is a level that can be read and changed concurrently.
+// Loggers sharing an AtomicLevel observe a change on their next log call.
+type AtomicLevel struct {
+	v atomic.Int32
+}
+
+// NewAtomicLevel creates an AtomicLevel set to level
+func NewAtomicLevel(level Level) *AtomicLevel {
+	a := &AtomicLevel{}
+	a.v.Store(int32(level))
+	return a
+}
+
+// Level returns the current level
+func (a *AtomicLevel) Level() Level {
+	return Level(a.v.Load())
+}
+
+// SetLevel changes the level
+func (a *AtomicLevel) SetLevel(level Level) {
+	a.v.Store(int32(level))
+}
+
+// Enabled reports whether entries at level pass the current level
+func (a *AtomicLevel) Enabled(level Level) bool {
+	return level >= a.Level()
+}
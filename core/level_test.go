@@ -131,3 +131,22 @@ func TestParseLevel(t *testing.T) {
 		})
 	}
 }
+
+// TestAtomicLevel tests reading and changing an AtomicLevel
+func TestAtomicLevel(t *testing.T) {
+	a := NewAtomicLevel(INFO)
+	if a.Level() != INFO {
+		t.Errorf("Expected INFO, got %v", a.Level())
+	}
+	if a.Enabled(DEBUG) {
+		t.Error("DEBUG should not be enabled at INFO")
+	}
+	if !a.Enabled(WARN) {
+		t.Error("WARN should be enabled at INFO")
+	}
+
+	a.SetLevel(DEBUG)
+	if a.Level() != DEBUG || !a.Enabled(DEBUG) {
+		t.Errorf("Expected DEBUG to be enabled after SetLevel, got %v", a.Level())
+	}
+}
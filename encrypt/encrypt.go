@@ -0,0 +1,331 @@
+// Package encrypt encrypts designated field values of log entries with
+// AES-GCM, so that they can be logged for support but stay unreadable to
+// log consumers without the key.
+//
+// An encrypted value is written as a single token in place of the original
+// value: "enc:v1:<key ID>:<base64url nonce and ciphertext>". The key ID and
+// version are authenticated with the ciphertext.
+package encrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"sync"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/errors"
+)
+
+const (
+	// Prefix starts every encrypted value
+	Prefix = "enc:v1:"
+	// Failed replaces a value that could not be encrypted, so that it is never written in clear
+	Failed = "[ENCRYPTION FAILED]"
+)
+
+// Config configures an Encryptor
+type Config struct {
+	Keyring Keyring  // Keys used to encrypt values
+	Fields  []string // Keys of the fields to encrypt, matched case-insensitively
+}
+
+// Encryptor encrypts the values of designated fields.
+// It is safe for concurrent use.
+type Encryptor struct {
+	conf    Config
+	ciphers sync.Map // Key ID -> *cachedCipher
+}
+
+// cachedCipher is the AEAD of a key, kept to avoid setting up AES for every value
+type cachedCipher struct {
+	key  []byte
+	aead cipher.AEAD
+}
+
+// New creates an Encryptor. It returns errors.ErrKeyringRequired if
+// conf.Keyring is nil.
+func New(conf Config) (*Encryptor, error) {
+	if conf.Keyring == nil {
+		return nil, errors.ErrKeyringRequired
+	}
+	return &Encryptor{conf: conf}, nil
+}
+
+// Encrypt encrypts value with the current key of the keyring
+func (e *Encryptor) Encrypt(value []byte) ([]byte, error) {
+	id, key, err := e.conf.Keyring.Current()
+	if err != nil {
+		return nil, err
+	}
+	aead, err := e.cipher(id, key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := appendHeader(make([]byte, 0, len(Prefix)+len(id)+1+base64.RawURLEncoding.EncodedLen(aead.NonceSize()+len(value)+aead.Overhead())), id)
+	sealed := make([]byte, aead.NonceSize(), aead.NonceSize()+len(value)+aead.Overhead())
+	if _, err := rand.Read(sealed); err != nil {
+		return nil, err
+	}
+	sealed = aead.Seal(sealed, sealed, value, header)
+	return base64.RawURLEncoding.AppendEncode(append(header, ':'), sealed), nil
+}
+
+// EncryptEntry encrypts the values of the designated fields, key-value pairs
+// and typed fields of an entry, and returns the number of values encrypted.
+// Values are replaced, never modified in place, and key-value pairs and typed
+// fields are copied first, since they belong to the caller. An encrypted
+// typed field becomes a string field. A value that cannot be encrypted is
+// replaced with Failed and the first error is returned.
+func (e *Encryptor) EncryptEntry(entry *core.LogEntry) (int, error) {
+	if len(e.conf.Fields) == 0 {
+		return 0, nil
+	}
+	n := 0
+	var firstErr error
+	encrypt := func(value []byte) []byte {
+		out, err := e.Encrypt(value)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return []byte(Failed)
+		}
+		n++
+		return out
+	}
+
+	for k, v := range entry.Fields {
+		if e.designated(k) {
+			entry.Fields[k] = encrypt(v)
+		}
+	}
+
+	copied := false
+	for i := 0; i+1 < len(entry.KeyVals); i += 2 {
+		if !e.designated(core.BytesToString(entry.KeyVals[i])) {
+			continue
+		}
+		if !copied {
+			entry.KeyVals = append([][]byte(nil), entry.KeyVals...)
+			copied = true
+		}
+		entry.KeyVals[i+1] = encrypt(entry.KeyVals[i+1])
+	}
+
+	copied = false
+	for i := range entry.TypedFields {
+		field := entry.TypedFields[i]
+		if !e.designated(field.Key) {
+			continue
+		}
+		if !copied {
+			entry.TypedFields = append([]core.Field(nil), entry.TypedFields...)
+			copied = true
+		}
+		var value []byte
+		switch field.Type {
+		case core.StringField:
+			value = core.StringToBytes(field.Str)
+		case core.BytesField:
+			value, _ = field.Iface.([]byte)
+		default:
+			value = field.AppendText(nil)
+		}
+		entry.TypedFields[i] = core.Field{Key: field.Key, Type: core.StringField, Str: core.BytesToString(encrypt(value))}
+	}
+	return n, firstErr
+}
+
+// designated reports whether the value of the field with the given key is encrypted
+func (e *Encryptor) designated(key string) bool {
+	for _, f := range e.conf.Fields {
+		if strings.EqualFold(f, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// cipher returns the AEAD of a key, set up once per key ID and key
+func (e *Encryptor) cipher(id string, key []byte) (cipher.AEAD, error) {
+	if c, ok := e.ciphers.Load(id); ok && bytes.Equal(c.(*cachedCipher).key, key) {
+		return c.(*cachedCipher).aead, nil
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	e.ciphers.Store(id, &cachedCipher{key: append([]byte(nil), key...), aead: aead})
+	return aead, nil
+}
+
+// Decrypt decrypts a value written by an Encryptor with a key of ring
+func Decrypt(ring Keyring, value []byte) ([]byte, error) {
+	if !bytes.HasPrefix(value, []byte(Prefix)) {
+		return nil, errors.ErrInvalidCiphertext
+	}
+	rest := value[len(Prefix):]
+	sep := bytes.IndexByte(rest, ':')
+	if sep <= 0 {
+		return nil, errors.ErrInvalidCiphertext
+	}
+	id := string(rest[:sep])
+	key, err := ring.Key(id)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := base64.RawURLEncoding.AppendDecode(nil, rest[sep+1:])
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, errors.ErrInvalidCiphertext
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plain, err := aead.Open(ciphertext[:0], nonce, ciphertext, value[:len(Prefix)+sep])
+	if err != nil {
+		return nil, errors.ErrInvalidCiphertext
+	}
+	return plain, nil
+}
+
+// DecryptText decrypts every encrypted value found in text, such as a
+// formatted log line, and returns the text with the values replaced by their
+// plaintext. The plaintext is escaped so that the line stays one valid line
+// and cannot forge fields: with JSON string escaping when the value follows a
+// double quote, and otherwise quoted and escaped the same way when it holds
+// spaces, '=', quotes, backslashes or control characters, as logfmt values
+// are. Values that cannot be decrypted are left in place and the first error
+// is returned.
+func DecryptText(ring Keyring, text []byte) ([]byte, error) {
+	var out []byte
+	var firstErr error
+	prev := 0
+	for i := 0; ; {
+		start := bytes.Index(text[i:], []byte(Prefix))
+		if start < 0 {
+			break
+		}
+		start += i
+		end := tokenEnd(text, start)
+		i = end
+		if end == start+len(Prefix) {
+			i = start + 1
+			continue
+		}
+		plain, err := Decrypt(ring, text[start:end])
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		out = append(out, text[prev:start]...)
+		out = appendPlaintext(out, plain, start > 0 && text[start-1] == '"')
+		prev = end
+	}
+	if out == nil {
+		return text, firstErr
+	}
+	return append(out, text[prev:]...), firstErr
+}
+
+// tokenEnd returns the end of the encrypted value starting at start: the key
+// ID, a colon and the base64url data. It returns start+len(Prefix) when there
+// is no valid value.
+func tokenEnd(text []byte, start int) int {
+	i := start + len(Prefix)
+	id := i
+	for i < len(text) && isKeyIDChar(text[i]) {
+		i++
+	}
+	if i == id || i >= len(text) || text[i] != ':' {
+		return start + len(Prefix)
+	}
+	i++
+	data := i
+	for i < len(text) && isBase64URLChar(text[i]) {
+		i++
+	}
+	if i == data {
+		return start + len(Prefix)
+	}
+	return i
+}
+
+// appendPlaintext appends a decrypted value. A quoted value is escaped as a
+// JSON string. An unquoted value that would not read back as a single logfmt
+// value, being empty or holding spaces, '=', quotes, backslashes or control
+// characters, is quoted and escaped the same way.
+func appendPlaintext(dst, plain []byte, quoted bool) []byte {
+	if quoted {
+		return appendEscaped(dst, plain)
+	}
+	if !needsQuote(plain) {
+		return append(dst, plain...)
+	}
+	dst = append(dst, '"')
+	dst = appendEscaped(dst, plain)
+	return append(dst, '"')
+}
+
+// appendEscaped appends plain escaped as the content of a JSON string
+func appendEscaped(dst, plain []byte) []byte {
+	const hexDigits = "0123456789abcdef"
+	for _, c := range plain {
+		switch {
+		case c == '"' || c == '\\':
+			dst = append(dst, '\\', c)
+		case c == '\n':
+			dst = append(dst, '\\', 'n')
+		case c == '\r':
+			dst = append(dst, '\\', 'r')
+		case c == '\t':
+			dst = append(dst, '\\', 't')
+		case c < ' ' || c == 0x7f:
+			dst = append(dst, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+		default:
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
+
+// needsQuote reports whether an unquoted value must be quoted to stay a single value
+func needsQuote(value []byte) bool {
+	if len(value) == 0 {
+		return true
+	}
+	for _, c := range value {
+		if c <= ' ' || c == '=' || c == '"' || c == '\\' || c == 0x7f {
+			return true
+		}
+	}
+	return false
+}
+
+// appendHeader appends the authenticated part of an encrypted value
+func appendHeader(dst []byte, id string) []byte {
+	dst = append(dst, Prefix...)
+	return append(dst, id...)
+}
+
+// newAEAD sets up AES-GCM with key
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.ErrInvalidEncryptionKey
+	}
+	return cipher.NewGCM(block)
+}
+
+// isBase64URLChar reports whether c belongs to the unpadded base64url alphabet
+func isBase64URLChar(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '-' || c == '_'
+}
@@ -0,0 +1,154 @@
+package encrypt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/errors"
+)
+
+// newTestKeyring creates a keyring holding k1 as current key
+func newTestKeyring(t *testing.T) *StaticKeyring {
+	k := NewStaticKeyring()
+	if err := k.Add("k1", bytes.Repeat([]byte{1}, 32)); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	return k
+}
+
+// TestEncryptDecrypt tests round trips, key rotation and tampering
+func TestEncryptDecrypt(t *testing.T) {
+	ring := newTestKeyring(t)
+	e, err := New(Config{Keyring: ring})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	first, err := e.Encrypt([]byte("AB123456C"))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if !strings.HasPrefix(string(first), Prefix+"k1:") || strings.Contains(string(first), "AB123456C") {
+		t.Errorf("Unexpected encrypted value %q", first)
+	}
+	if again, _ := e.Encrypt([]byte("AB123456C")); bytes.Equal(first, again) {
+		t.Error("Expected a fresh nonce for every value")
+	}
+
+	ring.Add("k2", bytes.Repeat([]byte{2}, 16))
+	ring.SetCurrent("k2")
+	second, _ := e.Encrypt([]byte("AB123456C"))
+	if !strings.HasPrefix(string(second), Prefix+"k2:") {
+		t.Errorf("Expected the rotated key to be used, got %q", second)
+	}
+	for _, value := range [][]byte{first, second} {
+		if plain, err := Decrypt(ring, value); err != nil || string(plain) != "AB123456C" {
+			t.Errorf("Decrypt(%q) = %q, %v", value, plain, err)
+		}
+	}
+
+	// The key ID is authenticated: a value cannot be relabeled
+	relabeled := bytes.Replace(first, []byte("k1"), []byte("k2"), 1)
+	ring.Add("k2", bytes.Repeat([]byte{1}, 32))
+	if _, err := Decrypt(ring, relabeled); err != errors.ErrInvalidCiphertext {
+		t.Errorf("Expected ErrInvalidCiphertext for a relabeled value, got %v", err)
+	}
+	tampered := append([]byte(nil), first...)
+	tampered[len(tampered)-2] ^= 1
+	if _, err := Decrypt(ring, tampered); err != errors.ErrInvalidCiphertext {
+		t.Errorf("Expected ErrInvalidCiphertext for a tampered value, got %v", err)
+	}
+	if _, err := Decrypt(ring, []byte(Prefix+"k9:AAAA")); err != errors.ErrEncryptionKeyNotFound {
+		t.Errorf("Expected ErrEncryptionKeyNotFound, got %v", err)
+	}
+
+	if _, err := New(Config{}); err != errors.ErrKeyringRequired {
+		t.Errorf("Expected ErrKeyringRequired, got %v", err)
+	}
+}
+
+// TestEncryptEntry tests encryption of every field kind without modifying
+// the slices owned by the caller
+func TestEncryptEntry(t *testing.T) {
+	ring := newTestKeyring(t)
+	e, _ := New(Config{Keyring: ring, Fields: []string{"national_id", "address", "zip"}})
+
+	keyvals := [][]byte{[]byte("Address"), []byte("1 Main St"), []byte("user"), []byte("jane")}
+	typed := []core.Field{
+		{Key: "zip", Type: core.Int64Field, Int: 12345},
+		{Key: "attempts", Type: core.Int64Field, Int: 3},
+	}
+	entry := &core.LogEntry{KeyVals: keyvals, TypedFields: typed}
+	entry.SetField("national_id", []byte("AB123456C"))
+	entry.SetField("host", []byte("db1"))
+
+	n, err := e.EncryptEntry(entry)
+	if err != nil || n != 3 {
+		t.Fatalf("EncryptEntry = %d, %v", n, err)
+	}
+	if plain, _ := Decrypt(ring, entry.Fields["national_id"]); string(plain) != "AB123456C" || string(entry.Fields["host"]) != "db1" {
+		t.Errorf("Unexpected fields %q", entry.Fields)
+	}
+	if plain, _ := Decrypt(ring, entry.KeyVals[1]); string(plain) != "1 Main St" || string(keyvals[1]) != "1 Main St" {
+		t.Errorf("Unexpected key-value pairs %q", entry.KeyVals)
+	}
+	zip := entry.TypedFields[0]
+	if plain, _ := Decrypt(ring, []byte(zip.Str)); zip.Type != core.StringField || string(plain) != "12345" || typed[0].Type != core.Int64Field {
+		t.Errorf("Unexpected typed field %+v", zip)
+	}
+
+	// Without a current key, values are never written in clear
+	entry = &core.LogEntry{}
+	entry.SetField("national_id", []byte("AB123456C"))
+	e, _ = New(Config{Keyring: NewStaticKeyring(), Fields: []string{"national_id"}})
+	if _, err := e.EncryptEntry(entry); err != errors.ErrEncryptionKeyNotFound || string(entry.Fields["national_id"]) != Failed {
+		t.Errorf("Expected failed encryption to replace the value, got %q, %v", entry.Fields["national_id"], err)
+	}
+}
+
+// TestDecryptText tests decryption of the values of formatted log lines
+func TestDecryptText(t *testing.T) {
+	ring := newTestKeyring(t)
+	e, _ := New(Config{Keyring: ring})
+	id, _ := e.Encrypt([]byte("AB123456C"))
+	addr, _ := e.Encrypt([]byte("1 Main St"))
+
+	line := []byte(`{"msg":"support","national_id":"` + string(id) + `","address":"` + string(addr) + `"}`)
+	got, err := DecryptText(ring, line)
+	if err != nil || string(got) != `{"msg":"support","national_id":"AB123456C","address":"1 Main St"}` {
+		t.Errorf("DecryptText = %q, %v", got, err)
+	}
+
+	line = []byte("id=" + Prefix + "k9:AAAA note=" + Prefix + " addr=" + string(addr))
+	got, err = DecryptText(ring, line)
+	if err != errors.ErrEncryptionKeyNotFound || string(got) != "id="+Prefix+"k9:AAAA note="+Prefix+` addr="1 Main St"` {
+		t.Errorf("DecryptText = %q, %v", got, err)
+	}
+
+	plain := []byte("no encrypted values")
+	if got, err := DecryptText(ring, plain); err != nil || &got[0] != &plain[0] {
+		t.Error("Expected text without encrypted values to be returned as is")
+	}
+
+	// Plaintext cannot break the quoting of a value or split the line
+	note, _ := e.Encrypt([]byte("say \"hi\"\\\n\x01\tx"))
+	got, err = DecryptText(ring, []byte(`{"note":"`+string(note)+`"}`))
+	if err != nil || string(got) != `{"note":"say \"hi\"\\\n\u0001\tx"}` {
+		t.Errorf("DecryptText = %q, %v", got, err)
+	}
+	got, err = DecryptText(ring, []byte("note="+string(note)+" level=info"))
+	if err != nil || string(got) != `note="say \"hi\"\\\n\u0001\tx" level=info` {
+		t.Errorf("DecryptText = %q, %v", got, err)
+	}
+	forged, _ := e.Encrypt([]byte(`x level=error user=admin`))
+	got, err = DecryptText(ring, []byte("note="+string(forged)+" level=info"))
+	if err != nil || string(got) != `note="x level=error user=admin" level=info` {
+		t.Errorf("DecryptText = %q, %v", got, err)
+	}
+	got, err = DecryptText(ring, []byte("note="+string(id)))
+	if err != nil || string(got) != "note=AB123456C" {
+		t.Errorf("DecryptText = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,152 @@
+package encrypt
+
+import (
+	"bufio"
+	"encoding/base64"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/Lunar-Chipter/mire/errors"
+)
+
+// maxKeyIDSize is the maximum size of a key ID
+const maxKeyIDSize = 64
+
+// Keyring supplies the AES keys of an Encryptor by ID. Key IDs are written
+// with the ciphertext, so a value can be decrypted after the current key has
+// changed, as long as the keyring still holds the key that encrypted it.
+// The returned keys must not be modified.
+type Keyring interface {
+	// Current returns the ID and the key that encrypt new values
+	Current() (id string, key []byte, err error)
+	// Key returns the key with the given ID
+	Key(id string) ([]byte, error)
+}
+
+// StaticKeyring is a Keyring holding its keys in memory. To rotate keys, add
+// the new key and make it current; keep the previous keys for as long as
+// values encrypted with them must stay readable. It is safe for concurrent use.
+type StaticKeyring struct {
+	mu      sync.RWMutex
+	keys    map[string][]byte
+	current string
+}
+
+// NewStaticKeyring creates an empty StaticKeyring
+func NewStaticKeyring() *StaticKeyring {
+	return &StaticKeyring{keys: make(map[string][]byte)}
+}
+
+// ReadKeyring reads a StaticKeyring from r, one key per line: the key ID and
+// the base64 key, separated by spaces. Empty lines and lines starting with
+// '#' are skipped. The last key becomes the current key, so keys are rotated
+// by appending a line.
+func ReadKeyring(r io.Reader) (*StaticKeyring, error) {
+	k := NewStaticKeyring()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) != 2 {
+			return nil, errors.ErrInvalidKeyFile
+		}
+		key, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, errors.ErrInvalidKeyFile
+		}
+		if err := k.Add(parts[0], key); err != nil {
+			return nil, err
+		}
+		k.current = parts[0]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Add adds a key, replacing any key with the same ID. The key must be 16, 24
+// or 32 bytes, for AES-128, AES-192 or AES-256. The first key added becomes
+// the current key.
+func (k *StaticKeyring) Add(id string, key []byte) error {
+	if !validKeyID(id) {
+		return errors.ErrInvalidKeyID
+	}
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return errors.ErrInvalidEncryptionKey
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[id] = append([]byte(nil), key...)
+	if k.current == "" {
+		k.current = id
+	}
+	return nil
+}
+
+// SetCurrent makes the key with the given ID encrypt new values
+func (k *StaticKeyring) SetCurrent(id string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.keys[id]; !ok {
+		return errors.ErrEncryptionKeyNotFound
+	}
+	k.current = id
+	return nil
+}
+
+// Remove removes a key that is not current. Values encrypted with it can no
+// longer be decrypted with this keyring.
+func (k *StaticKeyring) Remove(id string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if id != k.current {
+		delete(k.keys, id)
+	}
+}
+
+// Current returns the ID and the key that encrypt new values
+func (k *StaticKeyring) Current() (string, []byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[k.current]
+	if !ok {
+		return "", nil, errors.ErrEncryptionKeyNotFound
+	}
+	return k.current, key, nil
+}
+
+// Key returns the key with the given ID
+func (k *StaticKeyring) Key(id string) ([]byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[id]
+	if !ok {
+		return nil, errors.ErrEncryptionKeyNotFound
+	}
+	return key, nil
+}
+
+// validKeyID reports whether id can be written in an encrypted value
+func validKeyID(id string) bool {
+	if id == "" || len(id) > maxKeyIDSize {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if !isKeyIDChar(id[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isKeyIDChar reports whether c may appear in a key ID
+func isKeyIDChar(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '.' || c == '_' || c == '-'
+}
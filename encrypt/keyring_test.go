@@ -0,0 +1,73 @@
+package encrypt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Lunar-Chipter/mire/errors"
+)
+
+// TestStaticKeyring tests key validation, the current key and rotation
+func TestStaticKeyring(t *testing.T) {
+	k := NewStaticKeyring()
+	if _, _, err := k.Current(); err != errors.ErrEncryptionKeyNotFound {
+		t.Errorf("Expected ErrEncryptionKeyNotFound for an empty keyring, got %v", err)
+	}
+	if err := k.Add("k1", make([]byte, 20)); err != errors.ErrInvalidEncryptionKey {
+		t.Errorf("Expected ErrInvalidEncryptionKey, got %v", err)
+	}
+	if err := k.Add("bad id", make([]byte, 16)); err != errors.ErrInvalidKeyID {
+		t.Errorf("Expected ErrInvalidKeyID, got %v", err)
+	}
+
+	k1 := bytes.Repeat([]byte{1}, 32)
+	k2 := bytes.Repeat([]byte{2}, 16)
+	if err := k.Add("k1", k1); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	k.Add("k2", k2)
+	if id, key, _ := k.Current(); id != "k1" || !bytes.Equal(key, k1) {
+		t.Errorf("Expected the first key to be current, got %s", id)
+	}
+	if err := k.SetCurrent("k3"); err != errors.ErrEncryptionKeyNotFound {
+		t.Errorf("Expected ErrEncryptionKeyNotFound, got %v", err)
+	}
+	k.SetCurrent("k2")
+	k.Remove("k2")
+	if id, _, _ := k.Current(); id != "k2" {
+		t.Errorf("Expected the current key to be kept, got %s", id)
+	}
+	k.Remove("k1")
+	if _, err := k.Key("k1"); err != errors.ErrEncryptionKeyNotFound {
+		t.Errorf("Expected removed key to be gone, got %v", err)
+	}
+}
+
+// TestReadKeyring tests reading a key file
+func TestReadKeyring(t *testing.T) {
+	file := `# Rotated keys, newest last
+k1 AQEBAQEBAQEBAQEBAQEBAQ==
+
+k2 AgICAgICAgICAgICAgICAg==
+`
+	k, err := ReadKeyring(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("ReadKeyring failed: %v", err)
+	}
+	if id, key, _ := k.Current(); id != "k2" || !bytes.Equal(key, bytes.Repeat([]byte{2}, 16)) {
+		t.Errorf("Expected the last key to be current, got %s", id)
+	}
+	if _, err := k.Key("k1"); err != nil {
+		t.Errorf("Expected previous key to be kept, got %v", err)
+	}
+
+	for _, file := range []string{"k1", "k1 not-base64", "k1 AQ== extra"} {
+		if _, err := ReadKeyring(strings.NewReader(file)); err != errors.ErrInvalidKeyFile {
+			t.Errorf("Expected ErrInvalidKeyFile for %q, got %v", file, err)
+		}
+	}
+	if _, err := ReadKeyring(strings.NewReader("k1 AQ==")); err != errors.ErrInvalidEncryptionKey {
+		t.Errorf("Expected ErrInvalidEncryptionKey, got %v", err)
+	}
+}
@@ -56,3 +56,21 @@ func (e *customError) Error() string {
 }
 
 var ErrAsyncBufferFull = &customError{msg: "async log channel full"}
+
+var ErrInvalidOverflowPolicy = &customError{msg: "invalid overflow policy"}
+
+var ErrSpillFull = &customError{msg: "spill queue full"}
+
+var ErrRedactKeyRequired = &customError{msg: "redaction strategy Pseudonymize requires an HMAC key"}
+
+var ErrKeyringRequired = &customError{msg: "field encryption requires a keyring"}
+
+var ErrEncryptionKeyNotFound = &customError{msg: "encryption key not found"}
+
+var ErrInvalidEncryptionKey = &customError{msg: "encryption key must be 16, 24 or 32 bytes"}
+
+var ErrInvalidKeyID = &customError{msg: "encryption key ID must be 1 to 64 letters, digits, '.', '_' or '-'"}
+
+var ErrInvalidCiphertext = &customError{msg: "invalid encrypted value"}
+
+var ErrInvalidKeyFile = &customError{msg: "invalid key file line, expected an ID and a base64 key"}
@@ -12,16 +12,21 @@ type CSVFormatter struct {
 	IncludeHeader     bool                                // Include header row in output
 	FieldOrder        []string                            // Order of fields in CSV
 	TimestampFormat   string                              // Custom timestamp format
-	SensitiveFields   []string                            // List of sensitive field names to mask
-	MaskSensitiveData bool                                // Whether to mask sensitive data
-	MaskValue   string                              // String value to use for masking
 	FieldTransformers map[string]func(interface{}) string // Functions to transform field values
+	Sanitize          bool                                // Escape line breaks, control characters, ANSI escape sequences and invalid UTF-8 in values
+
+	// Deprecated: use logger.LoggerConfig.FieldPolicy.
+	SensitiveFields []string // List of sensitive field names to mask
+	// Deprecated: use logger.LoggerConfig.FieldPolicy.
+	MaskSensitiveData bool // Whether to mask sensitive data
+	// Deprecated: use logger.LoggerConfig.FieldPolicy.
+	MaskValue string // String value to use for masking
 }
 
 // NewCSVFormatter creates a new CSVFormatter
 func NewCSV() *CSVFormatter {
 	return &CSVFormatter{
-		MaskValue:       "[MASKED]",
+		MaskValue:         "[MASKED]",
 		FieldTransformers: make(map[string]func(interface{}) string),
 	}
 }
@@ -66,6 +71,10 @@ func (f *CSVFormatter) Format(buf *bytes.Buffer, entry *core.LogEntry) error {
 }
 
 func (f *CSVFormatter) writeCSVValue(buf *bytes.Buffer, value string) {
+	if f.Sanitize {
+		f.writeCSVValueBytes(buf, core.StringToBytes(value))
+		return
+	}
 	needsEscaping := false
 	for i := 0; i < len(value); i++ {
 		b := value[i]
@@ -92,6 +101,12 @@ func (f *CSVFormatter) writeCSVValue(buf *bytes.Buffer, value string) {
 }
 
 func (f *CSVFormatter) writeCSVValueBytes(buf *bytes.Buffer, value []byte) {
+	if f.Sanitize && needsSanitizing(value) {
+		clean := util.GetBuffer()
+		defer util.PutBuffer(clean)
+		writeSanitized(clean, value, nil)
+		value = clean.Bytes()
+	}
 	needsEscaping := false
 	for _, b := range value {
 		if b == '"' || b == ',' || b == '\n' || b == '\r' {
@@ -136,10 +151,20 @@ func (f *CSVFormatter) formatCSVField(buf *bytes.Buffer, field string, entry *co
 		buf.WriteByte('"')
 	case "goroutine_id":
 		f.writeCSVValueBytes(buf, entry.GoroutineID)
+	case "hostname":
+		f.writeCSVValueBytes(buf, entry.Hostname)
+	case "application":
+		f.writeCSVValueBytes(buf, entry.Application)
+	case "version":
+		f.writeCSVValueBytes(buf, entry.Version)
+	case "environment":
+		f.writeCSVValueBytes(buf, entry.Environment)
 	case "trace_id":
 		f.writeCSVValueBytes(buf, entry.TraceID)
 	case "span_id":
 		f.writeCSVValueBytes(buf, entry.SpanID)
+	case "trace_flags":
+		f.writeCSVValueBytes(buf, entry.TraceFlags)
 	case "user_id":
 		f.writeCSVValueBytes(buf, entry.UserID)
 	case "request_id":
@@ -162,7 +187,7 @@ func (f *CSVFormatter) formatCSVField(buf *bytes.Buffer, field string, entry *co
 		}
 	case "error":
 		if entry.Error != nil {
-			if appender, ok := entry.Error.(core.ErrAppend); ok {
+			if appender, ok := entry.Error.(core.ErrAppend); ok && !f.Sanitize {
 				buf.WriteByte('"')
 				appender.AppendError(buf)
 				buf.WriteByte('"')
@@ -173,6 +198,48 @@ func (f *CSVFormatter) formatCSVField(buf *bytes.Buffer, field string, entry *co
 			buf.WriteByte('"')
 			buf.WriteByte('"')
 		}
+	case "error_type":
+		if entry.Error != nil {
+			f.writeCSVValue(buf, core.ErrorTypeName(entry.Error))
+		}
+	case "error_chain":
+		if entry.Error != nil {
+			chain := util.GetBuffer()
+			for i, cause := range core.ErrorChain(entry.Error) {
+				if i > 0 {
+					chain.WriteString(" | ")
+				}
+				core.WriteError(chain, cause)
+			}
+			f.writeCSVValueBytes(buf, chain.Bytes())
+			util.PutBuffer(chain)
+		}
+	case "duration":
+		if entry.Duration > 0 {
+			f.writeCSVValue(buf, entry.Duration.String())
+		}
+	case "tags":
+		value := util.GetBuffer()
+		for i, tag := range entry.Tags {
+			if i > 0 {
+				value.WriteByte(',')
+			}
+			value.Write(tag)
+		}
+		f.writeCSVValueBytes(buf, value.Bytes())
+		util.PutBuffer(value)
+	case "custom_metrics":
+		value := util.GetBuffer()
+		for i, name := range sortedMetricNames(entry.CustomMetrics) {
+			if i > 0 {
+				value.WriteByte(' ')
+			}
+			value.WriteString(name)
+			value.WriteByte('=')
+			writeMetricValue(value, entry.CustomMetrics[name])
+		}
+		f.writeCSVValueBytes(buf, value.Bytes())
+		util.PutBuffer(value)
 	default:
 		if val, exists := entry.Fields[field]; exists {
 			if f.MaskSensitiveData && f.isSensitiveField(field) {
@@ -180,7 +247,13 @@ func (f *CSVFormatter) formatCSVField(buf *bytes.Buffer, field string, entry *co
 				return nil
 			}
 
-			if transformer, exists := f.FieldTransformers[field]; exists {
+			if f.Sanitize {
+				if transformer, exists := f.FieldTransformers[field]; exists {
+					f.writeCSVQuoted(buf, core.StringToBytes(transformer(val)))
+				} else {
+					f.writeCSVQuoted(buf, val)
+				}
+			} else if transformer, exists := f.FieldTransformers[field]; exists {
 				transformed := transformer(val)
 				buf.WriteByte('"')
 				util.FormatValue(buf, transformed, 0)
@@ -190,6 +263,20 @@ func (f *CSVFormatter) formatCSVField(buf *bytes.Buffer, field string, entry *co
 				util.FormatValue(buf, val, 0)
 				buf.WriteByte('"')
 			}
+		} else if typed := findTypedField(entry.TypedFields, field); typed != nil {
+			if f.MaskSensitiveData && f.isSensitiveField(field) {
+				f.writeCSVValue(buf, f.MaskValue)
+				return nil
+			}
+
+			tmp := util.GetSmallBuf()
+			value := typed.AppendText(tmp[:0])
+			f.writeCSVValueBytes(buf, value)
+			util.PutSmallBuf(value)
+		} else if metric, exists := entry.CustomMetrics[field]; exists {
+			buf.WriteByte('"')
+			writeMetricValue(buf, metric)
+			buf.WriteByte('"')
 		} else {
 			buf.WriteByte('"')
 			buf.WriteByte('"')
@@ -198,6 +285,31 @@ func (f *CSVFormatter) formatCSVField(buf *bytes.Buffer, field string, entry *co
 	return nil
 }
 
+// writeCSVQuoted writes a sanitized value in double quotes, doubling the quotes it contains
+func (f *CSVFormatter) writeCSVQuoted(buf *bytes.Buffer, value []byte) {
+	clean := util.GetBuffer()
+	writeSanitized(clean, value, nil)
+	buf.WriteByte('"')
+	for _, b := range clean.Bytes() {
+		if b == '"' {
+			buf.WriteByte('"')
+		}
+		buf.WriteByte(b)
+	}
+	buf.WriteByte('"')
+	util.PutBuffer(clean)
+}
+
+// findTypedField returns the last typed field with the given key, or nil
+func findTypedField(fields []core.Field, key string) *core.Field {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].Key == key {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
 func (f *CSVFormatter) isSensitiveField(field string) bool {
 	for _, sensitiveField := range f.SensitiveFields {
 		if field == sensitiveField {
@@ -2,6 +2,7 @@ package formatter
 
 import (
 	"bytes"
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -522,3 +523,94 @@ func TestCSVFormatterWithNonexistentField(t *testing.T) {
 		t.Error("CSVFormatter.Format with nonexistent field produced empty output")
 	}
 }
+
+// TestCSVFormatterTypedFields tests that typed fields can be selected in FieldOrder
+func TestCSVFormatterTypedFields(t *testing.T) {
+	cf := NewCSV()
+	cf.FieldOrder = []string{"message", "n", "name"}
+
+	entry := core.GetEntryFromPool()
+	defer core.PutEntryToPool(entry)
+
+	entry.Message = []byte("typed")
+	entry.TypedFields = []core.Field{
+		{Key: "n", Type: core.Int64Field, Int: 5},
+		{Key: "name", Type: core.StringField, Str: "a,b"},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := cf.Format(buf, entry); err != nil {
+		t.Fatalf("CSVFormatter.Format returned error: %v", err)
+	}
+
+	if got, want := buf.String(), "typed,5,\"a,b\"\n"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+// TestCSVFormatterMetadata tests that static metadata can be selected in FieldOrder
+func TestCSVFormatterMetadata(t *testing.T) {
+	cf := NewCSV()
+	cf.FieldOrder = []string{"hostname", "application", "version", "environment", "goroutine_id"}
+
+	entry := core.GetEntryFromPool()
+	defer core.PutEntryToPool(entry)
+
+	entry.Hostname = []byte("web-1")
+	entry.Application = []byte("billing")
+	entry.Version = []byte("1.2.3")
+	entry.Environment = []byte("prod")
+	entry.GoroutineID = []byte("42")
+
+	buf := &bytes.Buffer{}
+	if err := cf.Format(buf, entry); err != nil {
+		t.Fatalf("CSVFormatter.Format returned error: %v", err)
+	}
+
+	if got, want := buf.String(), "web-1,billing,1.2.3,prod,42\n"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+// TestCSVFormatterErrorColumns tests the error_type and error_chain columns
+func TestCSVFormatterErrorColumns(t *testing.T) {
+	cf := NewCSV()
+	cf.FieldOrder = []string{"error", "error_type", "error_chain"}
+
+	entry := core.GetEntryFromPool()
+	defer core.PutEntryToPool(entry)
+
+	entry.Error = errors.Join(errors.New("a"), errors.New("b"))
+
+	buf := &bytes.Buffer{}
+	if err := cf.Format(buf, entry); err != nil {
+		t.Fatalf("CSVFormatter.Format returned error: %v", err)
+	}
+
+	if got, want := buf.String(), "\"a\nb\",*errors.joinError,\"a\nb | a | b\"\n"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+// TestCSVFormatterTagsAndMetrics tests the tags, custom metric and duration columns
+func TestCSVFormatterTagsAndMetrics(t *testing.T) {
+	cf := NewCSV()
+	cf.FieldOrder = []string{"tags", "custom_metrics", "latency_ms", "duration"}
+
+	entry := core.GetEntryFromPool()
+	defer core.PutEntryToPool(entry)
+
+	entry.Tags = append(entry.Tags, []byte("billing"), []byte("migration"))
+	entry.CustomMetrics["latency_ms"] = 42
+	entry.CustomMetrics["amount"] = 12.5
+	entry.Duration = 1500 * time.Microsecond
+
+	buf := &bytes.Buffer{}
+	if err := cf.Format(buf, entry); err != nil {
+		t.Fatalf("CSVFormatter.Format returned error: %v", err)
+	}
+
+	if got, want := buf.String(), "\"billing,migration\",amount=12.5 latency_ms=42,\"42\",1.5ms\n"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
@@ -2,7 +2,11 @@ package formatter
 
 import (
 	"bytes"
+	"sort"
+	"strconv"
+
 	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/util"
 )
 
 // Formatter interface defines how log entries are formatted
@@ -21,3 +25,75 @@ type AllFormatter interface {
 	// GetOptions returns the current formatter options
 	GetOptions() interface{}
 }
+
+// sortedMetricNames returns the metric names in sorted order so that
+// every formatter renders custom metrics deterministically
+func sortedMetricNames(metrics map[string]float64) []string {
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeMetricValue writes a custom metric value in its shortest exact form
+func writeMetricValue(buf *bytes.Buffer, v float64) {
+	tmp := util.GetSmallBuf()
+	tmp = strconv.AppendFloat(tmp, v, 'f', -1, 64)
+	buf.Write(tmp)
+	util.PutSmallBuf(tmp)
+}
+
+// maxOrderedFields is the number of fields ordered without allocating
+const maxOrderedFields = 32
+
+// fieldRef refers to a field of an entry: a key of Fields when typed is
+// negative, otherwise the index of a typed field
+type fieldRef struct {
+	key   string
+	typed int
+}
+
+// orderFields appends the map and typed fields of an entry to dst in output
+// order. Map fields come in insertion order (core.LogEntry.FieldKeys), then
+// typed fields, which the logger adds from the call site. With sorted set all
+// fields are sorted by key instead. Fields whose keys are listed in custom are
+// then moved to the front in the listed order.
+func orderFields(dst []fieldRef, entry *core.LogEntry, custom []string, sorted bool) []fieldRef {
+	start := len(dst)
+	var stack [maxOrderedFields]string
+	keys := stack[:0]
+	if len(entry.Fields) > maxOrderedFields {
+		keys = make([]string, 0, len(entry.Fields))
+	}
+	for _, k := range entry.OrderedFieldKeys(keys) {
+		dst = append(dst, fieldRef{key: k, typed: -1})
+	}
+	for i := range entry.TypedFields {
+		dst = append(dst, fieldRef{key: entry.TypedFields[i].Key, typed: i})
+	}
+	refs := dst[start:]
+
+	if sorted {
+		// Insertion sort: stable, and sort.Slice would move the fields to the heap
+		for i := 1; i < len(refs); i++ {
+			for j := i; j > 0 && refs[j].key < refs[j-1].key; j-- {
+				refs[j], refs[j-1] = refs[j-1], refs[j]
+			}
+		}
+	}
+
+	next := 0
+	for _, key := range custom {
+		for i := next; i < len(refs); i++ {
+			if refs[i].key == key {
+				ref := refs[i]
+				copy(refs[next+1:i+1], refs[next:i])
+				refs[next] = ref
+				next++
+			}
+		}
+	}
+	return dst
+}
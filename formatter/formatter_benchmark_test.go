@@ -22,7 +22,7 @@ func BenchmarkCSVFormatter(b *testing.B) {
 
 	for i := 0; i < b.N; i++ {
 		buf.Reset()
-		_, _ = formatter.Format(&buf, entry)
+		_ = formatter.Format(&buf, entry)
 	}
 }
 
@@ -39,7 +39,7 @@ func BenchmarkJSONFormatter(b *testing.B) {
 
 	for i := 0; i < b.N; i++ {
 		buf.Reset()
-		_, _ = formatter.Format(&buf, entry)
+		_ = formatter.Format(&buf, entry)
 	}
 }
 
@@ -56,7 +56,7 @@ func BenchmarkJSONFormatterPretty(b *testing.B) {
 
 	for i := 0; i < b.N; i++ {
 		buf.Reset()
-		_, _ = formatter.Format(&buf, entry)
+		_ = formatter.Format(&buf, entry)
 	}
 }
 
@@ -79,6 +79,24 @@ func BenchmarkTextFormatter(b *testing.B) {
 	}
 }
 
+// BenchmarkLogfmtFormatter benchmarks the logfmt formatter
+func BenchmarkLogfmtFormatter(b *testing.B) {
+	formatter := NewLogfmt()
+	formatter.ShowTrace = true
+
+	entry := createBenchmarkEntry()
+	defer core.PutEntryToPool(entry)
+
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		_ = formatter.Format(&buf, entry)
+	}
+}
+
 // BenchmarkTextFormatterWithColors benchmarks the Text formatter with colors
 func BenchmarkTextFormatterWithColors(b *testing.B) {
 	formatter := NewText()
@@ -138,7 +156,7 @@ func BenchmarkAllFormatters(b *testing.B) {
 
 			for i := 0; i < b.N; i++ {
 				buf.Reset()
-				_, _ = tt.formatter.Format(&buf, entry)
+				_ = tt.formatter.Format(&buf, entry)
 			}
 		})
 	}
@@ -174,7 +192,7 @@ func BenchmarkFormatterWithFields(b *testing.B) {
 
 			for i := 0; i < b.N; i++ {
 				buf.Reset()
-				_, _ = tt.formatter.Format(&buf, entry)
+				_ = tt.formatter.Format(&buf, entry)
 			}
 		})
 	}
@@ -228,7 +246,7 @@ func BenchmarkFormatterWithSensitiveData(b *testing.B) {
 
 			for i := 0; i < b.N; i++ {
 				buf.Reset()
-				_, _ = tt.formatter.Format(&buf, entry)
+				_ = tt.formatter.Format(&buf, entry)
 			}
 		})
 	}
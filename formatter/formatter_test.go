@@ -0,0 +1,90 @@
+package formatter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Lunar-Chipter/mire/core"
+)
+
+// orderedEntry returns an entry with map fields set in insertion order and typed fields
+func orderedEntry() *core.LogEntry {
+	entry := &core.LogEntry{Level: core.INFO, Message: []byte("ordered")}
+	entry.SetField("service", []byte("api"))
+	entry.SetField("request", []byte("r1"))
+	entry.SetField("attempt", []byte("2"))
+	entry.TypedFields = []core.Field{{Key: "bytes", Type: core.Int64Field, Int: 512}}
+	return entry
+}
+
+// fieldKeys returns the keys of the ordered fields
+func fieldKeys(refs []fieldRef) string {
+	keys := make([]string, len(refs))
+	for i, ref := range refs {
+		keys[i] = ref.key
+	}
+	return strings.Join(keys, ",")
+}
+
+// TestOrderFields tests insertion order, sorted mode and custom order
+func TestOrderFields(t *testing.T) {
+	entry := orderedEntry()
+
+	tests := []struct {
+		name   string
+		custom []string
+		sorted bool
+		want   string
+	}{
+		{"insertion", nil, false, "service,request,attempt,bytes"},
+		{"sorted", nil, true, "attempt,bytes,request,service"},
+		{"custom", []string{"bytes", "missing", "request"}, false, "bytes,request,service,attempt"},
+		{"custom and sorted", []string{"service"}, true, "service,attempt,bytes,request"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refs := orderFields(nil, entry, tt.custom, tt.sorted)
+			if got := fieldKeys(refs); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	// Fields written to the map directly are sorted
+	direct := &core.LogEntry{Fields: map[string][]byte{"b": nil, "c": nil, "a": nil}}
+	if got := fieldKeys(orderFields(nil, direct, nil, false)); got != "a,b,c" {
+		t.Errorf("Expected a,b,c, got %s", got)
+	}
+}
+
+// TestFormattersFieldOrder tests that every formatter writes fields in the same order
+func TestFormattersFieldOrder(t *testing.T) {
+	custom := []string{"attempt"}
+	tests := []struct {
+		name      string
+		formatter Formatter
+		want      string
+	}{
+		{"text", &TextFormatter{CustomFieldOrder: custom}, "{attempt=2 service=api request=r1 bytes=512}"},
+		{"json", &JSONFormatter{CustomFieldOrder: custom}, `{"attempt":"2","service":"api","request":"r1","bytes":512}`},
+		{"logfmt", &LogfmtFormatter{CustomFieldOrder: custom}, "attempt=2 service=api request=r1 bytes=512"},
+		{"syslog", &SyslogFormatter{CustomFieldOrder: custom}, `attempt="2" service="api" request="r1" bytes="512"`},
+		{"text sorted", &TextFormatter{SortFields: true}, "{attempt=2 bytes=512 request=r1 service=api}"},
+		{"json sorted", &JSONFormatter{SortFields: true}, `{"attempt":"2","bytes":512,"request":"r1","service":"api"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			for i := 0; i < 20; i++ {
+				buf.Reset()
+				if err := tt.formatter.Format(&buf, orderedEntry()); err != nil {
+					t.Fatalf("Format failed: %v", err)
+				}
+				if !strings.Contains(buf.String(), tt.want) {
+					t.Fatalf("Expected %s in %q", tt.want, buf.String())
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package formatter
 
 import (
 	"bytes"
+	"math"
 	"strconv"
 
 	"github.com/Lunar-Chipter/mire/core"
@@ -15,8 +16,15 @@ var (
 	jsonMessageKey   = []byte("\"message\":\"")
 	jsonPidKey       = []byte(",\"pid\":")
 	jsonCallerKey    = []byte(",\"caller\":\"")
+	jsonDurationKey  = []byte(",\"duration\":")
+	jsonGoroutineKey = []byte(",\"goroutine_id\":\"")
+	jsonHostKey      = []byte(",\"hostname\":\"")
+	jsonAppKey       = []byte(",\"application\":\"")
+	jsonVersionKey   = []byte(",\"version\":\"")
+	jsonEnvKey       = []byte(",\"environment\":\"")
 	jsonTraceKey     = []byte(",\"trace_id\":\"")
 	jsonSpanKey      = []byte(",\"span_id\":\"")
+	jsonFlagsKey     = []byte(",\"trace_flags\":\"")
 	jsonUserKey      = []byte(",\"user_id\":\"")
 	jsonFieldsKey    = []byte(",\"fields\":")
 	jsonStackKey     = []byte(",\"stack_trace\":\"")
@@ -26,23 +34,33 @@ var (
 	jsonBraceOpen    = []byte("{")
 )
 
-// JSONFormatter formats log entries in JSON format
+// JSONFormatter formats log entries in JSON format. The version and
+// environment of an entry are written whenever it has them.
 type JSONFormatter struct {
 	PrettyPrint       bool                                     // Enable pretty-printed JSON
 	TimestampFormat   string                                   // Custom timestamp format
 	ShowCaller        bool                                     // Show caller information
 	ShowGoroutine     bool                                     // Show goroutine ID
+	ShowHostname      bool                                     // Show hostname
+	ShowApplication   bool                                     // Show application name
 	ShowPID           bool                                     // Show process ID
 	ShowTrace         bool                                     // Show trace information
 	IncludeStackTrace bool                                     // Enable stack trace for errors
 	ShowDuration      bool                                     // Show operation duration
 	FieldKeyMap       map[string]string                        // Map for renaming fields
 	DisableHTMLEscape bool                                     // Disable HTML escaping in JSON
-	SensitiveFields   []string                                 // List of sensitive field names
-	MaskSensitiveData bool                                     // Whether to mask sensitive data
-	MaskValue         string                                   // String value to use for masking
-	MaskStringBytes   []byte                                   // Byte slice for masking (zero-allocation)
 	FieldTransformers map[string]func(interface{}) interface{} // Functions to transform field values
+	CustomFieldOrder  []string                                 // Keys of fields written first, in this order
+	SortFields        bool                                     // Sort fields by key instead of keeping insertion order
+
+	// Deprecated: use logger.LoggerConfig.FieldPolicy.
+	SensitiveFields []string // List of sensitive field names
+	// Deprecated: use logger.LoggerConfig.FieldPolicy.
+	MaskSensitiveData bool // Whether to mask sensitive data
+	// Deprecated: use logger.LoggerConfig.FieldPolicy.
+	MaskValue string // String value to use for masking
+	// Deprecated: use logger.LoggerConfig.FieldPolicy.
+	MaskStringBytes []byte // Byte slice for masking (zero-allocation)
 }
 
 // NewJSONFormatter creates a new JSONFormatter
@@ -92,6 +110,21 @@ func (f *JSONFormatter) formatManually(buf *bytes.Buffer, entry *core.LogEntry)
 		util.WriteInt(buf, int64(entry.PID))
 	}
 
+	if f.ShowGoroutine && len(entry.GoroutineID) > 0 {
+		f.writeMetaString(buf, jsonGoroutineKey, entry.GoroutineID)
+	}
+
+	// Add static metadata set by the logger
+	if f.ShowHostname {
+		f.writeMetaString(buf, jsonHostKey, entry.Hostname)
+	}
+	if f.ShowApplication {
+		f.writeMetaString(buf, jsonAppKey, entry.Application)
+	}
+	// Version and environment have no Show* option: the logger only sets them when configured
+	f.writeMetaString(buf, jsonVersionKey, entry.Version)
+	f.writeMetaString(buf, jsonEnvKey, entry.Environment)
+
 	// Add caller info if needed
 	if f.ShowCaller && entry.Caller != nil {
 		buf.Write(jsonCallerKey)
@@ -101,12 +134,34 @@ func (f *JSONFormatter) formatManually(buf *bytes.Buffer, entry *core.LogEntry)
 		buf.WriteByte('"')
 	}
 
+	// Add operation duration in nanoseconds, like typed Duration fields
+	if f.ShowDuration && entry.Duration > 0 {
+		buf.Write(jsonDurationKey)
+		util.WriteInt(buf, int64(entry.Duration))
+	}
+
+	// Add error details if present
+	if entry.Error != nil {
+		f.formatError(buf, entry.Error, func(key string) {
+			buf.WriteString(",\"")
+			buf.WriteString(key)
+			buf.WriteString("\":")
+		})
+	}
+
 	// Add fields if present
-	if len(entry.Fields) > 0 {
+	if len(entry.Fields) > 0 || len(entry.TypedFields) > 0 {
 		buf.Write(jsonFieldsKey)
-		f.formatFields(buf, entry.Fields)
+		f.formatFields(buf, entry)
 	}
 
+	// Add tags and custom metrics if present
+	f.formatTagsAndMetrics(buf, entry, func(key string) {
+		buf.WriteString(",\"")
+		buf.WriteString(key)
+		buf.WriteString("\":")
+	})
+
 	// Add trace info if needed - organize in a way that reduces branching
 	if f.ShowTrace {
 		if entry.TraceID != nil {
@@ -119,6 +174,11 @@ func (f *JSONFormatter) formatManually(buf *bytes.Buffer, entry *core.LogEntry)
 			buf.Write(entry.SpanID)
 			buf.Write(jsonQuote)
 		}
+		if entry.TraceFlags != nil {
+			buf.Write(jsonFlagsKey)
+			buf.Write(entry.TraceFlags)
+			buf.Write(jsonQuote)
+		}
 		if entry.UserID != nil {
 			buf.Write(jsonUserKey)
 			buf.Write(entry.UserID)
@@ -138,6 +198,90 @@ func (f *JSONFormatter) formatManually(buf *bytes.Buffer, entry *core.LogEntry)
 	return nil
 }
 
+// formatError writes the error message, its type and, for wrapped or joined
+// errors, the messages of the whole cause chain. member writes the separator and key.
+func (f *JSONFormatter) formatError(buf *bytes.Buffer, err error, member func(key string)) {
+	msg := util.GetBuffer()
+	defer util.PutBuffer(msg)
+
+	member("error")
+	core.WriteError(msg, err)
+	buf.WriteByte('"')
+	escapeJSON(buf, msg.Bytes())
+	buf.WriteByte('"')
+
+	member("error_type")
+	buf.WriteByte('"')
+	escapeJSON(buf, core.StringToBytes(core.ErrorTypeName(err)))
+	buf.WriteByte('"')
+
+	chain := core.ErrorChain(err)
+	if len(chain) < 2 {
+		return
+	}
+	member("error_chain")
+	buf.WriteByte('[')
+	for i, cause := range chain {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		msg.Reset()
+		core.WriteError(msg, cause)
+		buf.WriteByte('"')
+		escapeJSON(buf, msg.Bytes())
+		buf.WriteByte('"')
+	}
+	buf.WriteByte(']')
+}
+
+// formatTagsAndMetrics writes the entry tags as a string array and the custom
+// metrics as an object of numbers. Non-finite metric values are written as null.
+func (f *JSONFormatter) formatTagsAndMetrics(buf *bytes.Buffer, entry *core.LogEntry, member func(key string)) {
+	if len(entry.Tags) > 0 {
+		member("tags")
+		buf.WriteByte('[')
+		for i, tag := range entry.Tags {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('"')
+			escapeJSON(buf, tag)
+			buf.WriteByte('"')
+		}
+		buf.WriteByte(']')
+	}
+
+	if len(entry.CustomMetrics) > 0 {
+		member("custom_metrics")
+		buf.WriteByte('{')
+		for i, name := range sortedMetricNames(entry.CustomMetrics) {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('"')
+			escapeJSON(buf, core.StringToBytes(name))
+			buf.WriteString("\":")
+			v := entry.CustomMetrics[name]
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				buf.WriteString("null")
+				continue
+			}
+			writeMetricValue(buf, v)
+		}
+		buf.WriteByte('}')
+	}
+}
+
+// writeMetaString writes an escaped string member if value is set
+func (f *JSONFormatter) writeMetaString(buf *bytes.Buffer, key []byte, value []byte) {
+	if value == nil {
+		return
+	}
+	buf.Write(key)
+	escapeJSON(buf, value)
+	buf.WriteByte('"')
+}
+
 // formatWithStandardEncoder handles pretty printing
 func (f *JSONFormatter) formatWithStandardEncoder(buf *bytes.Buffer, entry *core.LogEntry) error {
 	if f.PrettyPrint {
@@ -208,6 +352,37 @@ func (f *JSONFormatter) formatManuallyWithIndent(buf *bytes.Buffer, entry *core.
 		util.WriteInt(buf, int64(entry.PID))
 	}
 
+	if f.ShowGoroutine && len(entry.GoroutineID) > 0 {
+		buf.WriteString(",\n  ")
+		indent(1)
+		buf.WriteString("\"goroutine_id\": \"")
+		escapeJSON(buf, entry.GoroutineID)
+		buf.WriteByte('"')
+	}
+
+	// Add static metadata set by the logger
+	for _, meta := range [...]struct {
+		key   string
+		value []byte
+		show  bool
+	}{
+		{"hostname", entry.Hostname, f.ShowHostname},
+		{"application", entry.Application, f.ShowApplication},
+		{"version", entry.Version, true},
+		{"environment", entry.Environment, true},
+	} {
+		if !meta.show || meta.value == nil {
+			continue
+		}
+		buf.WriteString(",\n  ")
+		indent(1)
+		buf.WriteByte('"')
+		buf.WriteString(meta.key)
+		buf.WriteString("\": \"")
+		escapeJSON(buf, meta.value)
+		buf.WriteByte('"')
+	}
+
 	// Add caller info if needed
 	if f.ShowCaller && entry.Caller != nil {
 		buf.WriteString(",\n  ")
@@ -219,15 +394,43 @@ func (f *JSONFormatter) formatManuallyWithIndent(buf *bytes.Buffer, entry *core.
 		buf.WriteByte('"')
 	}
 
+	// Add operation duration in nanoseconds
+	if f.ShowDuration && entry.Duration > 0 {
+		buf.WriteString(",\n  ")
+		indent(1)
+		buf.WriteString("\"duration\": ")
+		util.WriteInt(buf, int64(entry.Duration))
+	}
+
+	// Add error details if present
+	if entry.Error != nil {
+		f.formatError(buf, entry.Error, func(key string) {
+			buf.WriteString(",\n  ")
+			indent(1)
+			buf.WriteByte('"')
+			buf.WriteString(key)
+			buf.WriteString("\": ")
+		})
+	}
+
 	// Add fields if present
-	if len(entry.Fields) > 0 {
+	if len(entry.Fields) > 0 || len(entry.TypedFields) > 0 {
 		buf.WriteString(",\n  ")
 		indent(1)
 		buf.WriteString("\"fields\": ")
 		// For indented fields, we need to format them manually with indentation
-		f.formatFieldsIndented(buf, entry.Fields, 2)
+		f.formatFieldsIndented(buf, entry, 2)
 	}
 
+	// Add tags and custom metrics if present
+	f.formatTagsAndMetrics(buf, entry, func(key string) {
+		buf.WriteString(",\n  ")
+		indent(1)
+		buf.WriteByte('"')
+		buf.WriteString(key)
+		buf.WriteString("\": ")
+	})
+
 	// Add trace info if needed
 	if f.ShowTrace {
 		if entry.TraceID != nil {
@@ -244,6 +447,13 @@ func (f *JSONFormatter) formatManuallyWithIndent(buf *bytes.Buffer, entry *core.
 			buf.Write(entry.SpanID)
 			buf.WriteByte('"')
 		}
+		if entry.TraceFlags != nil {
+			buf.WriteString(",\n  ")
+			indent(1)
+			buf.WriteString("\"trace_flags\": \"")
+			buf.Write(entry.TraceFlags)
+			buf.WriteByte('"')
+		}
 		if entry.UserID != nil {
 			buf.WriteString(",\n  ")
 			indent(1)
@@ -442,30 +652,38 @@ func (f *JSONFormatter) transformValue(val interface{}, defaultVal string) strin
 	}
 }
 
-// formatFields formats fields map in JSON format
-func (f *JSONFormatter) formatFields(buf *bytes.Buffer, fields map[string][]byte) {
-	if len(fields) == 0 {
+// formatFields formats the map and typed fields of the entry in JSON format,
+// in insertion order or sorted by key with SortFields, after those in CustomFieldOrder
+func (f *JSONFormatter) formatFields(buf *bytes.Buffer, entry *core.LogEntry) {
+	if len(entry.Fields) == 0 && len(entry.TypedFields) == 0 {
 		return
 	}
 
 	buf.Write([]byte("{"))
-	first := true
 
-	for k, v := range fields {
-		if !first {
+	var stack [maxOrderedFields]fieldRef
+	for i, ref := range orderFields(stack[:0], entry, f.CustomFieldOrder, f.SortFields) {
+		if i > 0 {
 			buf.WriteByte(',')
 		}
-		first = false
+
+		if ref.typed >= 0 {
+			buf.WriteByte('"')
+			escapeJSON(buf, core.StringToBytes(ref.key))
+			buf.Write([]byte("\":"))
+			f.formatTypedValue(buf, &entry.TypedFields[ref.typed])
+			continue
+		}
 
 		buf.WriteByte('"')
-		buf.Write(core.StringToBytes(k))
+		buf.Write(core.StringToBytes(ref.key))
 		buf.Write([]byte("\":"))
 
 		buf.WriteByte('"')
-		if f.MaskSensitiveData && f.isSensitiveField(k) {
+		if f.MaskSensitiveData && f.isSensitiveField(ref.key) {
 			buf.Write(core.StringToBytes(f.MaskValue))
 		} else {
-			escapeJSON(buf, v)
+			escapeJSON(buf, entry.Fields[ref.key])
 		}
 		buf.WriteByte('"')
 	}
@@ -473,8 +691,34 @@ func (f *JSONFormatter) formatFields(buf *bytes.Buffer, fields map[string][]byte
 	buf.Write([]byte("}"))
 }
 
-// formatFieldsIndented formats a fields map in JSON format with indentation
-func (f *JSONFormatter) formatFieldsIndented(buf *bytes.Buffer, fields map[string][]byte, indentLevel int) {
+// formatTypedValue writes a typed field value; numbers, booleans and
+// Any values are written as JSON literals, everything else as a string
+func (f *JSONFormatter) formatTypedValue(buf *bytes.Buffer, field *core.Field) {
+	if f.MaskSensitiveData && f.isSensitiveField(field.Key) {
+		buf.WriteByte('"')
+		if f.MaskStringBytes != nil {
+			buf.Write(f.MaskStringBytes)
+		} else {
+			buf.WriteString(f.MaskValue)
+		}
+		buf.WriteByte('"')
+		return
+	}
+
+	tmp := util.GetSmallBuf()
+	value := field.AppendJSON(tmp[:0])
+	if field.IsJSONLiteral() {
+		buf.Write(value)
+	} else {
+		buf.WriteByte('"')
+		escapeJSON(buf, value)
+		buf.WriteByte('"')
+	}
+	util.PutSmallBuf(value)
+}
+
+// formatFieldsIndented formats the map and typed fields of the entry in JSON format with indentation
+func (f *JSONFormatter) formatFieldsIndented(buf *bytes.Buffer, entry *core.LogEntry, indentLevel int) {
 	indentBuf := util.GetBuffer()
 	defer util.PutBuffer(indentBuf)
 
@@ -494,40 +738,37 @@ func (f *JSONFormatter) formatFieldsIndented(buf *bytes.Buffer, fields map[strin
 
 	buf.WriteByte('{')
 
-	if len(fields) > 0 {
+	if len(entry.Fields) > 0 || len(entry.TypedFields) > 0 {
 		indentBuf.WriteString("  ")
 		indentBytes = indentBuf.Bytes()
 		newlineAndIndent()
 	}
 
-	// to
-	orderedKeys := make([]string, 0, len(fields))
-	for k := range fields {
-		orderedKeys = append(orderedKeys, k)
-	}
-
-	first := true
-	for _, k := range orderedKeys {
-		v := fields[k]
-		if !first {
+	var stack [maxOrderedFields]fieldRef
+	for i, ref := range orderFields(stack[:0], entry, f.CustomFieldOrder, f.SortFields) {
+		if i > 0 {
 			buf.WriteByte(',')
 		}
-		first = false
 
 		newlineAndIndent()
 
 		buf.WriteByte('"')
-		buf.Write(core.StringToBytes(k))
+		if ref.typed >= 0 {
+			escapeJSON(buf, core.StringToBytes(ref.key))
+			buf.Write([]byte("\": "))
+			f.formatTypedValue(buf, &entry.TypedFields[ref.typed])
+			continue
+		}
+		buf.Write(core.StringToBytes(ref.key))
 		buf.Write([]byte("\": "))
 
 		buf.WriteByte('"')
-		if f.MaskSensitiveData && f.isSensitiveField(k) {
+		if f.MaskSensitiveData && f.isSensitiveField(ref.key) {
 			buf.Write(f.MaskStringBytes)
 		} else {
-			escapeJSON(buf, v)
+			escapeJSON(buf, entry.Fields[ref.key])
 		}
 		buf.WriteByte('"')
-		first = false
 	}
 
 	if len(originalIndent) >= 2 {
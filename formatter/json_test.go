@@ -2,6 +2,11 @@ package formatter
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -485,7 +490,7 @@ func TestJSONFormatterFormatFields(t *testing.T) {
 		"field4": []byte("3.14"),
 	}
 
-	jf.formatFields(buf, fields)
+	jf.formatFields(buf, &core.LogEntry{Fields: fields})
 
 	output := buf.String()
 	if len(output) == 0 {
@@ -515,7 +520,7 @@ func TestJSONFormatterFormatFieldsIndented(t *testing.T) {
 	}
 
 	// Call formatFieldsIndented with indent level 1
-	jf.formatFieldsIndented(buf, fields, 1)
+	jf.formatFieldsIndented(buf, &core.LogEntry{Fields: fields}, 1)
 
 	output := buf.String()
 	if len(output) == 0 {
@@ -616,3 +621,228 @@ func TestJSONFormatterFieldKeyMapping(t *testing.T) {
 	// at
 	// to
 }
+
+// TestJSONFormatterTypedFields tests that typed fields are encoded natively
+func TestJSONFormatterTypedFields(t *testing.T) {
+	jf := NewJSON()
+
+	entry := core.GetEntryFromPool()
+	defer core.PutEntryToPool(entry)
+
+	entry.Timestamp = time.Now()
+	entry.Level = core.INFO
+	entry.Message = []byte("typed")
+	entry.TypedFields = []core.Field{
+		{Key: "n", Type: core.Int64Field, Int: 5},
+		{Key: "ok", Type: core.BoolField, Int: 1},
+		{Key: "name", Type: core.StringField, Str: `a"b`},
+		{Key: "err", Type: core.ErrorField},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := jf.Format(buf, entry); err != nil {
+		t.Fatalf("JSONFormatter.Format returned error: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{`"n":5`, `"ok":true`, `"name":"a\"b"`, `"err":null`} {
+		if !bytes.Contains(buf.Bytes(), []byte(want)) {
+			t.Errorf("Expected output to contain %s, got %s", want, output)
+		}
+	}
+}
+
+// TestJSONFormatterTypedFieldsMasked tests that sensitive typed fields are masked
+func TestJSONFormatterTypedFieldsMasked(t *testing.T) {
+	jf := NewJSON()
+	jf.SensitiveFields = []string{"password"}
+	jf.MaskSensitiveData = true
+
+	entry := core.GetEntryFromPool()
+	defer core.PutEntryToPool(entry)
+
+	entry.Timestamp = time.Now()
+	entry.Level = core.INFO
+	entry.Message = []byte("typed")
+	entry.TypedFields = []core.Field{{Key: "password", Type: core.StringField, Str: "secret"}}
+
+	buf := &bytes.Buffer{}
+	if err := jf.Format(buf, entry); err != nil {
+		t.Fatalf("JSONFormatter.Format returned error: %v", err)
+	}
+
+	if bytes.Contains(buf.Bytes(), []byte("secret")) {
+		t.Errorf("Expected password to be masked, got %s", buf.String())
+	}
+}
+
+// TestJSONFormatterMetadata tests that static metadata and goroutine IDs are emitted
+func TestJSONFormatterMetadata(t *testing.T) {
+	entry := core.GetEntryFromPool()
+	defer core.PutEntryToPool(entry)
+
+	entry.Timestamp = time.Now()
+	entry.Level = core.INFO
+	entry.Message = []byte("meta")
+	entry.Hostname = []byte("web-1")
+	entry.Application = []byte("billing")
+	entry.Version = []byte("1.2.3")
+	entry.Environment = []byte("prod")
+	entry.GoroutineID = []byte("42")
+
+	for _, pretty := range []bool{false, true} {
+		jf := NewJSON()
+		jf.PrettyPrint = pretty
+		jf.ShowGoroutine = true
+		jf.ShowHostname = true
+		jf.ShowApplication = true
+
+		buf := &bytes.Buffer{}
+		if err := jf.Format(buf, entry); err != nil {
+			t.Fatalf("JSONFormatter.Format returned error: %v", err)
+		}
+
+		var decoded map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, buf.String())
+		}
+		want := map[string]string{
+			"hostname":     "web-1",
+			"application":  "billing",
+			"version":      "1.2.3",
+			"environment":  "prod",
+			"goroutine_id": "42",
+		}
+		for k, v := range want {
+			if decoded[k] != v {
+				t.Errorf("pretty=%v: expected %s=%s, got %v", pretty, k, v, decoded[k])
+			}
+		}
+
+		// Hostname and application are only written when shown
+		jf.ShowHostname = false
+		jf.ShowApplication = false
+		buf.Reset()
+		if err := jf.Format(buf, entry); err != nil {
+			t.Fatalf("JSONFormatter.Format returned error: %v", err)
+		}
+		if strings.Contains(buf.String(), "web-1") || strings.Contains(buf.String(), "billing") {
+			t.Errorf("pretty=%v: expected no hostname or application, got %s", pretty, buf.String())
+		}
+	}
+}
+
+// TestJSONFormatterError tests the error, error_type and error_chain members
+func TestJSONFormatterError(t *testing.T) {
+	entry := core.GetEntryFromPool()
+	defer core.PutEntryToPool(entry)
+
+	entry.Timestamp = time.Now()
+	entry.Level = core.ERROR
+	entry.Message = []byte("failed")
+	entry.Error = fmt.Errorf("query \"users\": %w", errors.New("timeout"))
+
+	for _, pretty := range []bool{false, true} {
+		jf := NewJSON()
+		jf.PrettyPrint = pretty
+
+		buf := &bytes.Buffer{}
+		if err := jf.Format(buf, entry); err != nil {
+			t.Fatalf("JSONFormatter.Format returned error: %v", err)
+		}
+
+		var decoded struct {
+			Error      string   `json:"error"`
+			ErrorType  string   `json:"error_type"`
+			ErrorChain []string `json:"error_chain"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, buf.String())
+		}
+		if decoded.Error != `query "users": timeout` || decoded.ErrorType != "*fmt.wrapError" {
+			t.Errorf("pretty=%v: unexpected error members %+v", pretty, decoded)
+		}
+		if len(decoded.ErrorChain) != 2 || decoded.ErrorChain[1] != "timeout" {
+			t.Errorf("pretty=%v: unexpected error chain %v", pretty, decoded.ErrorChain)
+		}
+	}
+}
+
+// TestJSONFormatterTagsAndMetrics tests rendering tags and custom metrics
+func TestJSONFormatterTagsAndMetrics(t *testing.T) {
+	entry := core.GetEntryFromPool()
+	defer core.PutEntryToPool(entry)
+
+	entry.Timestamp = time.Now()
+	entry.Level = core.INFO
+	entry.Message = []byte("invoice sent")
+	entry.Tags = append(entry.Tags, []byte("billing"), []byte("migration"))
+	entry.CustomMetrics["amount"] = 12.5
+	entry.CustomMetrics["ratio"] = math.NaN()
+
+	for _, pretty := range []bool{false, true} {
+		jf := NewJSON()
+		jf.PrettyPrint = pretty
+
+		buf := &bytes.Buffer{}
+		if err := jf.Format(buf, entry); err != nil {
+			t.Fatalf("JSONFormatter.Format returned error: %v", err)
+		}
+
+		var decoded struct {
+			Tags          []string            `json:"tags"`
+			CustomMetrics map[string]*float64 `json:"custom_metrics"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, buf.String())
+		}
+		if len(decoded.Tags) != 2 || decoded.Tags[0] != "billing" || decoded.Tags[1] != "migration" {
+			t.Errorf("pretty=%v: unexpected tags %v", pretty, decoded.Tags)
+		}
+		if v := decoded.CustomMetrics["amount"]; v == nil || *v != 12.5 {
+			t.Errorf("pretty=%v: unexpected amount metric in %s", pretty, buf.String())
+		}
+		if v, ok := decoded.CustomMetrics["ratio"]; !ok || v != nil {
+			t.Errorf("pretty=%v: expected NaN metric as null in %s", pretty, buf.String())
+		}
+	}
+}
+
+// TestJSONFormatterDuration tests rendering the operation duration
+func TestJSONFormatterDuration(t *testing.T) {
+	entry := core.GetEntryFromPool()
+	defer core.PutEntryToPool(entry)
+
+	entry.Timestamp = time.Now()
+	entry.Level = core.INFO
+	entry.Message = []byte("query")
+	entry.Duration = 1500 * time.Microsecond
+
+	for _, pretty := range []bool{false, true} {
+		jf := NewJSON()
+		jf.PrettyPrint = pretty
+
+		buf := &bytes.Buffer{}
+		if err := jf.Format(buf, entry); err != nil {
+			t.Fatalf("JSONFormatter.Format returned error: %v", err)
+		}
+		if bytes.Contains(buf.Bytes(), []byte("duration")) {
+			t.Errorf("pretty=%v: duration should be hidden without ShowDuration: %s", pretty, buf.String())
+		}
+
+		buf.Reset()
+		jf.ShowDuration = true
+		if err := jf.Format(buf, entry); err != nil {
+			t.Fatalf("JSONFormatter.Format returned error: %v", err)
+		}
+		var decoded struct {
+			Duration int64 `json:"duration"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, buf.String())
+		}
+		if decoded.Duration != 1500000 {
+			t.Errorf("pretty=%v: expected duration 1500000, got %d", pretty, decoded.Duration)
+		}
+	}
+}
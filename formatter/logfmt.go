@@ -0,0 +1,292 @@
+package formatter
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/util"
+)
+
+// DefaultLogfmtTimestampFormat is the timestamp format used when TimestampFormat is empty
+const DefaultLogfmtTimestampFormat = time.RFC3339Nano
+
+// LogfmtFormatter formats log entries as logfmt lines (key=value pairs).
+// Keys come in a fixed order: time, level, msg, the entry metadata, error,
+// fields in insertion order (or sorted by key with SortFields), key-value
+// pairs in the order given, tags and custom metrics. The version and
+// environment of an entry are written whenever it has them.
+type LogfmtFormatter struct {
+	TimestampFormat   string   // Custom timestamp format (default DefaultLogfmtTimestampFormat)
+	ShowCaller        bool     // Show caller information
+	ShowPID           bool     // Show process ID
+	ShowGoroutine     bool     // Show goroutine ID
+	ShowHostname      bool     // Show hostname
+	ShowApplication   bool     // Show application name
+	ShowTrace         bool     // Show trace information
+	ShowDuration      bool     // Show operation duration
+	IncludeStackTrace bool     // Include the stack trace of errors
+	CustomFieldOrder  []string // Keys of fields written first, in this order
+	SortFields        bool     // Sort fields by key instead of keeping insertion order
+
+	// Deprecated: use logger.LoggerConfig.FieldPolicy.
+	SensitiveFields []string // List of sensitive field names
+	// Deprecated: use logger.LoggerConfig.FieldPolicy.
+	MaskSensitiveData bool // Whether to mask sensitive data
+	// Deprecated: use logger.LoggerConfig.FieldPolicy.
+	MaskValue string // String value to use for masking
+	// Deprecated: use logger.LoggerConfig.FieldPolicy.
+	MaskStringBytes []byte // Byte slice for masking (zero-allocation)
+}
+
+// NewLogfmt creates a new LogfmtFormatter
+func NewLogfmt() *LogfmtFormatter {
+	return &LogfmtFormatter{
+		TimestampFormat: DefaultLogfmtTimestampFormat,
+		MaskValue:       "[MASKED]",
+		MaskStringBytes: []byte("[MASKED]"),
+		SensitiveFields: make([]string, 0),
+	}
+}
+
+// Format formats a log entry as a logfmt line
+func (f *LogfmtFormatter) Format(buf *bytes.Buffer, entry *core.LogEntry) error {
+	format := f.TimestampFormat
+	if format == "" {
+		format = DefaultLogfmtTimestampFormat
+	}
+	// Scratch space on the stack: util.PutSmallBuf allocates when boxing the slice it pools
+	var scratch [64]byte
+	buf.WriteString("time=")
+	buf.Write(entry.Timestamp.AppendFormat(scratch[:0], format))
+
+	buf.WriteString(" level=")
+	if entry.Level >= core.TRACE && entry.Level <= core.PANIC {
+		buf.WriteString(core.LowerLevelStrings[entry.Level])
+	} else {
+		buf.WriteString("unknown")
+	}
+
+	buf.WriteString(" msg=")
+	writeLogfmtValue(buf, entry.Message)
+
+	if f.ShowCaller && entry.Caller != nil {
+		tmp := append(scratch[:0], entry.Caller.File...)
+		tmp = append(tmp, ':')
+		tmp = strconv.AppendInt(tmp, int64(entry.Caller.Line), 10)
+		f.writePair(buf, "caller", tmp)
+	}
+	if f.ShowPID {
+		buf.WriteString(" pid=")
+		util.WriteInt(buf, int64(entry.PID))
+	}
+	if f.ShowGoroutine && len(entry.GoroutineID) > 0 {
+		f.writePair(buf, "goroutine_id", entry.GoroutineID)
+	}
+	if f.ShowHostname {
+		f.writePair(buf, "hostname", entry.Hostname)
+	}
+	if f.ShowApplication {
+		f.writePair(buf, "application", entry.Application)
+	}
+	// Version and environment have no Show* option: the logger only sets them when configured
+	f.writePair(buf, "version", entry.Version)
+	f.writePair(buf, "environment", entry.Environment)
+	if f.ShowTrace {
+		f.writePair(buf, "trace_id", entry.TraceID)
+		f.writePair(buf, "span_id", entry.SpanID)
+		f.writePair(buf, "trace_flags", entry.TraceFlags)
+		f.writePair(buf, "user_id", entry.UserID)
+	}
+	if f.ShowDuration && entry.Duration > 0 {
+		buf.WriteString(" duration=")
+		buf.WriteString(entry.Duration.String())
+	}
+
+	if entry.Error != nil {
+		f.formatError(buf, entry.Error)
+	}
+
+	f.formatFields(buf, entry)
+	for i := 0; i+1 < len(entry.KeyVals); i += 2 {
+		f.writeField(buf, core.BytesToString(entry.KeyVals[i]), entry.KeyVals[i+1])
+	}
+
+	if len(entry.Tags) > 0 {
+		tags := util.GetBuffer()
+		for i, tag := range entry.Tags {
+			if i > 0 {
+				tags.WriteByte(',')
+			}
+			tags.Write(tag)
+		}
+		f.writePair(buf, "tags", tags.Bytes())
+		util.PutBuffer(tags)
+	}
+	if len(entry.CustomMetrics) > 0 {
+		for _, name := range sortedMetricNames(entry.CustomMetrics) {
+			buf.WriteByte(' ')
+			writeLogfmtKey(buf, name)
+			buf.WriteByte('=')
+			if v := entry.CustomMetrics[name]; math.IsNaN(v) || math.IsInf(v, 0) {
+				buf.WriteString("null")
+			} else {
+				writeMetricValue(buf, v)
+			}
+		}
+	}
+
+	if f.IncludeStackTrace && len(entry.StackTrace) > 0 {
+		f.writePair(buf, "stack_trace", entry.StackTrace)
+	}
+
+	buf.WriteByte('\n')
+	return nil
+}
+
+// formatError writes the error message, its type and, for wrapped or joined
+// errors, the messages of the cause chain separated by " | "
+func (f *LogfmtFormatter) formatError(buf *bytes.Buffer, err error) {
+	msg := util.GetBuffer()
+	defer util.PutBuffer(msg)
+
+	core.WriteError(msg, err)
+	f.writePair(buf, "error", msg.Bytes())
+	f.writePair(buf, "error_type", core.StringToBytes(core.ErrorTypeName(err)))
+
+	if chain := core.ErrorChain(err); len(chain) > 1 {
+		msg.Reset()
+		for i, cause := range chain {
+			if i > 0 {
+				msg.WriteString(" | ")
+			}
+			core.WriteError(msg, cause)
+		}
+		f.writePair(buf, "error_chain", msg.Bytes())
+	}
+}
+
+// formatFields writes the map and typed fields of the entry in the order of orderFields
+func (f *LogfmtFormatter) formatFields(buf *bytes.Buffer, entry *core.LogEntry) {
+	if len(entry.Fields) == 0 && len(entry.TypedFields) == 0 {
+		return
+	}
+	var stack [maxOrderedFields]fieldRef
+	var value *[64]byte // Escapes through AppendText, so only allocated for typed fields
+	for _, ref := range orderFields(stack[:0], entry, f.CustomFieldOrder, f.SortFields) {
+		if ref.typed < 0 {
+			f.writeField(buf, ref.key, entry.Fields[ref.key])
+			continue
+		}
+		if value == nil {
+			value = new([64]byte)
+		}
+		f.writeField(buf, ref.key, entry.TypedFields[ref.typed].AppendText(value[:0]))
+	}
+}
+
+// writeField writes a user field, masking its value if the key is sensitive
+func (f *LogfmtFormatter) writeField(buf *bytes.Buffer, key string, value []byte) {
+	if f.MaskSensitiveData && contains(f.SensitiveFields, key) {
+		value = f.MaskStringBytes
+		if len(value) == 0 {
+			value = core.StringToBytes(f.MaskValue)
+		}
+	}
+	buf.WriteByte(' ')
+	writeLogfmtKey(buf, key)
+	buf.WriteByte('=')
+	writeLogfmtValue(buf, value)
+}
+
+// writePair writes a built-in key and its value if the value is set
+func (f *LogfmtFormatter) writePair(buf *bytes.Buffer, key string, value []byte) {
+	if len(value) == 0 {
+		return
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(key)
+	buf.WriteByte('=')
+	writeLogfmtValue(buf, value)
+}
+
+// writeLogfmtKey writes a key, replacing the characters logfmt does not allow
+// in keys (space, '=', '"' and control characters) with '_'
+func writeLogfmtKey(buf *bytes.Buffer, key string) {
+	if key == "" {
+		buf.WriteByte('_')
+		return
+	}
+	for i := 0; i < len(key); i++ {
+		b := key[i]
+		if b <= ' ' || b == '=' || b == '"' || b == 0x7f {
+			b = '_'
+		}
+		buf.WriteByte(b)
+	}
+}
+
+// writeLogfmtValue writes a value, quoting it when it is empty or contains
+// spaces, '=', '"', '\', control characters or invalid UTF-8
+func writeLogfmtValue(buf *bytes.Buffer, value []byte) {
+	if !logfmtNeedsQuote(value) {
+		buf.Write(value)
+		return
+	}
+
+	buf.WriteByte('"')
+	for i := 0; i < len(value); {
+		b := value[i]
+		if b >= utf8.RuneSelf {
+			r, size := utf8.DecodeRune(value[i:])
+			if r == utf8.RuneError && size == 1 {
+				buf.WriteString(`�`)
+			} else {
+				buf.Write(value[i : i+size])
+			}
+			i += size
+			continue
+		}
+		switch b {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(b)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if b < ' ' || b == 0x7f {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hexDigits[b>>4])
+				buf.WriteByte(hexDigits[b&0xf])
+			} else {
+				buf.WriteByte(b)
+			}
+		}
+		i++
+	}
+	buf.WriteByte('"')
+}
+
+// logfmtNeedsQuote reports whether a value must be quoted
+func logfmtNeedsQuote(value []byte) bool {
+	if len(value) == 0 {
+		return true
+	}
+	ascii := true
+	for _, b := range value {
+		if b <= ' ' || b == '=' || b == '"' || b == '\\' || b == 0x7f {
+			return true
+		}
+		if b >= utf8.RuneSelf {
+			ascii = false
+		}
+	}
+	return !ascii && !utf8.Valid(value)
+}
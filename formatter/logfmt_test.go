@@ -0,0 +1,104 @@
+package formatter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Lunar-Chipter/mire/core"
+)
+
+// newLogfmtEntry returns an entry with a fixed timestamp for logfmt tests
+func newLogfmtEntry() *core.LogEntry {
+	return &core.LogEntry{
+		Timestamp: time.Date(2024, 3, 5, 7, 8, 9, 123000000, time.UTC),
+		Level:     core.INFO,
+		Message:   []byte("request served"),
+		Fields:    map[string][]byte{"path": []byte("/users"), "status": []byte("200")},
+	}
+}
+
+// TestLogfmtFormatter tests key order and plain values
+func TestLogfmtFormatter(t *testing.T) {
+	f := NewLogfmt()
+	f.ShowTrace = true
+	entry := newLogfmtEntry()
+	entry.TraceID = []byte("4bf92f3577b34da6a3ce929d0e0e4736")
+	entry.KeyVals = [][]byte{[]byte("region"), []byte("eu-west")}
+	entry.TypedFields = []core.Field{{Key: "attempt", Type: core.Int64Field, Int: 2}}
+
+	var buf bytes.Buffer
+	if err := f.Format(&buf, entry); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	want := `time=2024-03-05T07:08:09.123Z level=info msg="request served" trace_id=4bf92f3577b34da6a3ce929d0e0e4736 ` +
+		`path=/users status=200 attempt=2 region=eu-west` + "\n"
+	if buf.String() != want {
+		t.Errorf("Unexpected line\n got: %s\nwant: %s", buf.String(), want)
+	}
+}
+
+// TestLogfmtFormatterQuoting tests quoting and escaping of keys and values
+func TestLogfmtFormatterQuoting(t *testing.T) {
+	f := NewLogfmt()
+	entry := newLogfmtEntry()
+	entry.Message = []byte("line1\nsaid \"hi\"")
+	entry.Fields = map[string][]byte{
+		"empty":     {},
+		"bad key=x": []byte("a=b"),
+		"path":      []byte(`C:\tmp`),
+		"ctrl":      {0x01},
+		"utf8":      []byte("héllo"),
+		"invalid":   {0xff},
+	}
+
+	var buf bytes.Buffer
+	_ = f.Format(&buf, entry)
+	want := `time=2024-03-05T07:08:09.123Z level=info msg="line1\nsaid \"hi\"" bad_key_x="a=b" ctrl="\u0001" ` +
+		`empty="" invalid="` + "\uFFFD" + `" path="C:\\tmp" utf8=héllo` + "\n"
+	if buf.String() != want {
+		t.Errorf("Unexpected line\n got: %s\nwant: %s", buf.String(), want)
+	}
+}
+
+// TestLogfmtFormatterError tests error, caller and masking output
+func TestLogfmtFormatterError(t *testing.T) {
+	f := NewLogfmt()
+	f.ShowCaller = true
+	f.MaskSensitiveData = true
+	f.SensitiveFields = []string{"password"}
+	entry := newLogfmtEntry()
+	entry.Level = core.ERROR
+	entry.Caller = &core.Caller{File: "handler.go", Line: 42}
+	entry.Error = fmt.Errorf("load user: %w", errors.New("timeout"))
+	entry.Fields = map[string][]byte{"password": []byte("hunter2")}
+
+	var buf bytes.Buffer
+	_ = f.Format(&buf, entry)
+	want := `time=2024-03-05T07:08:09.123Z level=error msg="request served" caller=handler.go:42 ` +
+		`error="load user: timeout" error_type=*fmt.wrapError error_chain="load user: timeout | timeout" password=[MASKED]` + "\n"
+	if buf.String() != want {
+		t.Errorf("Unexpected line\n got: %s\nwant: %s", buf.String(), want)
+	}
+}
+
+// TestLogfmtFormatterAllocations tests that formatting map fields and key-value pairs does not allocate
+func TestLogfmtFormatterAllocations(t *testing.T) {
+	f := NewLogfmt()
+	entry := newLogfmtEntry()
+	entry.KeyVals = [][]byte{[]byte("region"), []byte("eu west")}
+	entry.Caller = &core.Caller{File: "handler.go", Line: 42}
+	f.ShowCaller = true
+	var buf bytes.Buffer
+	buf.Grow(1024)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		buf.Reset()
+		_ = f.Format(&buf, entry)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations, got %v", allocs)
+	}
+}
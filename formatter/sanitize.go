@@ -0,0 +1,94 @@
+package formatter
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+const hexDigits = "0123456789abcdef"
+
+// writeSanitized writes user-controlled text so that it cannot forge log lines
+// or control a terminal: CR, LF and tab become \r, \n and \t, other C0
+// controls, ESC and DEL become \xHH, C1 controls become \u00HH and invalid
+// UTF-8 bytes are replaced with U+FFFD. Backslashes become \\, so that a
+// literal "\n" in the text cannot pass for an escaped line break. With a
+// non-empty indent, LF is written as a line break followed by indent, so
+// continuation lines stay recognizable.
+func writeSanitized(buf *bytes.Buffer, text []byte, indent []byte) {
+	start := 0
+	for i := 0; i < len(text); {
+		b := text[i]
+		if b >= ' ' && b < 0x7f && b != '\\' {
+			i++
+			continue
+		}
+
+		if b >= utf8.RuneSelf {
+			r, size := utf8.DecodeRune(text[i:])
+			invalid := r == utf8.RuneError && size == 1
+			if !invalid && (r < 0x80 || r > 0x9f) {
+				i += size
+				continue
+			}
+			buf.Write(text[start:i])
+			if invalid {
+				buf.WriteString("\uFFFD")
+			} else {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hexDigits[r>>4])
+				buf.WriteByte(hexDigits[r&0xf])
+			}
+			i += size
+			start = i
+			continue
+		}
+
+		buf.Write(text[start:i])
+		switch b {
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\n':
+			if len(indent) > 0 {
+				buf.WriteByte('\n')
+				buf.Write(indent)
+			} else {
+				buf.WriteString(`\n`)
+			}
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			buf.WriteString(`\x`)
+			buf.WriteByte(hexDigits[b>>4])
+			buf.WriteByte(hexDigits[b&0xf])
+		}
+		i++
+		start = i
+	}
+	buf.Write(text[start:])
+}
+
+// needsSanitizing reports whether writeSanitized would change text
+func needsSanitizing(text []byte) bool {
+	ascii := true
+	for _, b := range text {
+		if b < ' ' || b == 0x7f || b == '\\' {
+			return true
+		}
+		if b >= utf8.RuneSelf {
+			ascii = false
+		}
+	}
+	if ascii {
+		return false
+	}
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRune(text[i:])
+		if (r == utf8.RuneError && size == 1) || (r >= 0x80 && r <= 0x9f) {
+			return true
+		}
+		i += size
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package formatter
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Lunar-Chipter/mire/core"
+)
+
+// TestWriteSanitized tests escaping of line breaks, controls, ANSI sequences and invalid UTF-8
+func TestWriteSanitized(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		indent string
+		want   string
+	}{
+		{"plain", "user logged in", "", "user logged in"},
+		{"unicode", "héllo 世界", "", "héllo 世界"},
+		{"forged line", "ok\n[ERROR] fake entry", "", `ok\n[ERROR] fake entry`},
+		{"carriage return and tab", "a\rb\tc", "", `a\rb\tc`},
+		{"ansi", "\x1b[31mred\x1b[0m", "", `\x1b[31mred\x1b[0m`},
+		{"c0 and del", "a\x00b\x07c\x7f", "", `a\x00b\x07c\x7f`},
+		{"c1", "a\u0085b\u009bc", "", `a\u0085b\u009bc`},
+		{"invalid utf-8", "a\xffb\xc3", "", "a\uFFFDb\uFFFD"},
+		{"indented lines", "first\nsecond\r\nthird", "    ", "first\n    second\\r\n    third"},
+		{"backslash", `C:\temp`, "", `C:\\temp`},
+		{"literal backslash-n", `ok\n[ERROR] fake entry`, "", `ok\\n[ERROR] fake entry`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writeSanitized(&buf, []byte(tt.input), []byte(tt.indent))
+			if buf.String() != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, buf.String())
+			}
+			if needsSanitizing([]byte(tt.input)) != (tt.input != tt.want) {
+				t.Errorf("needsSanitizing(%q) disagrees with writeSanitized", tt.input)
+			}
+		})
+	}
+}
+
+// TestTextFormatterSanitize tests that user data cannot add lines or escape sequences to text output
+func TestTextFormatterSanitize(t *testing.T) {
+	entry := &core.LogEntry{
+		Level:   core.INFO,
+		Message: []byte("login failed\n[INFO] admin logged in"),
+		Tags:    [][]byte{[]byte("a\x1b[2Jb")},
+		KeyVals: [][]byte{[]byte("agent"), []byte("curl\r\n")},
+		Error:   errors.New("bad\ninput"),
+	}
+	entry.SetField("user", []byte("\x1b[31mroot"))
+	entry.TypedFields = []core.Field{{Key: "path", Type: core.StringField, Str: "/a\nb"}}
+
+	var buf bytes.Buffer
+	if err := (&TextFormatter{Sanitize: true}).Format(&buf, entry); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	out := buf.String()
+	if strings.Count(out, "\n") != 1 || strings.Contains(out, "\x1b") || strings.Contains(out, "\r") {
+		t.Fatalf("Expected a single line without control characters, got %q", out)
+	}
+	for _, want := range []string{`login failed\n[INFO] admin logged in`, `user=\x1b[31mroot`, `path=/a\nb`, `agent=curl\r\n`, `[a\x1b[2Jb]`, `error=bad\ninput`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %s in %q", want, out)
+		}
+	}
+
+	// A literal backslash-n must not look like an escaped line break
+	buf.Reset()
+	forged := &core.LogEntry{Level: core.INFO, Message: []byte(`login failed\n[INFO] admin logged in`)}
+	if err := (&TextFormatter{Sanitize: true}).Format(&buf, forged); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `login failed\\n[INFO] admin logged in`) {
+		t.Errorf("Expected the backslash to be escaped, got %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := (&TextFormatter{Sanitize: true, MultilineIndent: "  | "}).Format(&buf, entry); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "login failed\n  | [INFO] admin logged in") {
+		t.Errorf("Expected an indented continuation line, got %q", buf.String())
+	}
+}
+
+// TestCSVFormatterSanitize tests that values cannot break CSV rows
+func TestCSVFormatterSanitize(t *testing.T) {
+	entry := &core.LogEntry{Level: core.INFO, Message: []byte("a\nb,\"c\"\x1b[0m")}
+	entry.SetField("user", []byte("x\"\ny"))
+
+	var buf bytes.Buffer
+	f := &CSVFormatter{FieldOrder: []string{"message", "user"}, Sanitize: true}
+	if err := f.Format(&buf, entry); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	want := `"a\nb,""c""\x1b[0m","x""\ny"` + "\n"
+	if buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
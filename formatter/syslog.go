@@ -0,0 +1,316 @@
+package formatter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/util"
+)
+
+// SyslogFormat selects the syslog message format
+type SyslogFormat int
+
+const (
+	// RFC5424 is the current syslog protocol with structured data
+	RFC5424 SyslogFormat = iota
+	// RFC3164 is the legacy BSD syslog format
+	RFC3164
+)
+
+// SyslogFacility is a syslog facility code (RFC 5424 section 6.2.1)
+type SyslogFacility int
+
+const (
+	FacilityKern SyslogFacility = iota
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLPR
+	FacilityNews
+	FacilityUUCP
+	FacilityCron
+	FacilityAuthPriv
+	FacilityFTP
+	FacilityLocal0 SyslogFacility = iota + 4
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+// Syslog severities (RFC 5424 section 6.2.1)
+const (
+	SeverityEmergency = iota
+	SeverityAlert
+	SeverityCritical
+	SeverityError
+	SeverityWarning
+	SeverityNotice
+	SeverityInfo
+	SeverityDebug
+)
+
+// DefaultSyslogSDID is the SD-ID of the structured data element carrying entry fields
+const DefaultSyslogSDID = "fields@32473"
+
+const (
+	syslogTimestamp5424 = "2006-01-02T15:04:05.000000Z07:00"
+	syslogTimestamp3164 = time.Stamp
+
+	// Maximum lengths of the RFC 5424 header fields
+	syslogMaxHostname = 255
+	syslogMaxAppName  = 48
+	syslogMaxMsgID    = 32
+	syslogMaxParam    = 32
+	syslogMaxTag      = 32 // RFC 3164 TAG
+)
+
+// syslogNil is the RFC 5424 NILVALUE
+var syslogNil = []byte{'-'}
+
+// SyslogSeverity returns the syslog severity of a level: TRACE and DEBUG map to
+// debug, FATAL to critical and PANIC to alert
+func SyslogSeverity(level core.Level) int {
+	switch level {
+	case core.TRACE, core.DEBUG:
+		return SeverityDebug
+	case core.INFO:
+		return SeverityInfo
+	case core.NOTICE:
+		return SeverityNotice
+	case core.WARN:
+		return SeverityWarning
+	case core.ERROR:
+		return SeverityError
+	case core.FATAL:
+		return SeverityCritical
+	case core.PANIC:
+		return SeverityAlert
+	}
+	return SeverityNotice
+}
+
+// SyslogFormatter formats log entries as syslog messages, one per line.
+// Control characters are written as #ooo octal escapes, as rsyslog does, so a
+// message never spans lines and writer.Syslog can split batched writes.
+type SyslogFormatter struct {
+	Protocol SyslogFormat               // Message format (RFC5424 or RFC3164)
+	Facility SyslogFacility             // Facility of every message
+	Hostname string                     // HOSTNAME (defaults to the entry's hostname)
+	AppName  string                     // APP-NAME, or TAG in RFC 3164 (defaults to the entry's application)
+	MsgID    string                     // MSGID of RFC 5424 messages ("-" when empty)
+	SDID     string                     // SD-ID of the structured data with entry fields (default DefaultSyslogSDID)
+	Severity func(level core.Level) int // Level to severity mapping (defaults to SyslogSeverity)
+
+	CustomFieldOrder []string // Keys of fields written first, in this order
+	SortFields       bool     // Sort fields by key instead of keeping insertion order
+}
+
+// NewSyslog creates a SyslogFormatter for RFC 5424 messages of the user facility,
+// with the host name and program name of the process
+func NewSyslog() *SyslogFormatter {
+	hostname, _ := os.Hostname()
+	return &SyslogFormatter{
+		Protocol: RFC5424,
+		Facility: FacilityUser,
+		Hostname: hostname,
+		AppName:  filepath.Base(os.Args[0]),
+		SDID:     DefaultSyslogSDID,
+	}
+}
+
+// Format formats a log entry as a syslog message terminated by a newline
+func (f *SyslogFormatter) Format(buf *bytes.Buffer, entry *core.LogEntry) error {
+	severity := SyslogSeverity(entry.Level)
+	if f.Severity != nil {
+		severity = f.Severity(entry.Level)
+	}
+
+	tmp := util.GetSmallBuf()
+	buf.WriteByte('<')
+	tmp = strconv.AppendInt(tmp[:0], int64(f.Facility)*8+int64(severity), 10)
+	buf.Write(tmp)
+	buf.WriteByte('>')
+
+	hostname := core.StringToBytes(f.Hostname)
+	if len(hostname) == 0 {
+		hostname = entry.Hostname
+	}
+	appName := core.StringToBytes(f.AppName)
+	if len(appName) == 0 {
+		appName = entry.Application
+	}
+
+	if f.Protocol == RFC3164 {
+		tmp = entry.Timestamp.AppendFormat(tmp[:0], syslogTimestamp3164)
+		buf.Write(tmp)
+		buf.WriteByte(' ')
+		writeSyslogHeader(buf, hostname, syslogMaxHostname)
+		buf.WriteByte(' ')
+		writeSyslogHeader(buf, appName, syslogMaxTag)
+		if entry.PID > 0 {
+			buf.WriteByte('[')
+			tmp = strconv.AppendInt(tmp[:0], int64(entry.PID), 10)
+			buf.Write(tmp)
+			buf.WriteByte(']')
+		}
+		buf.WriteString(": ")
+		writeSyslogText(buf, entry.Message, false)
+		f.writeParams(buf, entry, false)
+	} else {
+		buf.WriteString("1 ")
+		if entry.Timestamp.IsZero() {
+			buf.Write(syslogNil)
+		} else {
+			tmp = entry.Timestamp.AppendFormat(tmp[:0], syslogTimestamp5424)
+			buf.Write(tmp)
+		}
+		buf.WriteByte(' ')
+		writeSyslogHeader(buf, hostname, syslogMaxHostname)
+		buf.WriteByte(' ')
+		writeSyslogHeader(buf, appName, syslogMaxAppName)
+		buf.WriteByte(' ')
+		if entry.PID > 0 {
+			tmp = strconv.AppendInt(tmp[:0], int64(entry.PID), 10)
+			buf.Write(tmp)
+		} else {
+			buf.Write(syslogNil)
+		}
+		buf.WriteByte(' ')
+		writeSyslogHeader(buf, core.StringToBytes(f.MsgID), syslogMaxMsgID)
+		buf.WriteByte(' ')
+		f.writeParams(buf, entry, true)
+		if len(entry.Message) > 0 {
+			buf.WriteByte(' ')
+			writeSyslogText(buf, entry.Message, false)
+		}
+	}
+	util.PutSmallBuf(tmp)
+
+	buf.WriteByte('\n')
+	return nil
+}
+
+// writeParams writes the entry fields as an RFC 5424 structured data element,
+// or as key=value pairs after the message of an RFC 3164 message
+func (f *SyslogFormatter) writeParams(buf *bytes.Buffer, entry *core.LogEntry, structured bool) {
+	start := buf.Len()
+	if structured {
+		buf.WriteByte('[')
+		sdid := f.SDID
+		if sdid == "" {
+			sdid = DefaultSyslogSDID
+		}
+		writeSyslogName(buf, core.StringToBytes(sdid))
+	}
+	written := false
+	param := func(key, value []byte) {
+		buf.WriteByte(' ')
+		if structured {
+			writeSyslogName(buf, key)
+			buf.WriteString(`="`)
+			writeSyslogText(buf, value, true)
+			buf.WriteByte('"')
+		} else {
+			writeSyslogText(buf, key, false)
+			buf.WriteByte('=')
+			writeSyslogText(buf, value, false)
+		}
+		written = true
+	}
+
+	if len(entry.Fields) > 0 || len(entry.TypedFields) > 0 {
+		tmp := util.GetSmallBuf()
+		var stack [maxOrderedFields]fieldRef
+		for _, ref := range orderFields(stack[:0], entry, f.CustomFieldOrder, f.SortFields) {
+			if ref.typed < 0 {
+				param(core.StringToBytes(ref.key), entry.Fields[ref.key])
+				continue
+			}
+			tmp = entry.TypedFields[ref.typed].AppendText(tmp[:0])
+			param(core.StringToBytes(ref.key), tmp)
+		}
+		util.PutSmallBuf(tmp)
+	}
+	for i := 0; i+1 < len(entry.KeyVals); i += 2 {
+		param(entry.KeyVals[i], entry.KeyVals[i+1])
+	}
+	if entry.Error != nil {
+		param([]byte("error"), core.StringToBytes(entry.Error.Error()))
+	}
+	if len(entry.TraceID) > 0 {
+		param([]byte("trace_id"), entry.TraceID)
+	}
+	if len(entry.SpanID) > 0 {
+		param([]byte("span_id"), entry.SpanID)
+	}
+
+	if structured {
+		if written {
+			buf.WriteByte(']')
+		} else {
+			buf.Truncate(start)
+			buf.Write(syslogNil)
+		}
+	}
+}
+
+// writeSyslogHeader writes a header field of printable ASCII, truncated to max
+// bytes, or the NILVALUE when it is empty
+func writeSyslogHeader(buf *bytes.Buffer, value []byte, max int) {
+	if len(value) == 0 {
+		buf.Write(syslogNil)
+		return
+	}
+	if len(value) > max {
+		value = value[:max]
+	}
+	for _, b := range value {
+		if b <= ' ' || b > '~' {
+			b = '_'
+		}
+		buf.WriteByte(b)
+	}
+}
+
+// writeSyslogName writes an SD-ID or PARAM-NAME, replacing the characters
+// RFC 5424 forbids in names
+func writeSyslogName(buf *bytes.Buffer, name []byte) {
+	if len(name) > syslogMaxParam {
+		name = name[:syslogMaxParam]
+	}
+	for _, b := range name {
+		if b <= ' ' || b > '~' || b == '=' || b == ']' || b == '"' {
+			b = '_'
+		}
+		buf.WriteByte(b)
+	}
+}
+
+// writeSyslogText writes free text with control characters escaped as #ooo.
+// In a PARAM-VALUE, '"', '\' and ']' are also escaped with a backslash.
+func writeSyslogText(buf *bytes.Buffer, text []byte, paramValue bool) {
+	for _, b := range text {
+		switch {
+		case b < ' ' || b == 0x7f:
+			buf.WriteByte('#')
+			buf.WriteByte('0' + b>>6)
+			buf.WriteByte('0' + (b>>3)&7)
+			buf.WriteByte('0' + b&7)
+		case paramValue && (b == '"' || b == '\\' || b == ']'):
+			buf.WriteByte('\\')
+			buf.WriteByte(b)
+		default:
+			buf.WriteByte(b)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package formatter
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Lunar-Chipter/mire/core"
+)
+
+// newSyslogEntry returns an entry with a fixed timestamp for syslog tests
+func newSyslogEntry() *core.LogEntry {
+	return &core.LogEntry{
+		Timestamp: time.Date(2024, 3, 5, 7, 8, 9, 123456000, time.UTC),
+		Level:     core.ERROR,
+		Message:   []byte("payment failed"),
+		PID:       42,
+		Fields:    map[string][]byte{"user": []byte("alice"), "note": []byte(`say "hi"]`)},
+	}
+}
+
+// TestSyslogFormatterRFC5424 tests RFC 5424 messages with structured data
+func TestSyslogFormatterRFC5424(t *testing.T) {
+	f := &SyslogFormatter{Facility: FacilityLocal0, Hostname: "web-1", AppName: "billing", MsgID: "PAY"}
+	entry := newSyslogEntry()
+	entry.TypedFields = []core.Field{{Key: "attempt", Type: core.Int64Field, Int: 3}}
+	entry.Error = errors.New("card declined")
+
+	var buf bytes.Buffer
+	if err := f.Format(&buf, entry); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	want := `<131>1 2024-03-05T07:08:09.123456Z web-1 billing 42 PAY ` +
+		`[fields@32473 note="say \"hi\"\]" user="alice" attempt="3" error="card declined"] payment failed` + "\n"
+	if buf.String() != want {
+		t.Errorf("Unexpected message\n got: %s\nwant: %s", buf.String(), want)
+	}
+}
+
+// TestSyslogFormatterRFC5424Nil tests NILVALUEs for missing header fields and structured data
+func TestSyslogFormatterRFC5424Nil(t *testing.T) {
+	f := &SyslogFormatter{Facility: FacilityUser}
+	entry := &core.LogEntry{Level: core.INFO, Message: []byte("line1\nline2")}
+
+	var buf bytes.Buffer
+	_ = f.Format(&buf, entry)
+	if want := "<14>1 - - - - - - line1#012line2\n"; buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
+
+// TestSyslogFormatterRFC3164 tests legacy BSD messages
+func TestSyslogFormatterRFC3164(t *testing.T) {
+	f := &SyslogFormatter{Protocol: RFC3164, Facility: FacilityDaemon, Hostname: "web-1", AppName: "billing"}
+	entry := newSyslogEntry()
+	entry.Level = core.WARN
+	entry.Fields = map[string][]byte{"user": []byte("alice")}
+
+	var buf bytes.Buffer
+	_ = f.Format(&buf, entry)
+	if want := "<28>Mar  5 07:08:09 web-1 billing[42]: payment failed user=alice\n"; buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
+
+// TestSyslogSeverity tests the level to severity mapping and its override
+func TestSyslogSeverity(t *testing.T) {
+	if SyslogSeverity(core.DEBUG) != SeverityDebug || SyslogSeverity(core.FATAL) != SeverityCritical || SyslogSeverity(core.PANIC) != SeverityAlert {
+		t.Error("Unexpected default severity mapping")
+	}
+
+	f := &SyslogFormatter{Severity: func(core.Level) int { return SeverityEmergency }}
+	var buf bytes.Buffer
+	_ = f.Format(&buf, &core.LogEntry{Level: core.INFO})
+	if !bytes.HasPrefix(buf.Bytes(), []byte("<0>1 ")) {
+		t.Errorf("Expected the custom severity, got %q", buf.String())
+	}
+}
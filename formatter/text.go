@@ -26,13 +26,21 @@ type TextFormatter struct {
 	IncludeStackTrace bool                                // Enable stack trace for errors
 	StackTraceDepth   int                                 // Maximum stack trace depth
 	ShowDuration      bool                                // Show operation duration
-	CustomFieldOrder  []string                            // Custom order for fields
+	CustomFieldOrder  []string                            // Keys of fields written first, in this order
+	SortFields        bool                                // Sort fields by key instead of keeping insertion order
 	FieldTransformers map[string]func(interface{}) string // Functions to transform field values
-	SensitiveFields   []string                            // List of sensitive field names
-	MaskSensitiveData bool                                // Whether to mask sensitive data
-	MaskValue         string                              // String value to use for masking
-	MaskStringBytes   []byte                              // Byte slice for masking (zero-allocation)
 	DisableHTMLEscape bool                                // Disable HTML escaping in text
+	Sanitize          bool                                // Escape control characters, ANSI escape sequences and invalid UTF-8 in logged data
+	MultilineIndent   string                              // With Sanitize, write message line breaks as a newline and this indent instead of \n
+
+	// Deprecated: use logger.LoggerConfig.FieldPolicy.
+	SensitiveFields []string // List of sensitive field names
+	// Deprecated: use logger.LoggerConfig.FieldPolicy.
+	MaskSensitiveData bool // Whether to mask sensitive data
+	// Deprecated: use logger.LoggerConfig.FieldPolicy.
+	MaskValue string // String value to use for masking
+	// Deprecated: use logger.LoggerConfig.FieldPolicy.
+	MaskStringBytes []byte // Byte slice for masking (zero-allocation)
 }
 
 var ResetColorBytes = []byte("\033[0m")
@@ -112,7 +120,11 @@ func (f *TextFormatter) Format(buf *bytes.Buffer, entry *core.LogEntry) error {
 			buf.Write([]byte("\033[1m")) // Bold for important messages
 		}
 	}
-	buf.Write(entry.Message) // Message is []byte, efficient
+	if f.Sanitize {
+		writeSanitized(buf, entry.Message, core.StringToBytes(f.MultilineIndent))
+	} else {
+		buf.Write(entry.Message) // Message is []byte, efficient
+	}
 	if f.EnableColors {
 		buf.Write(ResetColorBytes)
 	}
@@ -199,7 +211,7 @@ func (f *TextFormatter) writeMetaPartBytes(buf *bytes.Buffer, part []byte) {
 	if f.EnableColors {
 		buf.Write(metaColorBytes)
 	}
-	buf.Write(part) // part is already a byte slice
+	f.writeText(buf, part)
 	if f.EnableColors {
 		buf.Write(ResetColorBytes)
 	}
@@ -224,7 +236,7 @@ func (f *TextFormatter) writeTracePartBytes(buf *bytes.Buffer, key []byte, value
 	}
 	buf.Write(key)
 	buf.WriteByte(':')
-	buf.Write(value) // value is already a byte slice
+	f.writeText(buf, value)
 	if f.EnableColors {
 		buf.Write(ResetColorBytes)
 	}
@@ -238,20 +250,28 @@ func (f *TextFormatter) writePostMessage(buf *bytes.Buffer, entry *core.LogEntry
 			buf.Write(errorColorBytes)
 		}
 		buf.Write([]byte("error="))
-		if appender, ok := entry.Error.(core.ErrAppend); ok {
-			appender.AppendError(buf) // Use zero-allocation append
-		} else {
-			buf.WriteString(entry.Error.Error()) // Fallback to standard Error() which allocates
-		}
+		f.writeError(buf, entry.Error)
 		// Removed the '"' as it was inconsistent
+		buf.WriteString(" error_type=")
+		buf.WriteString(core.ErrorTypeName(entry.Error))
+		if chain := core.ErrorChain(entry.Error); len(chain) > 1 {
+			buf.WriteString(" error_chain=[")
+			for i, cause := range chain {
+				if i > 0 {
+					buf.WriteString(" | ")
+				}
+				f.writeError(buf, cause)
+			}
+			buf.WriteByte(']')
+		}
 		if f.EnableColors {
 			buf.Write(ResetColorBytes)
 		}
 	}
 
-	if len(entry.Fields) > 0 {
+	if len(entry.Fields) > 0 || len(entry.TypedFields) > 0 {
 		buf.WriteByte(' ')
-		f.formatFields(buf, entry.Fields)
+		f.formatFields(buf, entry)
 	}
 	if len(entry.KeyVals) > 0 {
 		buf.WriteByte(' ')
@@ -292,13 +312,13 @@ func (f *TextFormatter) formatKeyVals(buf *bytes.Buffer, keyvals [][]byte) {
 		if f.EnableColors {
 			buf.Write(fieldKeyColorBytes)
 		}
-		buf.Write(keyvals[i]) // key
+		f.writeText(buf, keyvals[i]) // key
 		buf.WriteByte('=')
 		if f.EnableColors {
 			buf.Write(fieldValueColorBytes)
 		}
 		if i+1 < len(keyvals) {
-			buf.Write(keyvals[i+1]) // value
+			f.writeText(buf, keyvals[i+1]) // value
 		}
 		if f.EnableColors {
 			buf.Write(ResetColorBytes)
@@ -311,41 +331,39 @@ func (f *TextFormatter) formatKeyVals(buf *bytes.Buffer, keyvals [][]byte) {
 	}
 }
 
-func (f *TextFormatter) formatFields(buf *bytes.Buffer, fields map[string][]byte) {
+// formatFields writes the map and typed fields of the entry in insertion
+// order, or sorted by key with SortFields, after those in CustomFieldOrder
+func (f *TextFormatter) formatFields(buf *bytes.Buffer, entry *core.LogEntry) {
 	if f.EnableColors {
 		buf.Write(fieldsWrapperColorBytes)
 	}
 	buf.WriteByte('{')
 
-	// Use natural map order - avoid extra slice allocation where possible
-	orderedKeys := make([]string, 0, len(fields))
-	for k := range fields {
-		orderedKeys = append(orderedKeys, k)
-	}
-
-	for i, k := range orderedKeys {
-		v := fields[k]
+	var stack [maxOrderedFields]fieldRef
+	for i, ref := range orderFields(stack[:0], entry, f.CustomFieldOrder, f.SortFields) {
 		if i > 0 {
 			buf.WriteByte(' ')
 		}
+		if ref.typed >= 0 {
+			f.formatTypedField(buf, &entry.TypedFields[ref.typed])
+			continue
+		}
 
 		if f.EnableColors {
 			buf.Write(fieldKeyColorBytes)
 		}
-		// to
-		buf.Write(core.StringToBytes(k))
+		f.writeText(buf, core.StringToBytes(ref.key))
 		buf.WriteByte('=')
 		if f.EnableColors {
 			buf.Write(fieldValueColorBytes)
 		}
 
 		// For byte fields, apply masking if needed
-		if f.MaskSensitiveData && f.isSensitiveField(k) {
+		if f.MaskSensitiveData && f.isSensitiveField(ref.key) {
 			// Use byte slice for mask value to avoid string allocation
 			buf.Write(f.MaskStringBytes) // Use pre-converted byte slice
 		} else {
-			// Directly append the byte value
-			buf.Write(v)
+			f.writeText(buf, entry.Fields[ref.key])
 		}
 	}
 
@@ -358,6 +376,28 @@ func (f *TextFormatter) formatFields(buf *bytes.Buffer, fields map[string][]byte
 	}
 }
 
+// formatTypedField writes a typed field as key=value without intermediate strings
+func (f *TextFormatter) formatTypedField(buf *bytes.Buffer, field *core.Field) {
+	if f.EnableColors {
+		buf.Write(fieldKeyColorBytes)
+	}
+	f.writeText(buf, core.StringToBytes(field.Key))
+	buf.WriteByte('=')
+	if f.EnableColors {
+		buf.Write(fieldValueColorBytes)
+	}
+
+	if f.MaskSensitiveData && f.isSensitiveField(field.Key) {
+		buf.Write(f.MaskStringBytes)
+		return
+	}
+
+	tmp := util.GetSmallBuf()
+	value := field.AppendText(tmp[:0])
+	f.writeText(buf, value)
+	util.PutSmallBuf(value)
+}
+
 func (f *TextFormatter) formatTags(buf *bytes.Buffer, tags []string) {
 	if f.EnableColors {
 		buf.Write(tagsColorBytes)
@@ -367,7 +407,7 @@ func (f *TextFormatter) formatTags(buf *bytes.Buffer, tags []string) {
 		if i > 0 {
 			buf.WriteByte(',')
 		}
-		buf.Write(core.StringToBytes(tag)) // Use zero-allocation string to byte conversion
+		f.writeText(buf, core.StringToBytes(tag))
 	}
 	buf.WriteByte(']')
 	if f.EnableColors {
@@ -384,7 +424,7 @@ func (f *TextFormatter) formatTagsBytes(buf *bytes.Buffer, tags [][]byte) {
 		if i > 0 {
 			buf.WriteByte(',')
 		}
-		buf.Write(tag) // tag is already a byte slice
+		f.writeText(buf, tag)
 	}
 	buf.WriteByte(']')
 	if f.EnableColors {
@@ -397,27 +437,45 @@ func (f *TextFormatter) formatMetrics(buf *bytes.Buffer, metrics map[string]floa
 		buf.Write(metricsColorBytes)
 	}
 	buf.WriteByte('<')
-	first := true
-	for k, v := range metrics {
-		if !first {
+	// Use pooled byte slice for AppendFloat
+	floatBuf := util.GetSmallBuf()
+	for i, k := range sortedMetricNames(metrics) {
+		if i > 0 {
 			buf.WriteByte(' ')
 		}
-		first = false
-		buf.Write(core.StringToBytes(k)) // Use zero-allocation string to byte conversion
+		f.writeText(buf, core.StringToBytes(k))
 		buf.WriteByte('=')
-
-		// Use pooled byte slice for AppendFloat
-		floatBuf := util.GetSmallBuf()
-		defer util.PutSmallBuf(floatBuf)
-		floatBytes := strconv.AppendFloat(floatBuf[:0], v, 'f', 2, 64) // at
-		buf.Write(floatBytes)
+		floatBuf = strconv.AppendFloat(floatBuf[:0], metrics[k], 'f', 2, 64)
+		buf.Write(floatBuf)
 	}
+	util.PutSmallBuf(floatBuf)
 	buf.WriteByte('>')
 	if f.EnableColors {
 		buf.Write(ResetColorBytes)
 	}
 }
 
+// writeText writes logged data, escaped when Sanitize is set
+func (f *TextFormatter) writeText(buf *bytes.Buffer, text []byte) {
+	if f.Sanitize {
+		writeSanitized(buf, text, nil)
+		return
+	}
+	buf.Write(text)
+}
+
+// writeError writes an error message, escaped when Sanitize is set
+func (f *TextFormatter) writeError(buf *bytes.Buffer, err error) {
+	if !f.Sanitize {
+		core.WriteError(buf, err) // Zero-allocation append when the error supports it
+		return
+	}
+	msg := util.GetBuffer()
+	core.WriteError(msg, err)
+	writeSanitized(buf, msg.Bytes(), nil)
+	util.PutBuffer(msg)
+}
+
 // --- Helper functions ---
 
 // manualFormatTimestamp formats timestamp manually to avoid allocation
@@ -2,6 +2,9 @@ package formatter
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -312,6 +315,9 @@ func TestTextFormatterWithCustomFieldOrder(t *testing.T) {
 	if len(output) == 0 {
 		t.Error("TextFormatter.Format with custom field order produced empty output")
 	}
+	if !strings.Contains(output, "{field2=value2 field1=value1 field3=value3}") {
+		t.Errorf("Expected custom-ordered fields first, got %q", output)
+	}
 }
 
 // TestTextFormatterWithFieldTransformers tests TextFormatter with field transformers
@@ -536,7 +542,7 @@ func TestTextFormatterFormatFields(t *testing.T) {
 		"float_field":  []byte("3.14"),
 	}
 
-	tf.formatFields(buf, fields)
+	tf.formatFields(buf, &core.LogEntry{Fields: fields})
 
 	output := buf.String()
 	if len(output) == 0 {
@@ -627,3 +633,77 @@ func TestTextFormatterManualFormatTimestamp(t *testing.T) {
 		t.Error("manualFormatTimestamp produced empty output")
 	}
 }
+
+// TestTextFormatterTypedFields tests that typed fields are rendered as key=value pairs
+func TestTextFormatterTypedFields(t *testing.T) {
+	tf := NewText()
+
+	entry := core.GetEntryFromPool()
+	defer core.PutEntryToPool(entry)
+
+	entry.Timestamp = time.Now()
+	entry.Level = core.INFO
+	entry.Message = []byte("typed")
+	entry.TypedFields = []core.Field{
+		{Key: "n", Type: core.Int64Field, Int: 5},
+		{Key: "d", Type: core.DurationField, Int: int64(2 * time.Second)},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tf.Format(buf, entry); err != nil {
+		t.Fatalf("TextFormatter.Format returned error: %v", err)
+	}
+
+	output := buf.String()
+	if !bytes.Contains(buf.Bytes(), []byte("n=5")) || !bytes.Contains(buf.Bytes(), []byte("d=2s")) {
+		t.Errorf("Expected typed fields in output, got %s", output)
+	}
+}
+
+// TestTextFormatterErrorChain tests the error type and cause chain in text output
+func TestTextFormatterErrorChain(t *testing.T) {
+	tf := NewText()
+
+	entry := core.GetEntryFromPool()
+	defer core.PutEntryToPool(entry)
+
+	entry.Timestamp = time.Now()
+	entry.Level = core.ERROR
+	entry.Message = []byte("failed")
+	entry.Error = fmt.Errorf("query: %w", errors.New("timeout"))
+
+	buf := &bytes.Buffer{}
+	if err := tf.Format(buf, entry); err != nil {
+		t.Fatalf("TextFormatter.Format returned error: %v", err)
+	}
+
+	want := "error=query: timeout error_type=*fmt.wrapError error_chain=[query: timeout | timeout]"
+	if !bytes.Contains(buf.Bytes(), []byte(want)) {
+		t.Errorf("Expected %q in output, got %s", want, buf.String())
+	}
+}
+
+// TestTextFormatterTagsAndMetrics tests that tags and sorted metrics follow the fields
+func TestTextFormatterTagsAndMetrics(t *testing.T) {
+	tf := NewText()
+
+	entry := core.GetEntryFromPool()
+	defer core.PutEntryToPool(entry)
+
+	entry.Timestamp = time.Now()
+	entry.Level = core.INFO
+	entry.Message = []byte("invoice sent")
+	entry.Tags = append(entry.Tags, []byte("billing"))
+	entry.CustomMetrics["latency_ms"] = 42
+	entry.CustomMetrics["amount"] = 12.5
+
+	buf := &bytes.Buffer{}
+	if err := tf.Format(buf, entry); err != nil {
+		t.Fatalf("TextFormatter.Format returned error: %v", err)
+	}
+
+	want := "[billing] <amount=12.50 latency_ms=42.00>"
+	if !bytes.Contains(buf.Bytes(), []byte(want)) {
+		t.Errorf("Expected %q in output, got %s", want, buf.String())
+	}
+}
@@ -92,6 +92,17 @@ func (h *FileHook) Fire(entry *core.LogEntry) error {
 	return nil
 }
 
+// Sync commits the error file to stable storage.
+func (h *FileHook) Sync() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.file != nil {
+		return h.file.Sync()
+	}
+	return nil
+}
+
 // Close closes the underlying file writer.
 func (h *FileHook) Close() error {
 	if h.file != nil {
@@ -310,3 +310,21 @@ func TestFileHookWithDifferentFormatters(t *testing.T) {
 		t.Error("Default formatter should be JSONFormatter")
 	}
 }
+
+// TestFileHookSync tests syncing the error file
+func TestFileHookSync(t *testing.T) {
+	tempFile := "test_sync_hook.log"
+	hook, err := NewFileHook(tempFile)
+	if err != nil {
+		t.Fatalf("NewFileHook failed: %v", err)
+	}
+	defer os.Remove(tempFile)
+	defer hook.Close()
+
+	if err := hook.Sync(); err != nil {
+		t.Errorf("Sync returned error: %v", err)
+	}
+	if err := (&FileHook{}).Sync(); err != nil {
+		t.Errorf("Sync with nil file returned error: %v", err)
+	}
+}
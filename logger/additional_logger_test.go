@@ -129,12 +129,22 @@ func TestLoggerWithHooks(t *testing.T) {
 		FlushInterval: 10 * time.Millisecond, // Shorter interval for test
 	})
 	defer logger.Close()
+	fired := 0
+	logger.AddHook(&fieldHook{fire: func(*core.LogEntry) { fired++ }})
 
 	logger.Info("message with potential hooks")
+	logger.LogZ(context.Background(), core.INFO, []byte("zero message with hooks"))
 
+	// The buffered writer only flushes on its interval unless synced
+	if err := logger.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
 	if !strings.Contains(buf.String(), "message with potential hooks") {
 		t.Error("Message should be in output")
 	}
+	if fired != 2 {
+		t.Errorf("Expected hooks to fire for every entry, fired %d times", fired)
+	}
 }
 
 // TestLoggerBufferedWriting tests buffered writing functionality
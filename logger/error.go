@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/Lunar-Chipter/mire/core"
+)
+
+// WithError creates a new logger that attaches err to every entry.
+// Formatters render the error message, its type and its cause chain.
+func (l *Logger) WithError(err error) *Logger {
+	if err == nil {
+		return l
+	}
+	newLogger := l.clone()
+	newLogger.err = err
+	return newLogger
+}
+
+// ErrorE logs an error message with the error attached and optional typed fields
+func (l *Logger) ErrorE(ctx context.Context, err error, msg string, fields ...Field) {
+	l.logTyped(ctx, core.ERROR, core.StringToBytes(msg), err, fields)
+}
+
+// attachError sets the entry error. With IncludeStackTrace, a stack trace carried by
+// the error or one of its causes replaces the stack of the logging call site.
+func (l *Logger) attachError(entry *core.LogEntry, err error) {
+	entry.Error = err
+	if !l.Config.IncludeStackTrace {
+		return
+	}
+	if stack := core.ErrorStack(err); stack != nil {
+		if entry.StackTraceBufPtr != nil {
+			core.PutBuffer(entry.StackTraceBufPtr)
+			entry.StackTraceBufPtr = nil
+		}
+		entry.StackTrace = stack
+	}
+}
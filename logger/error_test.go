@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/formatter"
+)
+
+// stackError is an error carrying its own stack trace
+type stackError struct {
+	msg string
+}
+
+func (e *stackError) Error() string      { return e.msg }
+func (e *stackError) StackTrace() []byte { return []byte("origin.go:42") }
+
+// newErrorTestLogger creates a JSON logger that includes stack traces
+func newErrorTestLogger(async bool) (*Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	jf := formatter.NewJSON()
+	jf.IncludeStackTrace = true
+	l := New(LoggerConfig{
+		Level:             core.INFO,
+		Output:            &buf,
+		Formatter:         jf,
+		IncludeStackTrace: true,
+		StackTraceDepth:   5,
+		AsyncMode:         async,
+		WorkerCount:       1,
+		ChannelSize:       10,
+	})
+	return l, &buf
+}
+
+// decodeErrorLine decodes a JSON log line
+func decodeErrorLine(t *testing.T, line []byte) map[string]interface{} {
+	t.Helper()
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(line, &decoded); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, line)
+	}
+	return decoded
+}
+
+// TestLoggerErrorE tests logging an error with its cause chain
+func TestLoggerErrorE(t *testing.T) {
+	l, buf := newErrorTestLogger(false)
+	defer l.Close()
+
+	var hooked error
+	l.AddHook(&fieldHook{fire: func(entry *core.LogEntry) { hooked = entry.Error }})
+
+	err := fmt.Errorf("load user: %w", errors.New("connection refused"))
+	l.ErrorE(context.Background(), err, "request failed", Int("attempt", 3))
+
+	decoded := decodeErrorLine(t, buf.Bytes())
+	if decoded["error"] != "load user: connection refused" || decoded["error_type"] != "*fmt.wrapError" {
+		t.Errorf("Unexpected error members: %s", buf.String())
+	}
+	chain, _ := decoded["error_chain"].([]interface{})
+	if len(chain) != 2 || chain[1] != "connection refused" {
+		t.Errorf("Unexpected error chain: %v", decoded["error_chain"])
+	}
+	if hooked != err {
+		t.Errorf("Expected hooks to see the error, got %v", hooked)
+	}
+}
+
+// TestLoggerErrorStackPreferred tests that stacks carried by errors replace the call site stack
+func TestLoggerErrorStackPreferred(t *testing.T) {
+	l, buf := newErrorTestLogger(false)
+	defer l.Close()
+
+	l.ErrorE(context.Background(), fmt.Errorf("wrapped: %w", &stackError{msg: "cause"}), "failed")
+
+	decoded := decodeErrorLine(t, buf.Bytes())
+	if decoded["stack_trace"] != "origin.go:42" {
+		t.Errorf("Expected the error's stack trace, got %v", decoded["stack_trace"])
+	}
+}
+
+// TestLoggerWithError tests attaching an error to every entry of a logger
+func TestLoggerWithError(t *testing.T) {
+	l, buf := newErrorTestLogger(false)
+	defer l.Close()
+
+	if l.WithError(nil) != l {
+		t.Error("WithError(nil) should return the same logger")
+	}
+
+	errLog := l.WithError(errors.New("disk full"))
+	errLog.Warn("retrying")
+	if decoded := decodeErrorLine(t, buf.Bytes()); decoded["error"] != "disk full" {
+		t.Errorf("Expected error on entry, got %s", buf.String())
+	}
+
+	buf.Reset()
+	l.Warn("unrelated")
+	if decoded := decodeErrorLine(t, buf.Bytes()); decoded["error"] != nil {
+		t.Errorf("Parent logger should not carry the error, got %s", buf.String())
+	}
+}
+
+// TestLoggerErrorEAsync tests that the error reaches the entry through the async workers
+func TestLoggerErrorEAsync(t *testing.T) {
+	l, buf := newErrorTestLogger(true)
+
+	l.ErrorE(context.Background(), errors.New("async failure"), "failed")
+	l.Close()
+
+	if decoded := decodeErrorLine(t, buf.Bytes()); decoded["error"] != "async failure" {
+		t.Errorf("Expected error on async entry, got %s", buf.String())
+	}
+}
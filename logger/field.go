@@ -0,0 +1,235 @@
+package logger
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/Lunar-Chipter/mire/core"
+)
+
+// Field is a strongly typed key-value pair for the *T logging methods.
+// Formatters encode fields natively, so numbers and booleans stay unquoted in JSON.
+type Field = core.Field
+
+// String constructs a field with a string value
+func String(key, value string) Field {
+	return Field{Key: key, Type: core.StringField, Str: value}
+}
+
+// Int constructs a field with an int value
+func Int(key string, value int) Field {
+	return Field{Key: key, Type: core.Int64Field, Int: int64(value)}
+}
+
+// Int64 constructs a field with an int64 value
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Type: core.Int64Field, Int: value}
+}
+
+// Uint64 constructs a field with a uint64 value
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, Type: core.Uint64Field, Int: int64(value)}
+}
+
+// Float64 constructs a field with a float64 value
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Type: core.Float64Field, Int: int64(math.Float64bits(value))}
+}
+
+// Bool constructs a field with a bool value
+func Bool(key string, value bool) Field {
+	var i int64
+	if value {
+		i = 1
+	}
+	return Field{Key: key, Type: core.BoolField, Int: i}
+}
+
+// Duration constructs a field with a duration value.
+// Text output uses time.Duration.String, JSON output uses nanoseconds.
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Type: core.DurationField, Int: int64(value)}
+}
+
+// Time constructs a field with a time value formatted as RFC 3339
+func Time(key string, value time.Time) Field {
+	if ns := value.UnixNano(); time.Unix(0, ns).Equal(value) {
+		return Field{Key: key, Type: core.TimeField, Int: ns, Iface: value.Location()}
+	}
+	// Out of the range of Unix nanoseconds: keep the time as is
+	return Field{Key: key, Type: core.TimeField, Iface: value}
+}
+
+// Err constructs a field with the key "error"
+func Err(err error) Field {
+	if err == nil {
+		return Field{Key: "error", Type: core.ErrorField}
+	}
+	return Field{Key: "error", Type: core.ErrorField, Iface: err}
+}
+
+// Bytes constructs a field with a []byte value written as text
+func Bytes(key string, value []byte) Field {
+	return Field{Key: key, Type: core.BytesField, Iface: value}
+}
+
+// Stringer constructs a field whose String method is called only when the entry is formatted
+func Stringer(key string, value core.Stringer) Field {
+	if value == nil {
+		return Field{Key: key, Type: core.StringerField}
+	}
+	return Field{Key: key, Type: core.StringerField, Iface: value}
+}
+
+// Any constructs a field with an arbitrary value.
+// Common types map to their typed constructor; anything else is encoded as JSON.
+func Any(key string, value interface{}) Field {
+	switch v := value.(type) {
+	case string:
+		return String(key, v)
+	case int:
+		return Int(key, v)
+	case int8:
+		return Int64(key, int64(v))
+	case int16:
+		return Int64(key, int64(v))
+	case int32:
+		return Int64(key, int64(v))
+	case int64:
+		return Int64(key, v)
+	case uint:
+		return Uint64(key, uint64(v))
+	case uint8:
+		return Uint64(key, uint64(v))
+	case uint16:
+		return Uint64(key, uint64(v))
+	case uint32:
+		return Uint64(key, uint64(v))
+	case uint64:
+		return Uint64(key, v)
+	case float32:
+		return Float64(key, float64(v))
+	case float64:
+		return Float64(key, v)
+	case bool:
+		return Bool(key, v)
+	case time.Duration:
+		return Duration(key, v)
+	case time.Time:
+		return Time(key, v)
+	case []byte:
+		return Bytes(key, v)
+	case error:
+		return Field{Key: key, Type: core.ErrorField, Iface: v}
+	case core.Stringer:
+		return Stringer(key, v)
+	default:
+		return Field{Key: key, Type: core.AnyField, Iface: value}
+	}
+}
+
+// logTyped handles logging with typed fields and an optional error
+func (l *Logger) logTyped(ctx context.Context, level core.Level, message []byte, err error, fields []Field) {
+	// Early return if logger is closed
+	if l.closed.Load() {
+		return
+	}
+
+	if level < l.minLevel() {
+		return
+	}
+
+	// Sampling if enabled
+	if l.sampler != nil && !l.sampler.ShouldLog() {
+		return
+	}
+
+	if err == nil {
+		err = l.err
+	}
+
+	if l.queueAsync(level) {
+		l.asyncLogger.LogFieldsFor(l.asyncProc, level, message, l.asyncContext(ctx), err, fields...)
+		if l.metrics != nil {
+			l.metrics.recordAsync(l.asyncLogger)
+		}
+		return
+	}
+
+	l.writeTyped(ctx, level, message, err, fields)
+}
+
+// writeTyped writes a log entry carrying typed fields and an optional error
+func (l *Logger) writeTyped(ctx context.Context, level core.Level, message []byte, err error, fields []Field) {
+	entry := l.buildEntryByte(ctx, level, message, nil)
+	// Call-site fields take precedence over logger and context fields of the same key
+	if len(entry.Fields) > 0 {
+		for i := range fields {
+			entry.DeleteField(fields[i].Key)
+		}
+	}
+	entry.TypedFields = fields
+	applyOperationDuration(ctx, entry)
+	l.applySlogRecord(ctx, entry)
+	if err != nil {
+		l.attachError(entry, err)
+	}
+
+	if !l.writeEntry(entry) {
+		core.PutEntryToPool(entry)
+		return
+	}
+
+	l.runHooks(entry)
+
+	// must be done after hooks and writing, but before PutEntryToPool
+	l.handleLevelActions(level, entry)
+
+	core.PutEntryToPool(entry)
+}
+
+// LogT logs with context and typed fields
+func (l *Logger) LogT(ctx context.Context, level core.Level, msg string, fields ...Field) {
+	l.logTyped(ctx, level, core.StringToBytes(msg), nil, fields)
+}
+
+// TraceT logs a trace message with typed fields
+func (l *Logger) TraceT(msg string, fields ...Field) {
+	l.logTyped(context.Background(), core.TRACE, core.StringToBytes(msg), nil, fields)
+}
+
+// DebugT logs a debug message with typed fields
+func (l *Logger) DebugT(msg string, fields ...Field) {
+	l.logTyped(context.Background(), core.DEBUG, core.StringToBytes(msg), nil, fields)
+}
+
+// InfoT logs an info message with typed fields
+func (l *Logger) InfoT(msg string, fields ...Field) {
+	l.logTyped(context.Background(), core.INFO, core.StringToBytes(msg), nil, fields)
+}
+
+// NoticeT logs a notice message with typed fields
+func (l *Logger) NoticeT(msg string, fields ...Field) {
+	l.logTyped(context.Background(), core.NOTICE, core.StringToBytes(msg), nil, fields)
+}
+
+// WarnT logs a warning message with typed fields
+func (l *Logger) WarnT(msg string, fields ...Field) {
+	l.logTyped(context.Background(), core.WARN, core.StringToBytes(msg), nil, fields)
+}
+
+// ErrorT logs an error message with typed fields
+func (l *Logger) ErrorT(msg string, fields ...Field) {
+	l.logTyped(context.Background(), core.ERROR, core.StringToBytes(msg), nil, fields)
+}
+
+// FatalT logs a fatal message with typed fields and exits
+func (l *Logger) FatalT(msg string, fields ...Field) {
+	l.logTyped(context.Background(), core.FATAL, core.StringToBytes(msg), nil, fields)
+}
+
+// PanicT logs a panic message with typed fields and exits
+func (l *Logger) PanicT(msg string, fields ...Field) {
+	l.logTyped(context.Background(), core.PANIC, core.StringToBytes(msg), nil, fields)
+}
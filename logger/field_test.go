@@ -0,0 +1,218 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/formatter"
+)
+
+// TestFieldConstructors tests the type chosen by each constructor
+func TestFieldConstructors(t *testing.T) {
+	tests := []struct {
+		field Field
+		want  core.FieldType
+	}{
+		{String("k", "v"), core.StringField},
+		{Int("k", 1), core.Int64Field},
+		{Int64("k", 1), core.Int64Field},
+		{Uint64("k", 1), core.Uint64Field},
+		{Float64("k", 1.5), core.Float64Field},
+		{Bool("k", true), core.BoolField},
+		{Duration("k", time.Second), core.DurationField},
+		{Time("k", time.Now()), core.TimeField},
+		{Err(errors.New("e")), core.ErrorField},
+		{Bytes("k", []byte("v")), core.BytesField},
+		{Stringer("k", time.Second), core.StringerField},
+		{Any("k", struct{}{}), core.AnyField},
+		{Any("k", int32(3)), core.Int64Field},
+		{Any("k", uint8(3)), core.Uint64Field},
+		{Any("k", float32(3)), core.Float64Field},
+		{Any("k", time.Minute), core.DurationField},
+		{Any("k", errors.New("e")), core.ErrorField},
+	}
+	for _, tt := range tests {
+		if tt.field.Type != tt.want {
+			t.Errorf("Field %+v: expected type %d, got %d", tt.field, tt.want, tt.field.Type)
+		}
+	}
+
+	if v := Float64("k", 1.5).Value(); v != 1.5 {
+		t.Errorf("Expected Float64 round trip 1.5, got %v", v)
+	}
+	if v := Uint64("k", 1<<63).Value(); v != uint64(1<<63) {
+		t.Errorf("Expected Uint64 round trip, got %v", v)
+	}
+	if f := Err(nil); f.Iface != nil || f.Key != "error" {
+		t.Errorf("Unexpected nil error field %+v", f)
+	}
+}
+
+// TestTimeFieldRange tests that times outside the range of Unix nanoseconds,
+// the zero time included, are kept as is
+func TestTimeFieldRange(t *testing.T) {
+	tests := []struct {
+		value time.Time
+		want  string
+	}{
+		{time.Time{}, "0001-01-01T00:00:00Z"},
+		{time.Date(1600, 1, 2, 3, 4, 5, 6, time.UTC), "1600-01-02T03:04:05.000000006Z"},
+		{time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC), "3000-01-01T00:00:00Z"},
+		{time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), "2024-03-01T12:00:00Z"},
+	}
+	for _, tt := range tests {
+		f := Time("t", tt.value)
+		if got := string(f.AppendText(nil)); got != tt.want {
+			t.Errorf("Time(%v): expected %q, got %q", tt.value, tt.want, got)
+		}
+		if got := f.Time(); !got.Equal(tt.value) {
+			t.Errorf("Time(%v): round trip gave %v", tt.value, got)
+		}
+	}
+}
+
+// TestLoggerTypedJSON tests that typed fields produce correctly typed JSON
+func TestLoggerTypedJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(LoggerConfig{Level: core.INFO, Output: &buf, Formatter: formatter.NewJSON()})
+	defer l.Close()
+
+	l.InfoT("request served",
+		String("path", "/users"),
+		Int("status", 200),
+		Float64("ratio", 0.5),
+		Bool("cached", false),
+		Duration("latency", 1500*time.Microsecond),
+		Err(nil),
+		Any("tags", []string{"a", "b"}))
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	fields, ok := decoded["fields"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected fields object, got %s", buf.String())
+	}
+
+	if fields["path"] != "/users" {
+		t.Errorf("Expected path /users, got %v", fields["path"])
+	}
+	if fields["status"] != float64(200) {
+		t.Errorf("Expected numeric status, got %#v", fields["status"])
+	}
+	if fields["ratio"] != 0.5 {
+		t.Errorf("Expected numeric ratio, got %#v", fields["ratio"])
+	}
+	if fields["cached"] != false {
+		t.Errorf("Expected boolean cached, got %#v", fields["cached"])
+	}
+	if fields["latency"] != float64(1500000) {
+		t.Errorf("Expected latency in nanoseconds, got %#v", fields["latency"])
+	}
+	if v, exists := fields["error"]; !exists || v != nil {
+		t.Errorf("Expected null error, got %#v", v)
+	}
+	if tags, ok := fields["tags"].([]interface{}); !ok || len(tags) != 2 {
+		t.Errorf("Expected tags array, got %#v", fields["tags"])
+	}
+}
+
+// TestLoggerTypedLevelFilter tests that typed logging honours the configured level
+func TestLoggerTypedLevelFilter(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(LoggerConfig{Level: core.WARN, Output: &buf, Formatter: &formatter.TextFormatter{}})
+	defer l.Close()
+
+	l.InfoT("filtered", Int("n", 1))
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output below WARN, got %s", buf.String())
+	}
+
+	l.WarnT("kept", Int("n", 2))
+	if !strings.Contains(buf.String(), "n=2") {
+		t.Errorf("Expected typed field in output, got %s", buf.String())
+	}
+}
+
+// TestLoggerTypedHooks tests that hooks see the typed fields of an entry
+func TestLoggerTypedHooks(t *testing.T) {
+	l, _, _ := newSlogTestLogger(core.INFO)
+	defer l.Close()
+
+	var seen []Field
+	l.AddHook(&fieldHook{fire: func(entry *core.LogEntry) {
+		seen = append(seen, entry.TypedFields...)
+	}})
+
+	l.ErrorT("failed", Err(errors.New("boom")))
+	if len(seen) != 1 || seen[0].Key != "error" {
+		t.Errorf("Expected hook to see the error field, got %+v", seen)
+	}
+}
+
+// TestLoggerTypedAsync tests typed logging through the async workers
+func TestLoggerTypedAsync(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(LoggerConfig{
+		Level:       core.INFO,
+		Output:      &buf,
+		Formatter:   formatter.NewJSON(),
+		AsyncMode:   true,
+		WorkerCount: 1,
+		ChannelSize: 10,
+	})
+
+	l.InfoT("async", Int64("n", 42), Bytes("raw", []byte("data")))
+	l.Close()
+
+	output := buf.String()
+	if !strings.Contains(output, `"n":42`) || !strings.Contains(output, `"raw":"data"`) {
+		t.Errorf("Expected typed fields in async output, got %s", output)
+	}
+}
+
+// userID dereferences its receiver in String
+type userID struct{ id string }
+
+func (u *userID) String() string { return u.id }
+
+// TestLoggerTypedAsyncNilStringer tests that a typed nil Stringer is written
+// as <nil> instead of crashing the async worker
+func TestLoggerTypedAsyncNilStringer(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(LoggerConfig{
+		Level:       core.INFO,
+		Output:      &buf,
+		Formatter:   formatter.NewJSON(),
+		AsyncMode:   true,
+		WorkerCount: 1,
+		ChannelSize: 10,
+	})
+
+	l.InfoT("first", Stringer("user", (*userID)(nil)))
+	l.InfoT("second", Stringer("user", &userID{"u1"}))
+	l.Close()
+
+	output := buf.String()
+	if !strings.Contains(output, `"user":"<nil>"`) || !strings.Contains(output, `"user":"u1"`) {
+		t.Errorf("Expected both entries, the first with a <nil> user, got %s", output)
+	}
+}
+
+// fieldHook calls fire for every entry
+type fieldHook struct {
+	fire func(*core.LogEntry)
+}
+
+func (h *fieldHook) Fire(entry *core.LogEntry) error {
+	h.fire(entry)
+	return nil
+}
+
+func (h *fieldHook) Close() error { return nil }
@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/Lunar-Chipter/mire/core"
+)
+
+// maxLevelRequestSize bounds the body accepted by the level handler
+const maxLevelRequestSize = 1024
+
+// SetLevel changes the base level at runtime.
+// The change applies to this logger and every logger cloned from it or its parent;
+// named loggers matching a rule in the LevelRegistry keep the rule's level.
+func (l *Logger) SetLevel(level core.Level) {
+	l.level.SetLevel(level)
+}
+
+// GetLevel returns the level this logger currently filters on
+func (l *Logger) GetLevel() core.Level {
+	return l.minLevel()
+}
+
+// levelPayload is the JSON body used by the level handler
+type levelPayload struct {
+	Level string `json:"level"`
+}
+
+// LevelHandler returns an http.Handler that reports the current level on GET and
+// changes it on PUT. A PUT body is either {"level":"DEBUG"} or the bare level name;
+// a "level" query parameter is also accepted. Names are parsed with core.ParseLevel.
+func (l *Logger) LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			name, err := readLevelRequest(r)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			level, err := core.ParseLevel(name)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			l.SetLevel(level)
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(levelPayload{Level: l.GetLevel().String()})
+	})
+}
+
+// readLevelRequest extracts the requested level name from a PUT request
+func readLevelRequest(r *http.Request) (string, error) {
+	if name := r.URL.Query().Get("level"); name != "" {
+		return name, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxLevelRequestSize))
+	if err != nil {
+		return "", err
+	}
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '{' {
+		var payload levelPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return "", err
+		}
+		return payload.Level, nil
+	}
+	return string(body), nil
+}
@@ -0,0 +1,143 @@
+package logger
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/formatter"
+)
+
+// newLevelTestLogger creates a text logger writing into a buffer
+func newLevelTestLogger(level core.Level) (*Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	l := New(LoggerConfig{Level: level, Output: &buf, Formatter: &formatter.TextFormatter{}})
+	return l, &buf
+}
+
+// TestLoggerSetLevel tests changing the level at runtime
+func TestLoggerSetLevel(t *testing.T) {
+	l, buf := newLevelTestLogger(core.INFO)
+	defer l.Close()
+
+	l.Debug("hidden")
+	if buf.Len() != 0 {
+		t.Fatalf("Expected DEBUG to be filtered, got %s", buf.String())
+	}
+
+	l.SetLevel(core.DEBUG)
+	if l.GetLevel() != core.DEBUG {
+		t.Errorf("Expected DEBUG, got %v", l.GetLevel())
+	}
+	l.Debug("visible")
+	if !strings.Contains(buf.String(), "visible") {
+		t.Errorf("Expected DEBUG output after SetLevel, got %s", buf.String())
+	}
+}
+
+// TestLoggerSetLevelSharedWithClones tests that clones follow level changes in both directions
+func TestLoggerSetLevelSharedWithClones(t *testing.T) {
+	l, _ := newLevelTestLogger(core.INFO)
+	defer l.Close()
+
+	child := l.WithFields(map[string]interface{}{"component": "db"})
+	l.SetLevel(core.ERROR)
+	if child.GetLevel() != core.ERROR {
+		t.Errorf("Expected clone to see ERROR, got %v", child.GetLevel())
+	}
+
+	child.SetLevel(core.TRACE)
+	if l.GetLevel() != core.TRACE {
+		t.Errorf("Expected parent to see TRACE, got %v", l.GetLevel())
+	}
+}
+
+// TestLoggerSetLevelConcurrent tests changing the level while logging
+func TestLoggerSetLevelConcurrent(t *testing.T) {
+	l, _ := newLevelTestLogger(core.INFO)
+	defer l.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if i%2 == 0 {
+					l.SetLevel(core.Level(j % 4))
+				} else {
+					l.Info("message")
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+// TestLoggerLevelHandler tests reading and updating the level over HTTP
+func TestLoggerLevelHandler(t *testing.T) {
+	l, _ := newLevelTestLogger(core.INFO)
+	defer l.Close()
+	handler := l.LevelHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/level", nil))
+	if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != `{"level":"INFO"}` {
+		t.Errorf("Unexpected GET response %d %q", rec.Code, rec.Body.String())
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		body   string
+		want   core.Level
+	}{
+		{"json body", "/level", `{"level":"debug"}`, core.DEBUG},
+		{"plain body", "/level", "WARNING\n", core.WARN},
+		{"query", "/level?level=error", "", core.ERROR},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, tt.target, strings.NewReader(tt.body)))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if l.GetLevel() != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, l.GetLevel())
+			}
+		})
+	}
+}
+
+// TestLoggerLevelHandlerErrors tests invalid requests to the level handler
+func TestLoggerLevelHandlerErrors(t *testing.T) {
+	l, _ := newLevelTestLogger(core.INFO)
+	defer l.Close()
+	handler := l.LevelHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/level", strings.NewReader("loud")))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown level, got %d", rec.Code)
+	}
+	if l.GetLevel() != core.INFO {
+		t.Errorf("Level should be unchanged, got %v", l.GetLevel())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/level", strings.NewReader(`{"level":`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for malformed JSON, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/level", nil))
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != "GET, PUT" {
+		t.Errorf("Expected 405 with Allow header, got %d %q", rec.Code, rec.Header().Get("Allow"))
+	}
+}
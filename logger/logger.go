@@ -13,9 +13,11 @@ import (
 
 	"github.com/Lunar-Chipter/mire/config"
 	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/encrypt"
 	"github.com/Lunar-Chipter/mire/formatter"
 	"github.com/Lunar-Chipter/mire/hook"
 	"github.com/Lunar-Chipter/mire/metric"
+	"github.com/Lunar-Chipter/mire/redact"
 	"github.com/Lunar-Chipter/mire/sampler"
 	"github.com/Lunar-Chipter/mire/util"
 	"github.com/Lunar-Chipter/mire/writer"
@@ -26,6 +28,10 @@ const (
 	DEFAULT_CALLER_DEPTH     = 3
 	DEFAULT_BUFFER_SIZE      = 1000
 	DEFAULT_FLUSH_INTERVAL   = 5 * time.Second
+	DEFAULT_SYNC_TIMEOUT     = 5 * time.Second
+	DEFAULT_OVERFLOW_TIMEOUT = 100 * time.Millisecond
+
+	DEFAULT_TRUNCATION_MARKER = "...[truncated]"
 
 	SmallBufferSize  = 512
 	MediumBufferSize = 2048
@@ -34,9 +40,9 @@ const (
 
 // Config holds configuration for the logger
 type LoggerConfig struct {
-	Level                   core.Level                              // Minimum level to log
+	Level                   core.Level                              // Initial minimum level to log (change it at runtime with Logger.SetLevel)
 	UseColors               bool                                    // Use ANSI colors in output
-	Output                  io.Writer                               // Output writer for logs
+	Output                  io.Writer                               // Output writer for logs (defaults to stdout unless Sinks are set)
 	ErrorOutput             io.Writer                               // Output writer for internal logger errors
 	Formatter               formatter.Formatter                     // Formatter to use for log entries
 	ShowCaller              bool                                    // Show caller information (file, line)
@@ -57,11 +63,12 @@ type LoggerConfig struct {
 	EnableRotation         bool                                    // Enable log rotation
 	RotationConfig               *config.RotationConfig                  // Configuration for log rotation
 	ExtractContext          func(context.Context) map[string][]byte // Function to extract fields from context as []byte for zero allocation
+	SpanContextProvider     util.SpanContextProvider                // Source of trace_id, span_id and trace_flags (defaults to util.ContextSpanProvider)
 	Hostname                string                                  // Hostname to include in logs
 	Application             string                                  // Application name to include in logs
 	Version                 string                                  // Application version to include in logs
 	Environment             string                                  // Environment (dev, prod, etc.)
-	MaxFieldSize            int                                     // Maximum size for field values
+	MaxFieldSize            int                                     // Maximum size in bytes of field values, truncation marker included (0 means unlimited)
 	EnableMetrics          bool                                    // Enable metrics collection
 	Collector               metric.Collector                 // Metrics collector to use
 	ErrorHandler            func(error)                             // Function to handle internal logger errors
@@ -74,47 +81,50 @@ type LoggerConfig struct {
 	NoLocking               bool                                    // Disable internal locking (for performance, use with caution)
 	FieldCapacity           int                                     // Pre-allocate map capacity for fields
 	TagCapacity             int                                     // Pre-allocate slice capacity for tags
-	MaxMessageSize          int                                     // Maximum size for log messages
+	MaxMessageSize          int                                     // Maximum size in bytes of log messages, truncation marker included (0 means unlimited)
+	Redactor                *redact.Redactor                        // Redacts sensitive data in messages and values before formatting (nil disables redaction)
+	// FieldPolicy replaces the deprecated SensitiveFields, MaskSensitiveData and MaskValue
+	// of the formatters: it redacts before formatting, so it applies to every formatter and
+	// sink, and takes precedence over them.
+	FieldPolicy             *redact.Policy                          // Redacts values by key in fields, key-value pairs and context values, whatever the formatter (nil disables it)
+	Encryptor               *encrypt.Encryptor                      // Encrypts designated field values after redaction and size limits (nil disables encryption)
+	TruncationMarker        string                                  // Appended to truncated values before their original size (default DEFAULT_TRUNCATION_MARKER)
 	AsyncMode               bool                                    // Enable asynchronous logging
 	ProcessTimeout         time.Duration                           // Timeout for processing log in async worker
 	ChannelSize             int                                     // Buffer size for async log channel
 	WorkerCount             int                                     // Number of async worker goroutines
 	NoTimeout              bool                                    // Disable context timeout per log in async mode
 	ClockInterval           time.Duration                           // Interval for clock (for timestamp optimization)
+	LevelRules              string                                  // Level overrides for named loggers, e.g. "db.*=DEBUG,http=WARN"
+	LevelRulesEnv           string                                  // Environment variable with level rules, applied after LevelRules (e.g. DefaultLevelRulesEnv)
+	SlowOperationThreshold  time.Duration                           // Default threshold below which ended operations are not logged (0 logs every operation)
+	SyncTimeout             time.Duration                           // Deadline for flushing logs before exiting on FATAL or PANIC
+	OverflowPolicy          writer.OverflowPolicy                   // What the async queue and buffered writer do when full (default writer.DropNewest)
+	OverflowTimeout         time.Duration                           // How long writer.BlockWithTimeout waits before dropping
+	OverflowMinLevel        core.Level                              // Lowest level writer.DropBelowLevel never drops (TRACE selects WARN)
+	DropSummaryInterval     time.Duration                           // Interval of "N logs dropped" summary entries (0 disables them)
+	SpillDir                string                                  // Directory of the on-disk queue used when the buffered writer is full or failing (requires BufferSize)
+	SpillMaxBytes           int64                                   // Maximum size of the spill queue (0 means unlimited)
+	Sinks                   []Sink                                  // Additional outputs, each with its own formatter, level and buffering
+	// Deprecated: use FieldPolicy.
 	MaskValue               string                                  // String value to use for masking sensitive data
 }
 
 // validate ensures the logger configuration has sane defaults
 func validate(c *LoggerConfig) {
-	if c.Output == nil {
+	if c.Output == nil && len(c.Sinks) == 0 {
 		c.Output = os.Stdout
 	}
 	if c.ErrorOutput == nil {
 		c.ErrorOutput = os.Stderr
 	}
 	if c.Formatter == nil {
-		// Use default mask string for the default formatter
-		defaultFormatter := &formatter.TextFormatter{TimestampFormat: DEFAULT_TIMESTAMP_FORMAT}
-		if c.MaskValue == "" {
-			defaultFormatter.MaskStringBytes = []byte("[MASKED]") // Default mask
-		} else {
-			defaultFormatter.MaskStringBytes = []byte(c.MaskValue)
-		}
-		c.Formatter = defaultFormatter
-	} else {
-		// If a formatter is provided, check its type and set MaskStringBytes
-		if tf, ok := c.Formatter.(*formatter.TextFormatter); ok {
-			if c.MaskValue == "" {
-				tf.MaskStringBytes = []byte("[MASKED]") // Default mask
-			} else {
-				tf.MaskStringBytes = []byte(c.MaskValue)
-			}
-		} else if jf, ok := c.Formatter.(*formatter.JSONFormatter); ok {
-			if c.MaskValue == "" {
-				jf.MaskStringBytes = []byte("[MASKED]") // Default mask
-			} else {
-				jf.MaskStringBytes = []byte(c.MaskValue)
-			}
+		c.Formatter = &formatter.TextFormatter{TimestampFormat: DEFAULT_TIMESTAMP_FORMAT}
+	}
+	setMaskValue(c.Formatter, c.MaskValue)
+	for i := range c.Sinks {
+		if c.Sinks[i].Formatter != nil {
+			setMaskValue(c.Sinks[i].Formatter, c.MaskValue)
 		}
 	}
 
@@ -127,11 +137,48 @@ func validate(c *LoggerConfig) {
 	if c.TimestampFormat == "" {
 		c.TimestampFormat = DEFAULT_TIMESTAMP_FORMAT
 	}
+	if c.SyncTimeout <= 0 {
+		c.SyncTimeout = DEFAULT_SYNC_TIMEOUT
+	}
+	if c.OverflowTimeout <= 0 {
+		c.OverflowTimeout = DEFAULT_OVERFLOW_TIMEOUT
+	}
+	if c.OverflowMinLevel == core.TRACE {
+		c.OverflowMinLevel = core.WARN
+	}
+	if c.EnableMetrics && c.Collector == nil {
+		c.Collector = metric.NewMetrics()
+	}
+	if c.SpanContextProvider == nil {
+		c.SpanContextProvider = util.ContextSpanProvider
+	}
+	if c.TruncationMarker == "" {
+		c.TruncationMarker = DEFAULT_TRUNCATION_MARKER
+	}
+}
+
+// setMaskValue sets the string that text, JSON and logfmt formatters write in
+// place of the values of their deprecated SensitiveFields
+func setMaskValue(f formatter.Formatter, mask string) {
+	if mask == "" {
+		mask = "[MASKED]" // Default mask
+	}
+	if tf, ok := f.(*formatter.TextFormatter); ok {
+		tf.MaskStringBytes = []byte(mask)
+	} else if jf, ok := f.(*formatter.JSONFormatter); ok {
+		jf.MaskStringBytes = []byte(mask)
+	} else if lf, ok := f.(*formatter.LogfmtFormatter); ok {
+		lf.MaskStringBytes = []byte(mask)
+	}
 }
 
 // Logger is the main logging structure
 type Logger struct {
 	Config           LoggerConfig                            // Configuration for the logger
+	level            *core.AtomicLevel                       // Minimum level to log, shared with clones
+	name             string                                  // Dotted logger name set by Named ("" for the root logger)
+	registry         *LevelRegistry                          // Level overrides for named loggers, shared with clones
+	levelCache       *atomic.Uint64                          // Registry level resolved for name (nil for the root logger)
 	formatter        formatter.Formatter                     // Formatter to use for log entries
 	out              io.Writer                               // Output writer for logs
 	errOut           io.Writer                               // Output writer for internal logger errors
@@ -140,26 +187,35 @@ type Logger struct {
 	hooks            []hook.Hook                             // Hooks to execute for each log entry
 	exitFunc         func(int)                               // Function to call on fatal/panic
 	fields           map[string][]byte                       // Default fields to include in all logs as []byte for zero allocation
+	fieldKeys        []string                                // Keys of fields in the order they were added
 	sampler          *sampler.LogSampler                    // Sampler for log sampling
 	buffer           *writer.Buffered                       // Buffered writer for performance
 	rotation         *writer.Rotator                         // Rotating file writer for log rotation
 	contextExtractor func(context.Context) map[string][]byte // Function to extract fields from context
-	metrics          metric.Collector                        // Metrics collector to use
+	spanProvider     util.SpanContextProvider                // Provider of the active span context
+	err              error                                   // Error attached to every entry by WithError
+	tags             [][]byte                                // Tags added to every entry by WithTags (copy-on-write)
+	customMetrics    map[string]float64                      // Metrics added to every entry by WithMetric (copy-on-write)
+	metrics          *loggerMetrics                          // Pipeline metrics reporter (nil when metrics are disabled)
 	onFatal          func(*core.LogEntry)                    // Function to call when a fatal log occurs
 	onPanic          func(*core.LogEntry)                    // Function to call when a panic log occurs
 	stats            *LoggerStats                            // Statistics for logger
 	asyncLogger      *writer.AsyncLogger                      // Async logger for non-blocking logging
+	asyncProc        *asyncProcessor                          // Hands queued entries back to this logger (nil without async mode)
+	dropSummary      *dropSummary                            // Periodic "N logs dropped" reporter (nil when disabled)
 	errorFileHook    *hook.FileHook                          // Built-in error file hook for ERROR+ levels
+	sinks            []*sinkWriter                           // Additional outputs from LoggerConfig.Sinks, shared with clones
 	closed           *atomic.Bool                            // Flag to indicate if logger is closed
 	pid              int                                     // Process ID
+	meta             entryMeta                               // Hostname, application, version and environment added to entries
 	clock            *util.Clock                             // Clock for timestamp optimization
-	entryPool        *sync.Pool                              // Pool for LogEntry objects
 }
 
 // LoggerStats tracks logger statistics
 type LoggerStats struct {
 	LogCounts    map[core.Level]int64
 	BytesWritten int64
+	Truncations  int64 // Messages and field values cut to MaxMessageSize or MaxFieldSize
 	StartTime    time.Time
 	mu           sync.RWMutex
 }
@@ -181,6 +237,13 @@ func (ls *LoggerStats) Increment(level core.Level, bytes int) {
 	ls.BytesWritten += int64(bytes)
 }
 
+// AddTruncation counts a truncated message or field value
+func (ls *LoggerStats) AddTruncation() {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	ls.Truncations++
+}
+
 // GetStats returns the current statistics
 func (ls *LoggerStats) GetStats() map[string]interface{} {
 	ls.mu.RLock()
@@ -189,6 +252,7 @@ func (ls *LoggerStats) GetStats() map[string]interface{} {
 	stats := make(map[string]interface{})
 	stats["start_time"] = ls.StartTime
 	stats["bytes_written"] = ls.BytesWritten
+	stats["truncations"] = ls.Truncations
 	stats["uptime"] = time.Since(ls.StartTime).String()
 
 	counts := make(map[string]int64)
@@ -200,6 +264,11 @@ func (ls *LoggerStats) GetStats() map[string]interface{} {
 	return stats
 }
 
+// Stats returns the statistics of the logger, shared with its clones
+func (l *Logger) Stats() *LoggerStats {
+	return l.stats
+}
+
 // NewDefaultLogger creates a logger with default configuration
 // This logger is configured with standard settings suitable for most applications
 func NewDefaultLogger() *Logger {
@@ -230,6 +299,8 @@ func NewLogger(config LoggerConfig) *Logger {
 
 	l := &Logger{
 		Config:           config,
+		level:            core.NewAtomicLevel(config.Level),
+		registry:         NewLevelRegistry(),
 		formatter:        config.Formatter,
 		out:              config.Output,
 		errOut:           config.ErrorOutput,
@@ -239,17 +310,24 @@ func NewLogger(config LoggerConfig) *Logger {
 		fields:           make(map[string][]byte),
 		hooks:            config.Hooks, // Initialize hooks from config
 		contextExtractor: config.ExtractContext,
-		metrics:          config.Collector,
+		spanProvider:     config.SpanContextProvider,
 		onFatal:          config.OnFatal,
 		onPanic:          config.OnPanic,
 		stats:            NewLoggerStats(),
 		closed:           &atomic.Bool{},
 		pid:              os.Getpid(),
-		entryPool: &sync.Pool{
-			New: func() interface{} {
-				return &core.LogEntry{}
-			},
-		},
+		meta:             newEntryMeta(&config),
+	}
+
+	if config.LevelRules != "" {
+		if err := l.registry.Parse(config.LevelRules); err != nil {
+			l.handleError(err)
+		}
+	}
+	if config.LevelRulesEnv != "" {
+		if err := l.registry.LoadEnv(config.LevelRulesEnv); err != nil {
+			l.handleError(err)
+		}
 	}
 
 	if config.LogErrors {
@@ -262,6 +340,10 @@ func NewLogger(config LoggerConfig) *Logger {
 		}
 	}
 
+	if config.EnableMetrics {
+		l.metrics = newLoggerMetrics(config.Collector, config.Application, config.Environment)
+	}
+
 	if config.ClockInterval > 0 {
 		l.clock = util.NewClock(config.ClockInterval)
 	} else {
@@ -280,11 +362,13 @@ func NewLogger(config LoggerConfig) *Logger {
 	}
 
 	if config.AsyncMode {
-		l.asyncLogger = writer.NewAsyncLogger(l, config.WorkerCount, config.ChannelSize, config.ProcessTimeout, config.NoTimeout)
+		l.asyncProc = &asyncProcessor{logger: l}
+		l.asyncLogger = writer.NewAsyncLogger(l.asyncProc, config.WorkerCount, config.ChannelSize, config.ProcessTimeout, config.NoTimeout)
+		l.asyncLogger.SetOverflow(l.overflowConfig())
 	}
 
-	if config.AsyncMode {
-		l.asyncLogger = writer.NewAsyncLogger(l, config.WorkerCount, config.ChannelSize, config.ProcessTimeout, config.NoTimeout)
+	if config.DropSummaryInterval > 0 && (l.asyncLogger != nil || l.buffer != nil || len(l.sinks) > 0) {
+		l.dropSummary = newDropSummary(l, config.DropSummaryInterval)
 	}
 
 	return l
@@ -299,14 +383,40 @@ func (w *samplerLoggerWrapper) Log(ctx context.Context, level core.Level, msg []
 	w.logger.LogLegacy(ctx, level, msg, fields)
 }
 
+// asyncProcessor feeds jobs from the async workers into the synchronous write path
+// of the logger that queued them, so that child loggers keep their fields, tags and error.
+// Passing the Logger itself would re-queue every job through Logger.Log.
+type asyncProcessor struct {
+	logger *Logger
+}
+
+func (p *asyncProcessor) Log(ctx context.Context, level core.Level, msg []byte, keyvals ...[]byte) {
+	p.logger.writeZero(ctx, level, msg, keyvals...)
+}
+func (p *asyncProcessor) LogMap(ctx context.Context, level core.Level, msg []byte, fields map[string][]byte) {
+	p.logger.writeByte(ctx, level, msg, fields)
+}
+func (p *asyncProcessor) LogFields(ctx context.Context, level core.Level, msg []byte, err error, fields []core.Field) {
+	p.logger.writeTyped(ctx, level, msg, err, fields)
+}
+func (p *asyncProcessor) ErrorHandler() func(error) { return p.logger.handleError }
+func (p *asyncProcessor) ErrOut() io.Writer         { return p.logger.errOut }
+func (p *asyncProcessor) ErrOutMu() *sync.Mutex     { return p.logger.errOutMu }
+
 func (l *Logger) setupWriters() {
+	l.setupSinks()
+
 	currentWriter := l.Config.Output
+	if currentWriter == nil {
+		return // Entries only go to the sinks
+	}
 
 	if l.Config.EnableRotation && l.Config.RotationConfig != nil {
 		if file, ok := currentWriter.(*os.File); ok {
 			var err error
 			l.rotation, err = writer.NewRotator(file.Name(), l.Config.RotationConfig)
 			if err == nil {
+				l.rotation.SetErrorHandler(l.handleError)
 				currentWriter = l.rotation
 			} else {
 				l.handleError(newErrorf("failed to setup rotation: %v", err))
@@ -316,6 +426,15 @@ func (l *Logger) setupWriters() {
 
 	if l.Config.BufferSize > 0 {
 		l.buffer = writer.NewBuffered(currentWriter, l.Config.BufferSize, l.Config.FlushInterval, l.handleError, l.Config.BatchSize, l.Config.BatchTimeout)
+		l.buffer.SetOverflow(l.overflowConfig())
+		if l.Config.SpillDir != "" {
+			spill, err := writer.NewSpillQueue(writer.SpillConfig{Dir: l.Config.SpillDir, MaxBytes: l.Config.SpillMaxBytes})
+			if err == nil {
+				l.buffer.SetSpill(spill)
+			} else {
+				l.handleError(newErrorf("failed to setup spill queue: %v", err))
+			}
+		}
 		currentWriter = l.buffer
 	}
 
@@ -336,7 +455,7 @@ func (l *Logger) log(ctx context.Context, level core.Level, message []byte, fiel
 	}
 
 	// Early filtering to avoid unnecessary work - branch prediction optimized
-	if level < l.Config.Level {
+	if level < l.minLevel() {
 		return
 	}
 
@@ -360,9 +479,12 @@ func (l *Logger) log(ctx context.Context, level core.Level, message []byte, fiel
 	}
 
 	// Optimized path for non-blocking scenarios using atomic operations
-	if l.asyncLogger != nil {
+	if l.queueAsync(level) {
 		// Use lock-free async logging for high throughput
-		l.asyncLogger.Log(level, message, byteFields, ctx)
+		l.asyncLogger.LogFor(l.asyncProc, level, message, byteFields, l.asyncContext(ctx))
+		if l.metrics != nil {
+			l.metrics.recordAsync(l.asyncLogger)
+		}
 		return
 	}
 
@@ -378,8 +500,11 @@ func (l *Logger) logZero(ctx context.Context, level core.Level, message []byte,
 	}
 
 	// For zero-allocation, we pass keyvals directly to formatter
-	if l.asyncLogger != nil {
-		l.asyncLogger.LogZero(level, message, ctx, keyvals...)
+	if l.queueAsync(level) {
+		l.asyncLogger.LogZeroFor(l.asyncProc, level, message, l.asyncContext(ctx), keyvals...)
+		if l.metrics != nil {
+			l.metrics.recordAsync(l.asyncLogger)
+		}
 		return
 	}
 
@@ -394,7 +519,7 @@ func (l *Logger) logBytes(ctx context.Context, level core.Level, message []byte,
 	}
 
 	// Early filtering to avoid unnecessary work - branch prediction optimized
-	if level < l.Config.Level {
+	if level < l.minLevel() {
 		return
 	}
 
@@ -404,9 +529,12 @@ func (l *Logger) logBytes(ctx context.Context, level core.Level, message []byte,
 	}
 
 	// Optimized path for non-blocking scenarios using atomic operations
-	if l.asyncLogger != nil {
+	if l.queueAsync(level) {
 		// Use lock-free async logging for high throughput
-		l.asyncLogger.Log(level, message, fields, ctx)
+		l.asyncLogger.LogFor(l.asyncProc, level, message, fields, l.asyncContext(ctx))
+		if l.metrics != nil {
+			l.metrics.recordAsync(l.asyncLogger)
+		}
 		return
 	}
 
@@ -419,37 +547,11 @@ func (l *Logger) logBytes(ctx context.Context, level core.Level, message []byte,
 func (l *Logger) write(ctx context.Context, level core.Level, message []byte, fields map[string]interface{}) {
 	entry := l.buildEntry(ctx, level, message, fields)
 
-	// Use efficient buffer for zero-allocation
-	buf := util.GetBuffer()
-	defer util.PutBuffer(buf)
-
-	if err := l.formatter.Format(buf, entry); err != nil {
-		l.handleError(err)
+	if !l.writeEntry(entry) {
 		core.PutEntryToPool(entry)
 		return
 	}
 
-	bytesToWrite := buf.Bytes()
-
-	// Optimized write with minimal locking - only lock when actually writing
-	if l.Config.NoLocking {
-		// Direct path: no locking at all
-		if n, err := l.out.Write(bytesToWrite); err != nil {
-			l.handleError(err)
-		} else {
-			l.stats.Increment(level, n)
-		}
-	} else {
-		// Standard path with proper locking
-		l.mu.Lock()
-		if n, err := l.out.Write(bytesToWrite); err != nil {
-			l.handleError(err)
-		} else {
-			l.stats.Increment(level, n)
-		}
-		l.mu.Unlock()
-	}
-
 	l.runHooks(entry)
 
 	// must be done after hooks and writing, but before PutEntryToPool
@@ -460,13 +562,7 @@ func (l *Logger) write(ctx context.Context, level core.Level, message []byte, fi
 
 // writeZero writes log entry with zero allocations using variadic key-value pairs
 func (l *Logger) writeZero(ctx context.Context, level core.Level, message []byte, keyvals ...[]byte) {
-	entry := l.entryPool.Get().(*core.LogEntry)
-	defer l.entryPool.Put(entry)
-
-	entry.Reset()
-	entry.Level = level
-	entry.Message = message
-	entry.Timestamp = l.clock.Now()
+	entry := l.buildEntryByte(ctx, level, message, nil)
 
 	// Set keyvals directly without map allocation
 	if len(keyvals)%2 == 0 {
@@ -476,54 +572,109 @@ func (l *Logger) writeZero(ctx context.Context, level core.Level, message []byte
 		entry.KeyVals = keyvals[:len(keyvals)-1]
 	}
 
-	if l.Config.ShowCaller {
-		entry.Caller = util.GetCallerInfo(l.Config.CallerDepth)
+	if !l.writeEntry(entry) {
+		core.PutEntryToPool(entry)
+		return
 	}
 
-	buf := util.GetBuffer()
-	defer util.PutBuffer(buf)
-	
-	_, _ = l.Config.Formatter.Format(buf, entry)
-	l.mu.Lock()
-	l.Config.Output.Write(buf.Bytes())
-	l.mu.Unlock()
+	l.runHooks(entry)
+
+	// must be done after hooks and writing, but before PutEntryToPool
+	l.handleLevelActions(level, entry)
+
+	core.PutEntryToPool(entry)
 }
 
-// final write to output with zero-allocation optimizations for []byte fields (true zero-allocation)
-func (l *Logger) writeByte(ctx context.Context, level core.Level, message []byte, fields map[string][]byte) {
-	entry := l.buildEntryByte(ctx, level, message, fields)
+// writeEntry redacts the entry, applies the size limits, encrypts designated fields and writes it to the output and to every sink that accepts it.
+// It returns false if the entry could not be formatted for the output.
+func (l *Logger) writeEntry(entry *core.LogEntry) bool {
+	// Redact before truncating, so that no partial match is left behind
+	if l.Config.FieldPolicy != nil {
+		l.Config.FieldPolicy.Apply(entry)
+	}
+	if l.Config.Redactor != nil {
+		l.Config.Redactor.RedactEntry(entry)
+	}
+	l.applyLimits(entry)
+	// Encrypt after truncating, since a truncated ciphertext cannot be decrypted
+	if l.Config.Encryptor != nil {
+		if _, err := l.Config.Encryptor.EncryptEntry(entry); err != nil {
+			l.handleError(err)
+		}
+	}
+	ok := true
+	if l.out != nil {
+		ok = l.writeOutput(entry)
+	}
+	if len(l.sinks) > 0 {
+		l.writeSinks(entry)
+	}
+	return ok
+}
 
+// writeOutput formats the entry and writes it to the output, updating stats and metrics.
+// It returns false if the entry could not be formatted.
+func (l *Logger) writeOutput(entry *core.LogEntry) bool {
 	// Use efficient buffer for zero-allocation
 	buf := util.GetBuffer()
 	defer util.PutBuffer(buf)
 
+	var start time.Time
+	if l.metrics != nil {
+		start = time.Now()
+	}
+
 	if err := l.formatter.Format(buf, entry); err != nil {
 		l.handleError(err)
-		core.PutEntryToPool(entry)
-		return
+		return false
+	}
+
+	if l.metrics != nil {
+		now := time.Now()
+		l.metrics.recordFormat(now.Sub(start))
+		start = now
 	}
 
 	bytesToWrite := buf.Bytes()
+	var n int
+	var err error
 
 	// Optimized write with minimal locking - only lock when actually writing
 	if l.Config.NoLocking {
 		// Direct path: no locking at all
-		if n, err := l.out.Write(bytesToWrite); err != nil {
-			l.handleError(err)
-		} else {
-			l.stats.Increment(level, n)
-		}
+		n, err = l.writeOut(entry.Level, bytesToWrite)
 	} else {
 		// Standard path with proper locking
 		l.mu.Lock()
-		if n, err := l.out.Write(bytesToWrite); err != nil {
-			l.handleError(err)
-		} else {
-			l.stats.Increment(level, n)
-		}
+		n, err = l.writeOut(entry.Level, bytesToWrite)
 		l.mu.Unlock()
 	}
 
+	if err != nil {
+		l.handleError(err)
+	} else {
+		l.stats.Increment(entry.Level, n)
+	}
+
+	if l.metrics != nil {
+		l.metrics.recordWrite(time.Since(start), n)
+		l.metrics.recordLevel(entry.Level)
+		if l.buffer != nil {
+			l.metrics.recordBuffer(l.buffer)
+		}
+	}
+	return true
+}
+
+// final write to output with zero-allocation optimizations for []byte fields (true zero-allocation)
+func (l *Logger) writeByte(ctx context.Context, level core.Level, message []byte, fields map[string][]byte) {
+	entry := l.buildEntryByte(ctx, level, message, fields)
+
+	if !l.writeEntry(entry) {
+		core.PutEntryToPool(entry)
+		return
+	}
+
 	l.runHooks(entry)
 
 	// must be done after hooks and writing, but before PutEntryToPool
@@ -646,29 +797,59 @@ func (l *Logger) buildEntry(ctx context.Context, level core.Level, message []byt
 	entry.LevelName = level.ToBytes()
 	entry.Message = message
 	entry.PID = l.pid
+	l.meta.apply(ctx, entry)
+
+	// Fields in order: logger fields, context fields, call-site fields.
+	// A repeated key keeps its first position and takes the later value.
+	l.setEntryFields(entry)
+	l.setContextFields(ctx, entry)
+	if len(fields) > 0 {
+		var stack [32]string
+		for _, k := range core.SortedKeys(stack[:0], fields) {
+			// Convert interface{} values to []byte when copying to entry.Fields
+			switch val := fields[k].(type) {
+			case string:
+				entry.SetField(k, core.StringToBytes(val))
+			case []byte:
+				entry.SetField(k, val)
+			default:
+				entry.SetField(k, core.StringToBytes(fmt.Sprintf("%v", val)))
+			}
+		}
+	}
+	l.applySpanContext(ctx, entry)
 
-	// Copy fields with minimal allocations - l.fields is now []byte
-	for k, v := range l.fields {
-		entry.Fields[k] = v // v is already []byte
+	// Caller info only if required to avoid overhead
+	if l.Config.ShowCaller {
+		entry.Caller = util.GetCallerInfo(l.Config.CallerDepth)
 	}
-	for k, v := range fields {
-		// Convert interface{} values to []byte when copying to entry.Fields
-		switch val := v.(type) {
-		case string:
-			entry.Fields[k] = core.StringToBytes(val)
-		case []byte:
-			entry.Fields[k] = val
-		default:
-			entry.Fields[k] = core.StringToBytes(fmt.Sprintf("%v", val))
-		}
+
+	// Stack trace only for ERROR level and above
+	if l.Config.IncludeStackTrace && level >= core.ERROR {
+		stackTraceBytes, stackTraceBufPtr := util.GetStackTrace(l.Config.StackTraceDepth)
+		entry.StackTrace = stackTraceBytes
+		entry.StackTraceBufPtr = stackTraceBufPtr
+	}
+	if l.err != nil {
+		l.attachError(entry, l.err)
 	}
+	l.applyTags(entry)
+
+	return entry
+}
+
+// setEntryFields copies the logger fields to the entry in the order they were added
+func (l *Logger) setEntryFields(entry *core.LogEntry) {
+	for _, k := range l.fieldKeys {
+		entry.SetField(k, l.fields[k])
+	}
+}
 
-	// Extract context with zero allocation if possible
+// setContextFields sets the fields of the context extractor, or the
+// well-known request values stored in ctx, on the entry
+func (l *Logger) setContextFields(ctx context.Context, entry *core.LogEntry) {
 	if l.contextExtractor != nil {
-		contextFields := l.contextExtractor(ctx)
-		for k, v := range contextFields {
-			entry.Fields[k] = v
-		}
+		entry.SetFields(l.contextExtractor(ctx))
 	} else if ctx != nil {
 		contextData := util.ExtractFromContext(ctx)
 		for k, v := range contextData {
@@ -687,20 +868,6 @@ func (l *Logger) buildEntry(ctx context.Context, level core.Level, message []byt
 		}
 		util.PutMapStr(contextData)
 	}
-
-	// Caller info only if required to avoid overhead
-	if l.Config.ShowCaller {
-		entry.Caller = util.GetCallerInfo(l.Config.CallerDepth)
-	}
-
-	// Stack trace only for ERROR level and above
-	if l.Config.IncludeStackTrace && level >= core.ERROR {
-		stackTraceBytes, stackTraceBufPtr := util.GetStackTrace(l.Config.StackTraceDepth)
-		entry.StackTrace = stackTraceBytes
-		entry.StackTraceBufPtr = stackTraceBufPtr
-	}
-
-	return entry
 }
 
 // buildEntryByte creates a log entry with minimal allocations using []byte fields (true zero-allocation)
@@ -718,39 +885,14 @@ func (l *Logger) buildEntryByte(ctx context.Context, level core.Level, message [
 	entry.LevelName = level.ToBytes()
 	entry.Message = message
 	entry.PID = l.pid
+	l.meta.apply(ctx, entry)
 
-	// Copy fields with minimal allocations - these are already []byte
-	for k, v := range l.fields {
-		entry.Fields[k] = v
-	}
-	for k, v := range fields {
-		entry.Fields[k] = v
-	}
-
-	// Extract context with zero allocation if possible
-	if l.contextExtractor != nil {
-		contextFields := l.contextExtractor(ctx)
-		for k, v := range contextFields {
-			entry.Fields[k] = v // v is already []byte
-		}
-	} else if ctx != nil {
-		contextData := util.ExtractFromContext(ctx)
-		for k, v := range contextData {
-			switch k {
-			case "trace_id":
-				entry.TraceID = core.StringToBytes(v)
-			case "span_id":
-				entry.SpanID = core.StringToBytes(v)
-			case "user_id":
-				entry.UserID = core.StringToBytes(v)
-			case "session_id":
-				entry.SessionID = core.StringToBytes(v)
-			case "request_id":
-				entry.RequestID = core.StringToBytes(v)
-			}
-		}
-		util.PutMapStr(contextData)
-	}
+	// Fields in order: logger fields, context fields, call-site fields.
+	// A repeated key keeps its first position and takes the later value.
+	l.setEntryFields(entry)
+	l.setContextFields(ctx, entry)
+	entry.SetFields(fields)
+	l.applySpanContext(ctx, entry)
 
 	// Caller info only if required to avoid overhead
 	if l.Config.ShowCaller {
@@ -763,6 +905,10 @@ func (l *Logger) buildEntryByte(ctx context.Context, level core.Level, message [
 		entry.StackTrace = stackTraceBytes
 		entry.StackTraceBufPtr = stackTraceBufPtr
 	}
+	if l.err != nil {
+		l.attachError(entry, l.err)
+	}
+	l.applyTags(entry)
 
 	return entry
 }
@@ -781,6 +927,9 @@ func (l *Logger) runHooks(entry *core.LogEntry) {
 	// Execute hooks with graceful error handling
 	for _, h := range l.hooks {
 		if err := h.Fire(entry); err != nil {
+			if l.metrics != nil {
+				l.metrics.recordHookFailure()
+			}
 			l.handleError(newErrorf("hook error: %v", err))
 		}
 	}
@@ -799,6 +948,7 @@ func (l *Logger) handleLevelActions(level core.Level, entry *core.LogEntry) {
 		if l.onFatal != nil {
 			l.onFatal(entry)
 		}
+		l.syncBeforeExit()
 		l.exitFunc(1)
 	case core.PANIC:
 		if l.onPanic != nil {
@@ -814,6 +964,7 @@ func (l *Logger) handleLevelActions(level core.Level, entry *core.LogEntry) {
 			msgBuf.WriteByte('\n')
 			_, _ = l.out.Write(msgBuf.Bytes())
 		}
+		l.syncBeforeExit()
 		l.exitFunc(1)
 	}
 }
@@ -848,6 +999,11 @@ func (l *Logger) Close() {
 			l.asyncLogger.Close()
 		}
 
+		// Report the final drop count before the buffered writer closes
+		if l.dropSummary != nil {
+			l.dropSummary.stop()
+		}
+
 		// Close buffered writer if present
 		if l.buffer != nil {
 			// Graceful degradation during closing
@@ -856,6 +1012,8 @@ func (l *Logger) Close() {
 			}
 		}
 
+		l.closeSinks()
+
 		// Close rotating file writer if present
 		if l.rotation != nil {
 			// Graceful degradation during closing
@@ -886,25 +1044,35 @@ func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
 		return l
 	}
 	newLogger := l.clone()
-	for k, v := range fields {
-		switch val := v.(type) {
+	// Keys of one call are added in sorted order, as map order is random
+	var stack [32]string
+	for _, k := range core.SortedKeys(stack[:0], fields) {
+		switch val := fields[k].(type) {
 		case string:
-			newLogger.fields[k] = []byte(val)
+			newLogger.setField(k, []byte(val))
 		case []byte:
-			newLogger.fields[k] = val
+			newLogger.setField(k, val)
 		case int:
-			newLogger.fields[k] = I2B(val)
+			newLogger.setField(k, I2B(val))
 		case float64:
-			newLogger.fields[k] = F2B(val)
+			newLogger.setField(k, F2B(val))
 		case bool:
-			newLogger.fields[k] = B2B(val)
+			newLogger.setField(k, B2B(val))
 		default:
-			newLogger.fields[k] = []byte(fmt.Sprintf("%v", val))
+			newLogger.setField(k, []byte(fmt.Sprintf("%v", val)))
 		}
 	}
 	return newLogger
 }
 
+// setField sets a logger field; a new key is added after the existing ones
+func (l *Logger) setField(key string, value []byte) {
+	if _, ok := l.fields[key]; !ok {
+		l.fieldKeys = append(l.fieldKeys, key)
+	}
+	l.fields[key] = value
+}
+
 // clone creates a copy of the logger with shared resources
 func (l *Logger) clone() *Logger {
 	l.mu.RLock()
@@ -915,6 +1083,9 @@ func (l *Logger) clone() *Logger {
 	// and their goroutines, not create new ones.
 	cloned := &Logger{
 		Config:           l.Config,
+		level:            l.level,
+		name:             l.name,
+		registry:         l.registry,
 		formatter:        l.formatter,
 		out:              l.out,
 		errOut:           l.errOut,
@@ -927,22 +1098,37 @@ func (l *Logger) clone() *Logger {
 		buffer:           l.buffer,
 		rotation:         l.rotation,
 		contextExtractor: l.contextExtractor,
+		spanProvider:     l.spanProvider,
+		err:              l.err,
+		tags:             l.tags,
+		customMetrics:    l.customMetrics,
 		metrics:          l.metrics,
 		onFatal:          l.onFatal,
 		onPanic:          l.onPanic,
 		stats:            l.stats,
 		asyncLogger:      l.asyncLogger,
+		dropSummary:      l.dropSummary,
 		errorFileHook:    l.errorFileHook,
+		sinks:            l.sinks,
 		closed:           &atomic.Bool{},
 		pid:              l.pid,
+		meta:             l.meta,
 		clock:            l.clock,
 	}
 	copy(cloned.hooks, l.hooks)
+	if l.asyncProc != nil {
+		cloned.asyncProc = &asyncProcessor{logger: cloned}
+	}
+
+	if l.levelCache != nil {
+		cloned.levelCache = &atomic.Uint64{}
+	}
 
 	// Copy parent fields.
 	for k, v := range l.fields {
 		cloned.fields[k] = v
 	}
+	cloned.fieldKeys = append(make([]string, 0, len(l.fieldKeys)+10), l.fieldKeys...)
 
 	return cloned
 }
@@ -953,7 +1139,7 @@ func (l *Logger) clone() *Logger {
 // Zero-allocation logging API using variadic parameters
 // LogZ logs with zero allocations using key-value pairs
 func (l *Logger) LogZ(ctx context.Context, level core.Level, msg []byte, keyvals ...[]byte) {
-	if level < l.Config.Level {
+	if level < l.minLevel() {
 		return
 	}
 	l.logZero(ctx, level, msg, keyvals...)
@@ -961,7 +1147,7 @@ func (l *Logger) LogZ(ctx context.Context, level core.Level, msg []byte, keyvals
 
 // LogZC logs with context only (zero allocation)
 func (l *Logger) LogZC(ctx context.Context, level core.Level, msg []byte) {
-	if level < l.Config.Level {
+	if level < l.minLevel() {
 		return
 	}
 	l.logZero(ctx, level, msg)
@@ -970,7 +1156,7 @@ func (l *Logger) LogZC(ctx context.Context, level core.Level, msg []byte) {
 // Legacy API - Log with map (may allocate)
 // LogLegacy for sampler compatibility (legacy map interface)
 func (l *Logger) LogLegacy(ctx context.Context, level core.Level, msg []byte, fields map[string][]byte) {
-	if level < l.Config.Level {
+	if level < l.minLevel() {
 		return
 	}
 	l.logBytes(ctx, level, msg, fields)
@@ -978,7 +1164,7 @@ func (l *Logger) LogLegacy(ctx context.Context, level core.Level, msg []byte, fi
 
 // Log using variadic key-value pairs
 func (l *Logger) Log(ctx context.Context, level core.Level, msg []byte, keyvals ...[]byte) {
-	if level < l.Config.Level {
+	if level < l.minLevel() {
 		return
 	}
 	l.logZero(ctx, level, msg, keyvals...)
@@ -986,7 +1172,7 @@ func (l *Logger) Log(ctx context.Context, level core.Level, msg []byte, keyvals
 
 // LogC with context
 func (l *Logger) LogC(ctx context.Context, level core.Level, msg []byte) {
-	if level < l.Config.Level {
+	if level < l.minLevel() {
 		return
 	}
 	l.log(ctx, level, msg, nil)
@@ -994,7 +1180,7 @@ func (l *Logger) LogC(ctx context.Context, level core.Level, msg []byte) {
 
 // LogCF with context and fields (complete API)
 func (l *Logger) LogCF(ctx context.Context, level core.Level, msg []byte, fields map[string][]byte) {
-	if level < l.Config.Level {
+	if level < l.minLevel() {
 		return
 	}
 	l.logBytes(ctx, level, msg, fields)
@@ -1012,14 +1198,14 @@ const (
 
 // Optimized formatted logging methods
 func (l *Logger) Tracef(format string, args ...interface{}) {
-	if core.TRACE < l.Config.Level {
+	if core.TRACE < l.minLevel() {
 		return
 	}
 	l.log(context.Background(), core.TRACE, l.formatfArgsToBytes(format, args...), nil)
 }
 
 func (l *Logger) Debugf(format string, args ...interface{}) {
-	if core.DEBUG < l.Config.Level {
+	if core.DEBUG < l.minLevel() {
 		return
 	}
 	l.log(context.Background(), core.DEBUG, l.formatfArgsToBytes(format, args...), nil)
@@ -1075,35 +1261,35 @@ func BoolB(b bool) []byte {
 
 // TraceCB logs a message with TRACE level and extracts context information using []byte (zero-allocation)
 func (l *Logger) TraceCB(ctx context.Context, message []byte) {
-	if core.TRACE >= l.Config.Level {
+	if core.TRACE >= l.minLevel() {
 		l.log(ctx, core.TRACE, message, nil)
 	}
 }
 
 // DebugCB logs a message with DEBUG level and extracts context information using []byte (zero-allocation)
 func (l *Logger) DebugCB(ctx context.Context, message []byte) {
-	if core.DEBUG >= l.Config.Level {
+	if core.DEBUG >= l.minLevel() {
 		l.log(ctx, core.DEBUG, message, nil)
 	}
 }
 
 // InfoCB logs a message with INFO level and extracts context information using []byte (zero-allocation)
 func (l *Logger) InfoCB(ctx context.Context, message []byte) { 
-	if core.INFO >= l.Config.Level {
+	if core.INFO >= l.minLevel() {
 		l.log(ctx, core.INFO, message, nil) 
 	}
 }
 
 // WarnCB logs a message with WARN level and extracts context information using []byte (zero-allocation)
 func (l *Logger) WarnCB(ctx context.Context, message []byte) { 
-	if core.WARN >= l.Config.Level {
+	if core.WARN >= l.minLevel() {
 		l.log(ctx, core.WARN, message, nil) 
 	}
 }
 
 // ErrorCB logs a message with ERROR level and extracts context information using []byte (zero-allocation)
 func (l *Logger) ErrorCB(ctx context.Context, message []byte) {
-	if core.ERROR >= l.Config.Level {
+	if core.ERROR >= l.minLevel() {
 		l.log(ctx, core.ERROR, message, nil)
 	}
 }
@@ -1115,31 +1301,31 @@ func (l *Logger) FatalCB(ctx context.Context, message []byte) {
 
 // Context-aware logging methods (interface{} args)
 func (l *Logger) TraceC(ctx context.Context, args ...interface{}) {
-	if core.TRACE >= l.Config.Level {
+	if core.TRACE >= l.minLevel() {
 		l.log(ctx, core.TRACE, l.formatArgsToBytes(args...), nil)
 	}
 }
 
 func (l *Logger) DebugC(ctx context.Context, args ...interface{}) {
-	if core.DEBUG >= l.Config.Level {
+	if core.DEBUG >= l.minLevel() {
 		l.log(ctx, core.DEBUG, l.formatArgsToBytes(args...), nil)
 	}
 }
 
 func (l *Logger) InfoC(ctx context.Context, args ...interface{}) {
-	if core.INFO >= l.Config.Level {
+	if core.INFO >= l.minLevel() {
 		l.log(ctx, core.INFO, l.formatArgsToBytes(args...), nil)
 	}
 }
 
 func (l *Logger) WarnC(ctx context.Context, args ...interface{}) {
-	if core.WARN >= l.Config.Level {
+	if core.WARN >= l.minLevel() {
 		l.log(ctx, core.WARN, l.formatArgsToBytes(args...), nil)
 	}
 }
 
 func (l *Logger) ErrorC(ctx context.Context, args ...interface{}) {
-	if core.ERROR >= l.Config.Level {
+	if core.ERROR >= l.minLevel() {
 		l.log(ctx, core.ERROR, l.formatArgsToBytes(args...), nil)
 	}
 }
@@ -11,7 +11,9 @@ import (
 	"time"
 
 	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/encrypt"
 	"github.com/Lunar-Chipter/mire/formatter"
+	"github.com/Lunar-Chipter/mire/redact"
 	"github.com/Lunar-Chipter/mire/util"
 )
 
@@ -371,3 +373,124 @@ type errorWriter struct{}
 func (ew *errorWriter) Write(p []byte) (n int, err error) {
 	return 0, os.ErrInvalid
 }
+
+// TestLoggerFieldOrder tests that fields keep the order logger, context, call
+// site, and that a later source takes precedence for a repeated key
+func TestLoggerFieldOrder(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(LoggerConfig{
+		Level:     core.DEBUG,
+		Output:    &buf,
+		Formatter: &formatter.TextFormatter{},
+		ExtractContext: func(ctx context.Context) map[string][]byte {
+			return map[string][]byte{"tenant": []byte("acme"), "region": []byte("ctx")}
+		},
+	})
+	defer l.Close()
+
+	child := l.WithFields(map[string]interface{}{"service": "api", "region": "eu"}).
+		WithFields(map[string]interface{}{"attempt": 1})
+
+	for i := 0; i < 20; i++ {
+		buf.Reset()
+		child.LogCF(context.Background(), core.INFO, []byte("ordered"), map[string][]byte{
+			"user":   []byte("42"),
+			"tenant": []byte("call"),
+		})
+		want := "{region=ctx service=api attempt=1 tenant=call user=42}"
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("Expected %s, got %q", want, buf.String())
+		}
+	}
+
+	// Typed call-site fields replace map fields of the same key
+	buf.Reset()
+	child.InfoT("typed", Int("attempt", 2))
+	if !strings.Contains(buf.String(), "{region=ctx service=api tenant=acme attempt=2}") {
+		t.Errorf("Expected the typed field to win, got %q", buf.String())
+	}
+}
+
+// TestLoggerRedaction tests that the redactor applies to the output and every sink before formatting
+func TestLoggerRedaction(t *testing.T) {
+	r, err := redact.New(redact.Config{})
+	if err != nil {
+		t.Fatalf("redact.New failed: %v", err)
+	}
+	var text, json bytes.Buffer
+	l := New(LoggerConfig{
+		Level:     core.DEBUG,
+		Output:    &text,
+		Formatter: &formatter.TextFormatter{},
+		Sinks:     []Sink{{Output: &json, Formatter: formatter.NewJSON()}},
+		Redactor:  r,
+	})
+	defer l.Close()
+
+	l.InfoT("payment by jane@example.com", String("card", "4111 1111 1111 1111"))
+	for name, out := range map[string]string{"text": text.String(), "json": json.String()} {
+		if strings.Contains(out, "jane@example.com") || strings.Contains(out, "4111") || strings.Count(out, "[REDACTED]") != 2 {
+			t.Errorf("Expected redacted %s output, got %q", name, out)
+		}
+	}
+}
+
+// TestLoggerFieldPolicy tests that the field policy applies to every source
+// of fields, whatever the formatter
+func TestLoggerFieldPolicy(t *testing.T) {
+	p, err := redact.NewPolicy(redact.PolicyConfig{Rules: []redact.FieldRule{
+		{Pattern: "*password*", Strategy: redact.Mask},
+		{Pattern: "auth.*", Strategy: redact.Drop},
+		{Pattern: "user_id", Strategy: redact.Mask},
+	}})
+	if err != nil {
+		t.Fatalf("redact.NewPolicy failed: %v", err)
+	}
+	var text, json bytes.Buffer
+	l := New(LoggerConfig{
+		Level:       core.DEBUG,
+		Output:      &text,
+		Formatter:   &formatter.TextFormatter{ShowTraceInfo: true},
+		Sinks:       []Sink{{Output: &json, Formatter: formatter.NewJSON()}},
+		FieldPolicy: p,
+	})
+	defer l.Close()
+
+	ctx := util.WithUserID(context.Background(), "user-42")
+	l.WithFields(map[string]interface{}{"db_password": "hunter2"}).InfoC(ctx, "login")
+	l.InfoT("login", String("auth.token", "abc123"), String("Password", "hunter2"), String("user", "jane"))
+	for name, out := range map[string]string{"text": text.String(), "json": json.String()} {
+		if strings.Contains(out, "hunter2") || strings.Contains(out, "abc123") || strings.Contains(out, "user-42") || !strings.Contains(out, "jane") {
+			t.Errorf("Expected redacted %s output, got %q", name, out)
+		}
+	}
+}
+
+// TestLoggerEncryption tests that designated fields are written encrypted and can be decrypted
+func TestLoggerEncryption(t *testing.T) {
+	ring := encrypt.NewStaticKeyring()
+	ring.Add("k1", bytes.Repeat([]byte{1}, 32))
+	e, err := encrypt.New(encrypt.Config{Keyring: ring, Fields: []string{"national_id"}})
+	if err != nil {
+		t.Fatalf("encrypt.New failed: %v", err)
+	}
+	var text, json bytes.Buffer
+	l := New(LoggerConfig{
+		Level:     core.DEBUG,
+		Output:    &text,
+		Formatter: &formatter.TextFormatter{},
+		Sinks:     []Sink{{Output: &json, Formatter: formatter.NewJSON()}},
+		Encryptor: e,
+	})
+	defer l.Close()
+
+	l.InfoT("support request", String("national_id", "AB123456C"), String("user", "jane"))
+	for name, out := range map[string]string{"text": text.String(), "json": json.String()} {
+		if strings.Contains(out, "AB123456C") || !strings.Contains(out, encrypt.Prefix+"k1:") {
+			t.Errorf("Expected encrypted %s output, got %q", name, out)
+		}
+		if plain, err := encrypt.DecryptText(ring, []byte(out)); err != nil || !strings.Contains(string(plain), "AB123456C") {
+			t.Errorf("Expected %s output to decrypt, got %q, %v", name, plain, err)
+		}
+	}
+}
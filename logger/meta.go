@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/Lunar-Chipter/mire/core"
+)
+
+// entryMeta holds the static metadata copied onto every entry.
+// Values are resolved once when the logger is created; nil values are left unset.
+type entryMeta struct {
+	hostname    []byte
+	application []byte
+	version     []byte
+	environment []byte
+	goroutineID bool
+}
+
+// newEntryMeta resolves the static metadata for a configuration.
+// The hostname is only set when ShowHostname is set, and falls back to
+// os.Hostname; the application name is only set when ShowApp is set, and
+// falls back to the executable name.
+func newEntryMeta(config *LoggerConfig) entryMeta {
+	meta := entryMeta{
+		version:     nonEmptyBytes(config.Version),
+		environment: nonEmptyBytes(config.Environment),
+		goroutineID: config.ShowGoroutine,
+	}
+	if config.ShowHostname {
+		meta.hostname = nonEmptyBytes(config.Hostname)
+		if meta.hostname == nil {
+			if hostname, err := os.Hostname(); err == nil {
+				meta.hostname = nonEmptyBytes(hostname)
+			}
+		}
+	}
+	if config.ShowApp {
+		meta.application = nonEmptyBytes(config.Application)
+		if meta.application == nil && len(os.Args) > 0 {
+			meta.application = nonEmptyBytes(filepath.Base(os.Args[0]))
+		}
+	}
+	return meta
+}
+
+// nonEmptyBytes converts s to a byte slice, or nil when s is empty
+func nonEmptyBytes(s string) []byte {
+	if s == "" {
+		return nil
+	}
+	return []byte(s)
+}
+
+// goroutineIDKey is the context key carrying the goroutine ID of the caller
+// through the async workers to the entry
+type goroutineIDKey struct{}
+
+// apply copies the metadata onto entry. The goroutine ID is the one captured
+// by asyncContext when ctx comes from a queued entry, and the current one otherwise.
+func (m *entryMeta) apply(ctx context.Context, entry *core.LogEntry) {
+	entry.Hostname = m.hostname
+	entry.Application = m.application
+	entry.Version = m.version
+	entry.Environment = m.environment
+	if !m.goroutineID {
+		return
+	}
+	if ctx != nil {
+		if id, ok := ctx.Value(goroutineIDKey{}).([]byte); ok {
+			entry.GoroutineID = append(entry.GoroutineID[:0], id...)
+			return
+		}
+	}
+	entry.GoroutineID = core.AppendGoroutineID(entry.GoroutineID[:0])
+}
+
+// asyncContext returns the context of an entry about to be queued for the
+// async workers, carrying the goroutine ID of the caller when it is shown
+func (l *Logger) asyncContext(ctx context.Context) context.Context {
+	if !l.meta.goroutineID {
+		return ctx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, goroutineIDKey{}, core.AppendGoroutineID(nil))
+}
@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/formatter"
+)
+
+// TestNewEntryMeta tests resolving static metadata from the configuration
+func TestNewEntryMeta(t *testing.T) {
+	meta := newEntryMeta(&LoggerConfig{
+		Hostname:     "web-1",
+		Application:  "billing",
+		Version:      "1.2.3",
+		Environment:  "prod",
+		ShowHostname: true,
+		ShowApp:      true,
+	})
+	if string(meta.hostname) != "web-1" || string(meta.application) != "billing" ||
+		string(meta.version) != "1.2.3" || string(meta.environment) != "prod" {
+		t.Errorf("Unexpected metadata %+v", meta)
+	}
+
+	meta = newEntryMeta(&LoggerConfig{Hostname: "web-1", Application: "billing"})
+	if meta.hostname != nil || meta.application != nil {
+		t.Errorf("Expected no hostname or application without ShowHostname and ShowApp, got %+v", meta)
+	}
+
+	meta = newEntryMeta(&LoggerConfig{})
+	if meta.hostname != nil || meta.application != nil || meta.version != nil || meta.environment != nil {
+		t.Errorf("Expected empty metadata, got %+v", meta)
+	}
+
+	meta = newEntryMeta(&LoggerConfig{ShowHostname: true, ShowApp: true})
+	if hostname, err := os.Hostname(); err == nil && string(meta.hostname) != hostname {
+		t.Errorf("Expected hostname %q, got %q", hostname, meta.hostname)
+	}
+	if string(meta.application) != filepath.Base(os.Args[0]) {
+		t.Errorf("Expected executable name, got %q", meta.application)
+	}
+}
+
+// TestLoggerEntryMetadata tests that entries carry the static metadata and goroutine ID
+func TestLoggerEntryMetadata(t *testing.T) {
+	var buf bytes.Buffer
+	jf := formatter.NewJSON()
+	jf.ShowGoroutine = true
+	jf.ShowHostname = true
+	jf.ShowApplication = true
+	l := New(LoggerConfig{
+		Level:         core.INFO,
+		Output:        &buf,
+		Formatter:     jf,
+		Hostname:      "web-1",
+		Application:   "billing",
+		Version:       "1.2.3",
+		Environment:   "prod",
+		ShowGoroutine: true,
+		ShowHostname:  true,
+		ShowApp:       true,
+	})
+	defer l.Close()
+
+	gid := string(core.AppendGoroutineID(nil))
+	logs := []func(){
+		func() { l.Info("legacy") },
+		func() { l.Log(context.Background(), core.INFO, []byte("zero")) },
+		func() { l.InfoT("typed") },
+		func() { l.Infof("formatted %d", 1) },
+	}
+	for _, log := range logs {
+		buf.Reset()
+		log()
+
+		var decoded map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, buf.String())
+		}
+		want := map[string]string{
+			"hostname":     "web-1",
+			"application":  "billing",
+			"version":      "1.2.3",
+			"environment":  "prod",
+			"goroutine_id": gid,
+		}
+		for k, v := range want {
+			if decoded[k] != v {
+				t.Errorf("%s: expected %s=%s, got %v", decoded["message"], k, v, decoded[k])
+			}
+		}
+	}
+}
+
+// TestLoggerAsyncGoroutineID tests that queued entries carry the goroutine ID
+// of the caller rather than the one of the async worker
+func TestLoggerAsyncGoroutineID(t *testing.T) {
+	var buf syncBuffer
+	jf := formatter.NewJSON()
+	jf.ShowGoroutine = true
+	l := New(LoggerConfig{
+		Level:         core.INFO,
+		Output:        &buf,
+		Formatter:     jf,
+		ShowGoroutine: true,
+		AsyncMode:     true,
+		WorkerCount:   1,
+		ChannelSize:   16,
+	})
+
+	gid := string(core.AppendGoroutineID(nil))
+	l.Info("legacy")
+	l.Log(context.Background(), core.INFO, []byte("zero"))
+	l.InfoT("typed")
+	l.InfoC(context.Background(), "context")
+	l.Close()
+
+	lines := bytes.Split(bytes.TrimSpace([]byte(buf.String())), []byte("\n"))
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 entries, got %d:\n%s", len(lines), buf.String())
+	}
+	for _, line := range lines {
+		var decoded map[string]interface{}
+		if err := json.Unmarshal(line, &decoded); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, line)
+		}
+		if decoded["goroutine_id"] != gid {
+			t.Errorf("%s: expected goroutine_id=%s, got %v", decoded["message"], gid, decoded["goroutine_id"])
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package logger
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/metric"
+	"github.com/Lunar-Chipter/mire/util"
+	"github.com/Lunar-Chipter/mire/writer"
+)
+
+// loggerMetrics reports pipeline metrics to a metric.Collector.
+// A single instance is shared by a logger and all of its clones.
+type loggerMetrics struct {
+	collector metric.Collector
+	counters  metric.CounterCollector // nil when the collector has no named counters
+	tags      map[string]string       // Static tags attached to every metric (read-only)
+
+	bytesWritten  atomic.Int64
+	hookFailures  atomic.Int64
+	asyncDropped  atomic.Int64 // Last async drop total reported
+	bufferDropped atomic.Int64 // Last buffered drop total reported
+}
+
+// newLoggerMetrics creates a metrics reporter tagged with application and environment
+func newLoggerMetrics(collector metric.Collector, application, environment string) *loggerMetrics {
+	m := &loggerMetrics{collector: collector}
+	if cc, ok := collector.(metric.CounterCollector); ok {
+		m.counters = cc
+	}
+	if application != "" || environment != "" {
+		m.tags = make(map[string]string, 2)
+		if application != "" {
+			m.tags["application"] = application
+		}
+		if environment != "" {
+			m.tags["environment"] = environment
+		}
+	}
+	return m
+}
+
+// add increments a named counter, falling back to a gauge of the running total
+func (m *loggerMetrics) add(name string, delta, total int64) {
+	if m.counters != nil {
+		m.counters.AddCounter(name, delta, m.tags)
+		return
+	}
+	m.collector.RecordGauge(name, float64(total), m.tags)
+}
+
+// recordLevel counts an entry written at the given level
+func (m *loggerMetrics) recordLevel(level core.Level) {
+	m.collector.IncrementCounter(level, m.tags)
+}
+
+// recordFormat records the time spent formatting an entry
+func (m *loggerMetrics) recordFormat(d time.Duration) {
+	m.collector.RecordHistogram(metric.FormatDuration, d.Seconds(), m.tags)
+}
+
+// recordWrite records the time spent writing an entry and the bytes written
+func (m *loggerMetrics) recordWrite(d time.Duration, n int) {
+	m.collector.RecordHistogram(metric.WriteDuration, d.Seconds(), m.tags)
+	if n > 0 {
+		m.add(metric.BytesWritten, int64(n), m.bytesWritten.Add(int64(n)))
+	}
+}
+
+// recordHookFailure counts a failed hook invocation
+func (m *loggerMetrics) recordHookFailure() {
+	m.add(metric.HookFailures, 1, m.hookFailures.Add(1))
+}
+
+// recordAsync reports the async queue depth and any new drops
+func (m *loggerMetrics) recordAsync(al *writer.AsyncLogger) {
+	m.collector.RecordGauge(metric.AsyncQueueDepth, float64(al.QueueDepth()), m.tags)
+	m.recordDrops(metric.AsyncDropped, &m.asyncDropped, al.Dropped())
+}
+
+// recordBuffer reports any new drops from the buffered writer
+func (m *loggerMetrics) recordBuffer(bw *writer.Buffered) {
+	m.recordDrops(metric.BufferDropped, &m.bufferDropped, bw.DroppedLogs())
+}
+
+// recordDrops reports the increase of a monotonically growing drop total
+func (m *loggerMetrics) recordDrops(name string, last *atomic.Int64, total int64) {
+	for {
+		prev := last.Load()
+		if total <= prev {
+			return
+		}
+		if last.CompareAndSwap(prev, total) {
+			m.add(name, total-prev, total)
+			return
+		}
+	}
+}
+
+// MetricsHandler returns an http.Handler serving the logger's metrics in the
+// Prometheus text exposition format. It exports the configured collector when it
+// is a *metric.Metrics, the logger statistics, the async queue and buffered writer
+// state, and the global core and pool metrics.
+func (l *Logger) MetricsHandler() http.Handler {
+	return l.newExporter()
+}
+
+// newExporter builds an exporter for all metric sources of the logger
+func (l *Logger) newExporter() *metric.Exporter {
+	collector, _ := l.Config.Collector.(*metric.Metrics)
+	exporter := metric.NewExporter(collector,
+		statsSource(l.stats),
+		metric.CoreMetricsSource(core.GetCoreMetrics()),
+		metric.PoolMetricsSource(util.GetPoolMetrics()),
+	)
+	if l.asyncLogger != nil {
+		exporter.AddSource(asyncSource(l.asyncLogger))
+	}
+	if l.buffer != nil {
+		exporter.AddSource(metric.BufferedSource(l.buffer))
+	}
+	return exporter
+}
+
+// statsSource exports LoggerStats as per-level entry counts, bytes written, truncations and uptime
+func statsSource(ls *LoggerStats) metric.Source {
+	return func() []metric.Sample {
+		ls.mu.RLock()
+		defer ls.mu.RUnlock()
+
+		samples := make([]metric.Sample, 0, len(ls.LogCounts)+4)
+		for level := core.TRACE; level <= core.PANIC; level++ {
+			count, ok := ls.LogCounts[level]
+			if !ok {
+				continue
+			}
+			samples = append(samples, metric.Sample{
+				Name:   "logger_entries_total",
+				Help:   "Log entries written by level.",
+				Type:   metric.TypeCounter,
+				Labels: map[string]string{"level": strings.ToLower(level.String())},
+				Value:  float64(count),
+			})
+		}
+		return append(samples,
+			metric.Sample{Name: "logger_bytes_written_total", Help: "Bytes written to the log output.", Type: metric.TypeCounter, Value: float64(ls.BytesWritten)},
+			metric.Sample{Name: "logger_truncations_total", Help: "Messages and field values truncated to the size limits.", Type: metric.TypeCounter, Value: float64(ls.Truncations)},
+			metric.Sample{Name: "logger_start_time_seconds", Help: "Unix time the logger was created.", Type: metric.TypeGauge, Value: float64(ls.StartTime.UnixNano()) / 1e9},
+			metric.Sample{Name: "logger_uptime_seconds", Help: "Seconds since the logger was created.", Type: metric.TypeGauge, Value: time.Since(ls.StartTime).Seconds()},
+		)
+	}
+}
+
+// asyncSource exports the async queue depth and drop count
+func asyncSource(al *writer.AsyncLogger) metric.Source {
+	return func() []metric.Sample {
+		return []metric.Sample{
+			{Name: "async_queue_depth", Help: "Entries waiting in the async queue.", Type: metric.TypeGauge, Value: float64(al.QueueDepth())},
+			{Name: "async_dropped_total", Help: "Entries dropped because the async queue was full.", Type: metric.TypeCounter, Value: float64(al.Dropped())},
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/formatter"
+	"github.com/Lunar-Chipter/mire/hook"
+	"github.com/Lunar-Chipter/mire/metric"
+)
+
+// failingHook is a hook that always returns an error
+type failingHook struct{}
+
+func (h *failingHook) Fire(entry *core.LogEntry) error { return errors.New("hook failed") }
+func (h *failingHook) Close() error                    { return nil }
+
+// gaugeOnlyCollector implements only the base Collector interface
+type gaugeOnlyCollector struct {
+	mu       sync.Mutex
+	counts   map[core.Level]int
+	gauges   map[string]float64
+	lastTags map[string]string
+}
+
+func newGaugeOnlyCollector() *gaugeOnlyCollector {
+	return &gaugeOnlyCollector{counts: make(map[core.Level]int), gauges: make(map[string]float64)}
+}
+
+func (c *gaugeOnlyCollector) IncrementCounter(level core.Level, tags map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[level]++
+	c.lastTags = tags
+}
+
+func (c *gaugeOnlyCollector) RecordHistogram(metric string, value float64, tags map[string]string) {}
+
+func (c *gaugeOnlyCollector) RecordGauge(metric string, value float64, tags map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gauges[metric] = value
+}
+
+// TestLoggerMetricsLevelsAndBytes tests per-level counts and bytes written
+func TestLoggerMetricsLevelsAndBytes(t *testing.T) {
+	var buf bytes.Buffer
+	collector := metric.NewMetrics()
+	logger := New(LoggerConfig{
+		Level:         core.INFO,
+		Output:        &buf,
+		Formatter:     &formatter.TextFormatter{},
+		EnableMetrics: true,
+		Collector:     collector,
+	})
+	defer logger.Close()
+
+	logger.Info("first")
+	logger.Info("second")
+	logger.Error("third")
+	logger.Debug("filtered")
+
+	if got := collector.GetCounter("log.info"); got != 2 {
+		t.Errorf("Expected 2 info entries, got %d", got)
+	}
+	if got := collector.GetCounter("log.error"); got != 1 {
+		t.Errorf("Expected 1 error entry, got %d", got)
+	}
+	if got := collector.GetCounter("log.debug"); got != 0 {
+		t.Errorf("Filtered entries must not be counted, got %d", got)
+	}
+	if got := collector.GetCounter(metric.BytesWritten); got != int64(buf.Len()) {
+		t.Errorf("Expected %d bytes written, got %d", buf.Len(), got)
+	}
+	if min, _, _, _ := collector.GetHistogram(metric.WriteDuration); min < 0 {
+		t.Errorf("Write duration must not be negative, got %v", min)
+	}
+}
+
+// TestLoggerMetricsDisabled tests that a collector is ignored without EnableMetrics
+func TestLoggerMetricsDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	collector := metric.NewMetrics()
+	logger := New(LoggerConfig{
+		Level:     core.INFO,
+		Output:    &buf,
+		Formatter: &formatter.TextFormatter{},
+		Collector: collector,
+	})
+	defer logger.Close()
+
+	logger.Info("not counted")
+
+	if got := collector.GetCounter("log.info"); got != 0 {
+		t.Errorf("Metrics should not be reported when disabled, got %d", got)
+	}
+}
+
+// TestLoggerMetricsDefaultCollector tests that EnableMetrics creates a collector
+func TestLoggerMetricsDefaultCollector(t *testing.T) {
+	logger := New(LoggerConfig{
+		Output:        &bytes.Buffer{},
+		Formatter:     &formatter.TextFormatter{},
+		EnableMetrics: true,
+	})
+	defer logger.Close()
+
+	collector, ok := logger.Config.Collector.(*metric.Metrics)
+	if !ok {
+		t.Fatalf("Expected default *metric.Metrics collector, got %T", logger.Config.Collector)
+	}
+	logger.Info("counted")
+	if got := collector.GetCounter("log.info"); got != 1 {
+		t.Errorf("Expected 1 info entry, got %d", got)
+	}
+}
+
+// TestLoggerMetricsHookFailures tests hook failure counting
+func TestLoggerMetricsHookFailures(t *testing.T) {
+	collector := metric.NewMetrics()
+	logger := New(LoggerConfig{
+		Output:        &bytes.Buffer{},
+		Formatter:     &formatter.TextFormatter{},
+		Hooks:         []hook.Hook{&failingHook{}},
+		ErrorHandler:  func(error) {},
+		EnableMetrics: true,
+		Collector:     collector,
+	})
+	defer logger.Close()
+
+	logger.Info("one")
+	logger.Info("two")
+
+	if got := collector.GetCounter(metric.HookFailures); got != 2 {
+		t.Errorf("Expected 2 hook failures, got %d", got)
+	}
+}
+
+// TestLoggerMetricsTagsAndGaugeFallback tests static tags and gauge totals for basic collectors
+func TestLoggerMetricsTagsAndGaugeFallback(t *testing.T) {
+	var buf bytes.Buffer
+	collector := newGaugeOnlyCollector()
+	logger := New(LoggerConfig{
+		Output:        &buf,
+		Formatter:     &formatter.TextFormatter{},
+		Application:   "billing",
+		Environment:   "staging",
+		EnableMetrics: true,
+		Collector:     collector,
+	})
+	defer logger.Close()
+
+	logger.Warn("tagged")
+	logger.Warn("tagged again")
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	if collector.counts[core.WARN] != 2 {
+		t.Errorf("Expected 2 warn entries, got %d", collector.counts[core.WARN])
+	}
+	if collector.lastTags["application"] != "billing" || collector.lastTags["environment"] != "staging" {
+		t.Errorf("Unexpected tags %v", collector.lastTags)
+	}
+	if got := collector.gauges[metric.BytesWritten]; got != float64(buf.Len()) {
+		t.Errorf("Expected bytes written gauge %d, got %v", buf.Len(), got)
+	}
+}
+
+// TestLoggerMetricsAsync tests that async entries are written and the queue depth is reported
+func TestLoggerMetricsAsync(t *testing.T) {
+	var buf syncBuffer
+	collector := newGaugeOnlyCollector()
+	logger := New(LoggerConfig{
+		Output:        &buf,
+		Formatter:     &formatter.TextFormatter{},
+		AsyncMode:     true,
+		WorkerCount:   1,
+		ChannelSize:   16,
+		EnableMetrics: true,
+		Collector:     collector,
+	})
+
+	logger.Info("async message")
+	logger.Close()
+
+	if !strings.Contains(buf.String(), "async message") {
+		t.Errorf("Async entry should be written, got %q", buf.String())
+	}
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	if _, ok := collector.gauges[metric.AsyncQueueDepth]; !ok {
+		t.Error("Async queue depth should be reported")
+	}
+}
+
+// TestLoggerMetricsRecordDrops tests that drop totals are reported as deltas
+func TestLoggerMetricsRecordDrops(t *testing.T) {
+	collector := metric.NewMetrics()
+	m := newLoggerMetrics(collector, "", "")
+	var last atomic.Int64
+
+	m.recordDrops(metric.BufferDropped, &last, 3)
+	m.recordDrops(metric.BufferDropped, &last, 3)
+	m.recordDrops(metric.BufferDropped, &last, 5)
+	m.recordDrops(metric.BufferDropped, &last, 4) // Stale total must be ignored
+
+	if got := collector.GetCounter(metric.BufferDropped); got != 5 {
+		t.Errorf("Expected 5 drops, got %d", got)
+	}
+}
+
+// TestLoggerMetricsHandler tests the Prometheus scrape endpoint of a logger
+func TestLoggerMetricsHandler(t *testing.T) {
+	logger := New(LoggerConfig{
+		Level:         core.INFO,
+		Output:        &bytes.Buffer{},
+		Formatter:     &formatter.TextFormatter{},
+		Application:   "billing",
+		EnableMetrics: true,
+	})
+	defer logger.Close()
+
+	logger.Info("one")
+	logger.Info("two")
+	logger.Warn("three")
+
+	rec := httptest.NewRecorder()
+	logger.MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+
+	for _, want := range []string{
+		`mire_log_info_total{application="billing"} 2`,
+		"# TYPE mire_log_write_duration_seconds histogram",
+		`mire_log_write_duration_seconds_count{application="billing"} 3`,
+		`mire_logger_entries_total{level="info"} 2`,
+		`mire_logger_entries_total{level="warn"} 1`,
+		"# TYPE mire_logger_bytes_written_total counter",
+		"# TYPE mire_core_entries_created_total counter",
+		"# TYPE mire_pool_buffer_gets_total counter",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected scrape output to contain %q, got:\n%s", want, body)
+		}
+	}
+}
+
+// TestLoggerMetricsHandlerWithoutCollector tests that stats are exported without a *metric.Metrics collector
+func TestLoggerMetricsHandlerWithoutCollector(t *testing.T) {
+	logger := New(LoggerConfig{
+		Level:     core.INFO,
+		Output:    &bytes.Buffer{},
+		Formatter: &formatter.TextFormatter{},
+	})
+	defer logger.Close()
+
+	logger.Error("boom")
+
+	rec := httptest.NewRecorder()
+	logger.MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	if !strings.Contains(body, `mire_logger_entries_total{level="error"} 1`) {
+		t.Errorf("Expected logger stats in scrape output, got:\n%s", body)
+	}
+	if strings.Contains(body, "mire_log_error_total") {
+		t.Errorf("Collector metrics should not be exported when metrics are disabled, got:\n%s", body)
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
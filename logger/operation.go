@@ -0,0 +1,147 @@
+package logger
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"github.com/Lunar-Chipter/mire/core"
+)
+
+// operationKey is the context key of the active operation
+type operationKey struct{}
+
+// operationDurationKey is the context key carrying the elapsed time of an ended operation
+// through the async workers to the entry
+type operationDurationKey struct{}
+
+// Operation times a unit of work. End and EndWithError log the elapsed time in
+// LogEntry.Duration together with the operation name and ID. Operations started
+// with the operation's Context are its children and log the parent ID.
+type Operation struct {
+	logger    *Logger
+	ctx       context.Context
+	name      string
+	id        string
+	parentID  string
+	fields    []Field
+	start     time.Time
+	threshold time.Duration
+	ended     atomic.Bool
+}
+
+// StartOperation starts timing an operation. The fields are logged when the operation ends.
+func (l *Logger) StartOperation(ctx context.Context, name string, fields ...Field) *Operation {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	op := &Operation{
+		logger:    l,
+		name:      name,
+		id:        newOperationID(),
+		fields:    fields,
+		threshold: l.Config.SlowOperationThreshold,
+	}
+	if parent := OperationFromContext(ctx); parent != nil {
+		op.parentID = parent.id
+	}
+	op.ctx = context.WithValue(ctx, operationKey{}, op)
+	op.start = time.Now()
+	return op
+}
+
+// OperationFromContext returns the operation stored in ctx by StartOperation, or nil
+func OperationFromContext(ctx context.Context) *Operation {
+	if ctx == nil {
+		return nil
+	}
+	op, _ := ctx.Value(operationKey{}).(*Operation)
+	return op
+}
+
+// WithSlowThreshold sets the threshold below which End does not log, overriding
+// LoggerConfig.SlowOperationThreshold. A zero threshold logs every operation.
+func (op *Operation) WithSlowThreshold(threshold time.Duration) *Operation {
+	op.threshold = threshold
+	return op
+}
+
+// Context returns a context carrying the operation. Operations started with it are children.
+func (op *Operation) Context() context.Context {
+	return op.ctx
+}
+
+// ID returns the operation ID
+func (op *Operation) ID() string {
+	return op.id
+}
+
+// ParentID returns the ID of the parent operation, or "" for a root operation
+func (op *Operation) ParentID() string {
+	return op.parentID
+}
+
+// End ends the operation and returns the elapsed time. The operation is logged at INFO,
+// or at WARN when a slow threshold is set and exceeded. Faster operations are not logged
+// when a threshold is set. Calls after the first return 0 and log nothing.
+func (op *Operation) End(fields ...Field) time.Duration {
+	return op.end(nil, fields)
+}
+
+// EndWithError ends the operation like End. A non-nil err is always logged at ERROR
+// regardless of the slow threshold.
+func (op *Operation) EndWithError(err error, fields ...Field) time.Duration {
+	return op.end(err, fields)
+}
+
+func (op *Operation) end(err error, fields []Field) time.Duration {
+	if !op.ended.CompareAndSwap(false, true) {
+		return 0
+	}
+	elapsed := time.Since(op.start)
+
+	level := core.INFO
+	switch {
+	case err != nil:
+		level = core.ERROR
+	case op.threshold > 0 && elapsed < op.threshold:
+		return elapsed
+	case op.threshold > 0:
+		level = core.WARN
+	}
+
+	all := make([]Field, 0, len(op.fields)+len(fields)+3)
+	all = append(all, String("operation", op.name), String("operation_id", op.id))
+	if op.parentID != "" {
+		all = append(all, String("parent_operation_id", op.parentID))
+	}
+	all = append(all, op.fields...)
+	all = append(all, fields...)
+
+	ctx := context.WithValue(op.ctx, operationDurationKey{}, elapsed)
+	op.logger.logTyped(ctx, level, core.StringToBytes(op.name), err, all)
+	return elapsed
+}
+
+// applyOperationDuration sets the entry duration when ctx comes from an ended operation
+func applyOperationDuration(ctx context.Context, entry *core.LogEntry) {
+	if ctx == nil {
+		return
+	}
+	if d, ok := ctx.Value(operationDurationKey{}).(time.Duration); ok {
+		entry.Duration = d
+	}
+}
+
+// newOperationID returns a random 16 digit hex ID
+func newOperationID() string {
+	const hexDigits = "0123456789abcdef"
+	var id [16]byte
+	n := rand.Uint64()
+	for i := len(id) - 1; i >= 0; i-- {
+		id[i] = hexDigits[n&0xf]
+		n >>= 4
+	}
+	return string(id[:])
+}
@@ -0,0 +1,151 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/formatter"
+)
+
+// newOperationTestLogger creates a JSON logger that shows durations
+func newOperationTestLogger(config LoggerConfig) (*Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	jf := formatter.NewJSON()
+	jf.ShowDuration = true
+	config.Level = core.INFO
+	config.Output = &buf
+	config.Formatter = jf
+	return New(config), &buf
+}
+
+// operationLine is the decoded form of an operation log line
+type operationLine struct {
+	Level    string `json:"level_name"`
+	Message  string `json:"message"`
+	Duration int64  `json:"duration"`
+	Error    string `json:"error"`
+	Fields   struct {
+		Operation string `json:"operation"`
+		ID        string `json:"operation_id"`
+		ParentID  string `json:"parent_operation_id"`
+		Rows      int    `json:"rows"`
+	} `json:"fields"`
+}
+
+// decodeOperationLines decodes every JSON log line in buf
+func decodeOperationLines(t *testing.T, buf *bytes.Buffer) []operationLine {
+	t.Helper()
+	var lines []operationLine
+	for _, raw := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if raw == "" {
+			continue
+		}
+		var line operationLine
+		if err := json.Unmarshal([]byte(raw), &line); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, raw)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// TestOperationEnd tests that ending an operation logs its duration and fields
+func TestOperationEnd(t *testing.T) {
+	l, buf := newOperationTestLogger(LoggerConfig{})
+	defer l.Close()
+
+	op := l.StartOperation(context.Background(), "db.query", String("table", "users"))
+	time.Sleep(2 * time.Millisecond)
+	elapsed := op.End(Int("rows", 3))
+
+	if elapsed < 2*time.Millisecond {
+		t.Errorf("Expected elapsed time of at least 2ms, got %v", elapsed)
+	}
+	if op.End() != 0 {
+		t.Error("Ending an operation twice should return 0")
+	}
+
+	lines := decodeOperationLines(t, buf)
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 log line, got %d: %s", len(lines), buf.String())
+	}
+	line := lines[0]
+	if line.Level != "INFO" || line.Message != "db.query" || line.Fields.Operation != "db.query" {
+		t.Errorf("Unexpected operation line %+v", line)
+	}
+	if line.Duration != int64(elapsed) {
+		t.Errorf("Expected duration %d, got %d", elapsed, line.Duration)
+	}
+	if line.Fields.ID != op.ID() || len(op.ID()) != 16 || line.Fields.Rows != 3 {
+		t.Errorf("Unexpected operation fields %+v", line.Fields)
+	}
+}
+
+// TestOperationNested tests parent and child operation IDs
+func TestOperationNested(t *testing.T) {
+	l, buf := newOperationTestLogger(LoggerConfig{})
+	defer l.Close()
+
+	parent := l.StartOperation(context.Background(), "checkout")
+	child := l.StartOperation(parent.Context(), "charge")
+	if child.ParentID() != parent.ID() || parent.ParentID() != "" {
+		t.Fatalf("Expected child of %s, got parent %q", parent.ID(), child.ParentID())
+	}
+	if OperationFromContext(child.Context()) != child {
+		t.Error("Expected the child operation in its context")
+	}
+	child.End()
+	parent.End()
+
+	lines := decodeOperationLines(t, buf)
+	if len(lines) != 2 || lines[0].Fields.ParentID != parent.ID() || lines[1].Fields.ParentID != "" {
+		t.Errorf("Unexpected nested operation lines: %s", buf.String())
+	}
+}
+
+// TestOperationSlowThreshold tests that fast operations are skipped and slow ones warn
+func TestOperationSlowThreshold(t *testing.T) {
+	l, buf := newOperationTestLogger(LoggerConfig{SlowOperationThreshold: time.Hour})
+	defer l.Close()
+
+	l.StartOperation(context.Background(), "fast").End()
+	if buf.Len() != 0 {
+		t.Errorf("Expected fast operation to be skipped, got %s", buf.String())
+	}
+
+	op := l.StartOperation(context.Background(), "slow").WithSlowThreshold(time.Millisecond)
+	time.Sleep(2 * time.Millisecond)
+	op.End()
+
+	l.StartOperation(context.Background(), "failed").EndWithError(errors.New("timeout"))
+
+	lines := decodeOperationLines(t, buf)
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got %d: %s", len(lines), buf.String())
+	}
+	if lines[0].Level != "WARN" || lines[0].Message != "slow" {
+		t.Errorf("Expected slow operation at WARN, got %+v", lines[0])
+	}
+	if lines[1].Level != "ERROR" || lines[1].Error != "timeout" || lines[1].Duration <= 0 {
+		t.Errorf("Expected failed operation at ERROR, got %+v", lines[1])
+	}
+}
+
+// TestOperationAsync tests that the duration survives the async workers
+func TestOperationAsync(t *testing.T) {
+	l, buf := newOperationTestLogger(LoggerConfig{AsyncMode: true, WorkerCount: 1, ChannelSize: 10})
+
+	elapsed := l.StartOperation(context.Background(), "async").End()
+	l.Close()
+
+	lines := decodeOperationLines(t, buf)
+	if len(lines) != 1 || lines[0].Duration != int64(elapsed) {
+		t.Errorf("Expected async operation with duration %d, got %s", elapsed, buf.String())
+	}
+}
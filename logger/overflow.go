@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/writer"
+)
+
+// overflowConfig returns the overflow policy for the async queue and the buffered writer
+func (l *Logger) overflowConfig() writer.OverflowConfig {
+	return writer.OverflowConfig{
+		Policy:   l.Config.OverflowPolicy,
+		Timeout:  l.Config.OverflowTimeout,
+		MinLevel: l.Config.OverflowMinLevel,
+	}
+}
+
+// writeOut writes a formatted entry, passing its level to the buffered writer for the overflow policy
+func (l *Logger) writeOut(level core.Level, p []byte) (int, error) {
+	if l.buffer != nil {
+		return l.buffer.WriteLevel(level, p)
+	}
+	return l.out.Write(p)
+}
+
+// OverflowStats returns the combined overflow outcomes of the async queue and the buffered writers
+func (l *Logger) OverflowStats() writer.OverflowStats {
+	var stats writer.OverflowStats
+	if l.asyncLogger != nil {
+		stats = l.asyncLogger.OverflowStats()
+	}
+	if l.buffer != nil {
+		addOverflowStats(&stats, l.buffer.OverflowStats())
+	}
+	for _, s := range l.sinks {
+		if s.buffer != nil {
+			addOverflowStats(&stats, s.buffer.OverflowStats())
+		}
+	}
+	return stats
+}
+
+// addOverflowStats adds the counters of b to stats
+func addOverflowStats(stats *writer.OverflowStats, b writer.OverflowStats) {
+	stats.Blocked += b.Blocked
+	stats.TimedOut += b.TimedOut
+	stats.DroppedNewest += b.DroppedNewest
+	stats.DroppedOldest += b.DroppedOldest
+	stats.DroppedBelowLevel += b.DroppedBelowLevel
+	stats.Spilled += b.Spilled
+}
+
+// dropSummary periodically writes a WARN entry with the number of entries dropped
+// since the previous summary, so that losses are visible in the log stream itself
+type dropSummary struct {
+	logger   *Logger
+	reported int64
+	quit     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// newDropSummary starts reporting drops every interval
+func newDropSummary(l *Logger, interval time.Duration) *dropSummary {
+	s := &dropSummary{
+		logger: l,
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go s.run(interval)
+	return s
+}
+
+func (s *dropSummary) run(interval time.Duration) {
+	defer close(s.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.report()
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// report writes a summary entry if entries were dropped since the last one.
+// The entry is written directly, bypassing the async queue.
+func (s *dropSummary) report() {
+	dropped := s.logger.OverflowStats().Dropped()
+	delta := dropped - s.reported
+	if delta <= 0 {
+		return
+	}
+	s.reported = dropped
+
+	msg := strconv.AppendInt(nil, delta, 10)
+	msg = append(msg, " logs dropped"...)
+	s.logger.writeTyped(context.Background(), core.WARN, msg, nil, []Field{
+		Int64("dropped", delta),
+		String("policy", s.logger.Config.OverflowPolicy.String()),
+	})
+}
+
+// stop ends periodic reporting and writes a final summary
+func (s *dropSummary) stop() {
+	s.once.Do(func() {
+		close(s.quit)
+		<-s.done
+		s.report()
+	})
+}
@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/formatter"
+	"github.com/Lunar-Chipter/mire/writer"
+)
+
+// gatedWriter blocks writes until open is closed, then writes to out
+type gatedWriter struct {
+	open chan struct{}
+	out  lockedBuffer
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	<-w.open
+	return w.out.Write(p)
+}
+
+// TestLoggerDropSummary tests that dropped entries are reported in the log stream
+func TestLoggerDropSummary(t *testing.T) {
+	out := &gatedWriter{open: make(chan struct{})}
+	l := New(LoggerConfig{
+		Level:               core.INFO,
+		Output:              out,
+		Formatter:           &formatter.TextFormatter{},
+		AsyncMode:           true,
+		WorkerCount:         1,
+		ChannelSize:         1,
+		NoTimeout:           true,
+		OverflowPolicy:      writer.DropBelowLevel,
+		DropSummaryInterval: time.Hour,
+	})
+
+	for i := 0; i < 20; i++ {
+		l.Info("noise")
+	}
+	errorsDone := make(chan struct{})
+	go func() {
+		defer close(errorsDone)
+		for i := 0; i < 3; i++ {
+			l.Error("important")
+		}
+	}()
+
+	// Let the error writers block on the full queue before opening the output
+	time.Sleep(10 * time.Millisecond)
+	close(out.open)
+	<-errorsDone
+
+	stats := l.OverflowStats()
+	if stats.DroppedBelowLevel == 0 || stats.DroppedNewest != 0 {
+		t.Errorf("Expected only INFO entries to be dropped, got %+v", stats)
+	}
+	l.Close()
+
+	got := out.out.String()
+	if n := strings.Count(got, "important"); n != 3 {
+		t.Errorf("Expected all 3 ERROR entries, got %d:\n%s", n, got)
+	}
+	want := "logs dropped"
+	if !strings.Contains(got, want) || !strings.Contains(got, "policy=drop-below-level") {
+		t.Errorf("Expected a drop summary in the output, got:\n%s", got)
+	}
+}
+
+// failingWriter rejects every write, like an unreachable sink
+type failingWriter struct{}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
+
+// TestLoggerSpill tests that a failing output spills entries to disk instead of dropping them
+func TestLoggerSpill(t *testing.T) {
+	dir := t.TempDir()
+	failing := &failingWriter{}
+	l := New(LoggerConfig{
+		Level:         core.INFO,
+		Output:        failing,
+		Formatter:     &formatter.TextFormatter{},
+		BufferSize:    4,
+		BatchSize:     1,
+		FlushInterval: time.Hour,
+		SpillDir:      dir,
+		ErrorHandler:  func(error) {},
+	})
+	for i := 0; i < 10; i++ {
+		l.Info("kept")
+	}
+	_ = l.Sync(context.Background())
+	l.Close()
+
+	stats := l.OverflowStats()
+	if stats.Dropped() != 0 || stats.Spilled == 0 {
+		t.Errorf("Expected spills and no drops, got %+v", stats)
+	}
+	if segs, _ := filepath.Glob(filepath.Join(dir, "*.seg")); len(segs) == 0 {
+		t.Error("Expected spilled entries to stay on disk after Close")
+	}
+}
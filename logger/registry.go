@@ -0,0 +1,231 @@
+package logger
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Lunar-Chipter/mire/core"
+)
+
+// DefaultLevelRulesEnv is the conventional environment variable holding level rules
+const DefaultLevelRulesEnv = "MIRE_LOG_LEVELS"
+
+// LevelRule overrides the level of named loggers matching Pattern.
+//
+// A plain name such as "http" matches the logger "http" and every logger below it
+// ("http.server", "http.client.pool"). A trailing wildcard such as "db.*" matches
+// only loggers below "db". The pattern "*" matches every named logger.
+// When several rules match, the one with the longest name wins; for loggers below
+// a name, "db.*" wins over "db".
+type LevelRule struct {
+	Pattern string
+	Level   core.Level
+}
+
+// ParseLevelRules parses a comma-separated list of pattern=LEVEL pairs,
+// for example "db.*=DEBUG,http=WARN". Empty entries are ignored.
+func ParseLevelRules(s string) ([]LevelRule, error) {
+	var rules []LevelRule
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		eq := strings.IndexByte(part, '=')
+		if eq < 0 {
+			return nil, newErrorf("invalid level rule %q: expected pattern=LEVEL", part)
+		}
+		pattern := strings.TrimSpace(part[:eq])
+		if !validLevelPattern(pattern) {
+			return nil, newErrorf("invalid level rule pattern %q", pattern)
+		}
+		level, err := core.ParseLevel(strings.TrimSpace(part[eq+1:]))
+		if err != nil {
+			return nil, newErrorf("invalid level rule %q: %w", part, err)
+		}
+		rules = append(rules, LevelRule{Pattern: pattern, Level: level})
+	}
+	return rules, nil
+}
+
+// validLevelPattern reports whether a wildcard only appears as "*" or a trailing ".*"
+func validLevelPattern(pattern string) bool {
+	if pattern == "*" {
+		return true
+	}
+	prefix := strings.TrimSuffix(pattern, ".*")
+	return prefix != "" && !strings.Contains(prefix, "*")
+}
+
+// LevelRegistry holds the level rules shared by a logger and all its named children
+type LevelRegistry struct {
+	mu    sync.RWMutex
+	rules []LevelRule
+	gen   atomic.Uint64 // Bumped on every change so loggers can cache their resolved level
+}
+
+// NewLevelRegistry creates a registry with the given rules
+func NewLevelRegistry(rules ...LevelRule) *LevelRegistry {
+	r := &LevelRegistry{}
+	r.gen.Store(1)
+	r.SetRules(rules)
+	return r
+}
+
+// SetRules replaces all rules
+func (r *LevelRegistry) SetRules(rules []LevelRule) {
+	r.mu.Lock()
+	r.rules = append([]LevelRule(nil), rules...)
+	r.mu.Unlock()
+	r.gen.Add(1)
+}
+
+// SetLevel adds a rule for pattern, replacing any existing rule with the same pattern
+func (r *LevelRegistry) SetLevel(pattern string, level core.Level) {
+	r.mu.Lock()
+	replaced := false
+	for i := range r.rules {
+		if r.rules[i].Pattern == pattern {
+			r.rules[i].Level = level
+			replaced = true
+		}
+	}
+	if !replaced {
+		r.rules = append(r.rules, LevelRule{Pattern: pattern, Level: level})
+	}
+	r.mu.Unlock()
+	r.gen.Add(1)
+}
+
+// Remove deletes the rule for pattern
+func (r *LevelRegistry) Remove(pattern string) {
+	r.mu.Lock()
+	kept := r.rules[:0]
+	for _, rule := range r.rules {
+		if rule.Pattern != pattern {
+			kept = append(kept, rule)
+		}
+	}
+	r.rules = kept
+	r.mu.Unlock()
+	r.gen.Add(1)
+}
+
+// Rules returns a copy of the current rules
+func (r *LevelRegistry) Rules() []LevelRule {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]LevelRule(nil), r.rules...)
+}
+
+// Parse replaces all rules with the ones parsed from s
+func (r *LevelRegistry) Parse(s string) error {
+	rules, err := ParseLevelRules(s)
+	if err != nil {
+		return err
+	}
+	r.SetRules(rules)
+	return nil
+}
+
+// LoadEnv replaces all rules with the ones in the environment variable key.
+// The rules are left untouched when the variable is unset.
+func (r *LevelRegistry) LoadEnv(key string) error {
+	s, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+	return r.Parse(s)
+}
+
+// Lookup returns the level of the most specific rule matching name
+func (r *LevelRegistry) Lookup(name string) (core.Level, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	best := -1
+	var level core.Level
+	for _, rule := range r.rules {
+		if score := matchLevelPattern(rule.Pattern, name); score > best {
+			best = score
+			level = rule.Level
+		}
+	}
+	return level, best >= 0
+}
+
+// matchLevelPattern returns how specific a match of pattern against name is, or -1
+func matchLevelPattern(pattern, name string) int {
+	if pattern == "*" {
+		return 0
+	}
+	if prefix, ok := strings.CutSuffix(pattern, ".*"); ok {
+		if len(name) > len(prefix)+1 && strings.HasPrefix(name, prefix) && name[len(prefix)] == '.' {
+			return 2*len(prefix) + 1
+		}
+		return -1
+	}
+	if name == pattern || (strings.HasPrefix(name, pattern) && name[len(pattern)] == '.') {
+		return 2 * len(pattern)
+	}
+	return -1
+}
+
+// Named creates a child logger whose name is appended to the parent's with a dot.
+// The name is added to entries as the "logger" field, and rules in the level
+// registry matching the name take precedence over the base level.
+func (l *Logger) Named(name string) *Logger {
+	if name == "" {
+		return l
+	}
+	newLogger := l.clone()
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+	newLogger.name = name
+	newLogger.levelCache = &atomic.Uint64{}
+	newLogger.setField("logger", []byte(name))
+	return newLogger
+}
+
+// Name returns the dotted name of the logger, or "" for the root logger
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// LevelRegistry returns the level rules shared by this logger and its named children
+func (l *Logger) LevelRegistry() *LevelRegistry {
+	return l.registry
+}
+
+// Level cache layout: registry generation in the upper bits, a matched flag, and the level
+const (
+	levelCacheMatched  = 1 << 8
+	levelCacheGenShift = 9
+)
+
+// minLevel returns the level entries must reach to be logged
+func (l *Logger) minLevel() core.Level {
+	if l.levelCache == nil {
+		return l.level.Level()
+	}
+
+	gen := l.registry.gen.Load()
+	cached := l.levelCache.Load()
+	if cached>>levelCacheGenShift != gen {
+		level, ok := l.registry.Lookup(l.name)
+		cached = gen<<levelCacheGenShift | uint64(uint8(level))
+		if ok {
+			cached |= levelCacheMatched
+		}
+		l.levelCache.Store(cached)
+	}
+
+	if cached&levelCacheMatched != 0 {
+		return core.Level(int8(uint8(cached)))
+	}
+	return l.level.Level()
+}
@@ -0,0 +1,182 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/formatter"
+)
+
+// TestParseLevelRules tests parsing rule lists
+func TestParseLevelRules(t *testing.T) {
+	rules, err := ParseLevelRules(" db.*=DEBUG, http=warn,,*=error ")
+	if err != nil {
+		t.Fatalf("ParseLevelRules returned error: %v", err)
+	}
+	want := []LevelRule{{"db.*", core.DEBUG}, {"http", core.WARN}, {"*", core.ERROR}}
+	if len(rules) != len(want) {
+		t.Fatalf("Expected %d rules, got %+v", len(want), rules)
+	}
+	for i := range want {
+		if rules[i] != want[i] {
+			t.Errorf("Rule %d: expected %+v, got %+v", i, want[i], rules[i])
+		}
+	}
+
+	for _, invalid := range []string{"db", "=DEBUG", "db=LOUD", "d*b=INFO", "*.db=INFO", ".*=INFO"} {
+		if _, err := ParseLevelRules(invalid); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+// TestLevelRegistryLookup tests rule matching and precedence
+func TestLevelRegistryLookup(t *testing.T) {
+	r := NewLevelRegistry(
+		LevelRule{"*", core.ERROR},
+		LevelRule{"db.*", core.DEBUG},
+		LevelRule{"db", core.WARN},
+		LevelRule{"http", core.INFO},
+		LevelRule{"db.pool", core.TRACE},
+	)
+
+	tests := []struct {
+		name string
+		want core.Level
+	}{
+		{"db", core.WARN},
+		{"db.conn", core.DEBUG},
+		{"db.pool", core.TRACE},
+		{"db.pool.idle", core.TRACE},
+		{"http", core.INFO},
+		{"http.server", core.INFO},
+		{"httpd", core.ERROR},
+		{"cache", core.ERROR},
+	}
+	for _, tt := range tests {
+		level, ok := r.Lookup(tt.name)
+		if !ok || level != tt.want {
+			t.Errorf("Lookup(%q) = %v, %v; want %v", tt.name, level, ok, tt.want)
+		}
+	}
+
+	r.Remove("*")
+	if _, ok := r.Lookup("cache"); ok {
+		t.Error("Expected no match after removing the wildcard rule")
+	}
+
+	r.SetLevel("http", core.WARN)
+	if level, _ := r.Lookup("http"); level != core.WARN {
+		t.Errorf("Expected SetLevel to replace the http rule, got %v", level)
+	}
+	if len(r.Rules()) != 4 {
+		t.Errorf("Expected 4 rules, got %+v", r.Rules())
+	}
+}
+
+// TestLevelRegistryLoadEnv tests loading rules from an environment variable
+func TestLevelRegistryLoadEnv(t *testing.T) {
+	r := NewLevelRegistry(LevelRule{"db", core.WARN})
+
+	if err := r.LoadEnv("MIRE_TEST_UNSET_LEVELS"); err != nil || len(r.Rules()) != 1 {
+		t.Errorf("Unset variable should leave rules untouched, got %+v, %v", r.Rules(), err)
+	}
+
+	t.Setenv("MIRE_TEST_LEVELS", "http=DEBUG")
+	if err := r.LoadEnv("MIRE_TEST_LEVELS"); err != nil {
+		t.Fatalf("LoadEnv returned error: %v", err)
+	}
+	if rules := r.Rules(); len(rules) != 1 || rules[0].Pattern != "http" {
+		t.Errorf("Expected rules to be replaced, got %+v", rules)
+	}
+}
+
+// TestLoggerNamed tests child logger names and the logger field
+func TestLoggerNamed(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(LoggerConfig{Level: core.INFO, Output: &buf, Formatter: &formatter.TextFormatter{}})
+	defer l.Close()
+
+	pool := l.Named("db").Named("pool")
+	if pool.Name() != "db.pool" {
+		t.Errorf("Expected name db.pool, got %q", pool.Name())
+	}
+	if l.Name() != "" || l.Named("") != l {
+		t.Error("Root logger should stay unnamed")
+	}
+
+	pool.Info("connected")
+	if !strings.Contains(buf.String(), "logger=db.pool") {
+		t.Errorf("Expected logger field in output, got %s", buf.String())
+	}
+}
+
+// TestLoggerNamedLevelRules tests that registry rules override the base level
+func TestLoggerNamedLevelRules(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(LoggerConfig{
+		Level:      core.INFO,
+		Output:     &buf,
+		Formatter:  &formatter.TextFormatter{},
+		LevelRules: "db.*=DEBUG,http=WARN",
+	})
+	defer l.Close()
+
+	pool := l.Named("db").Named("pool")
+	httpLog := l.Named("http")
+	cache := l.Named("cache")
+
+	pool.Debug("pool debug")
+	httpLog.Info("http info")
+	cache.Debug("cache debug")
+	cache.Info("cache info")
+	l.Debug("root debug")
+
+	output := buf.String()
+	if !strings.Contains(output, "pool debug") {
+		t.Error("Expected db.pool DEBUG to pass")
+	}
+	if strings.Contains(output, "http info") {
+		t.Error("Expected http INFO to be filtered")
+	}
+	if strings.Contains(output, "cache debug") || !strings.Contains(output, "cache info") {
+		t.Error("Expected unmatched logger to use the base level")
+	}
+	if strings.Contains(output, "root debug") {
+		t.Error("Expected root logger to use the base level")
+	}
+
+	// Changing the registry applies to existing loggers and their clones
+	withFields := httpLog.WithFields(map[string]interface{}{"k": "v"})
+	l.LevelRegistry().SetLevel("http", core.DEBUG)
+	if httpLog.GetLevel() != core.DEBUG || withFields.GetLevel() != core.DEBUG {
+		t.Errorf("Expected http loggers at DEBUG, got %v and %v", httpLog.GetLevel(), withFields.GetLevel())
+	}
+	if withFields.Name() != "http" {
+		t.Errorf("Expected clone to keep the name, got %q", withFields.Name())
+	}
+
+	// The base level still applies to loggers without a rule
+	l.SetLevel(core.ERROR)
+	if cache.GetLevel() != core.ERROR || pool.GetLevel() != core.DEBUG {
+		t.Errorf("Unexpected levels after SetLevel: cache %v, pool %v", cache.GetLevel(), pool.GetLevel())
+	}
+}
+
+// TestLoggerLevelRulesEnv tests loading level rules from the configured environment variable
+func TestLoggerLevelRulesEnv(t *testing.T) {
+	t.Setenv("MIRE_TEST_LOGGER_LEVELS", "worker=TRACE")
+	l := New(LoggerConfig{
+		Level:         core.INFO,
+		Output:        &bytes.Buffer{},
+		Formatter:     &formatter.TextFormatter{},
+		LevelRulesEnv: "MIRE_TEST_LOGGER_LEVELS",
+	})
+	defer l.Close()
+
+	if level := l.Named("worker").GetLevel(); level != core.TRACE {
+		t.Errorf("Expected TRACE from environment, got %v", level)
+	}
+}
@@ -0,0 +1,147 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/formatter"
+	"github.com/Lunar-Chipter/mire/util"
+	"github.com/Lunar-Chipter/mire/writer"
+)
+
+// Sink is an additional output of a Logger with its own formatter, minimum
+// level and buffering. Entries still pass the logger's level first, so set it
+// no higher than the lowest sink level. A failing or panicking sink does not
+// affect the others; its errors go to the logger's error handler.
+type Sink struct {
+	Name          string                    // Name used in error messages (defaults to the sink's index)
+	Output        io.Writer                 // Writer for entries, closed with the logger unless it is stdout or stderr
+	Formatter     formatter.Formatter       // Formatter for entries (defaults to the logger's formatter)
+	Level         core.Level                // Minimum level written to this sink
+	Filter        func(*core.LogEntry) bool // Optional predicate; entries it rejects are skipped
+	BufferSize    int                       // Size of the sink's own buffered writer (0 writes synchronously)
+	FlushInterval time.Duration             // Interval to flush the sink's buffer (defaults to the logger's FlushInterval)
+}
+
+// sinkWriter is a Sink set up for writing
+type sinkWriter struct {
+	Sink
+	out    io.Writer        // Output, or the buffered writer in front of it
+	buffer *writer.Buffered // Buffered writer (nil when writing synchronously)
+	mu     sync.Mutex       // Serializes synchronous writes
+}
+
+// setupSinks creates the writers for LoggerConfig.Sinks
+func (l *Logger) setupSinks() {
+	for i, conf := range l.Config.Sinks {
+		if conf.Name == "" {
+			conf.Name = strconv.Itoa(i)
+		}
+		if conf.Output == nil {
+			l.handleError(newErrorf("sink %s has no output", conf.Name))
+			continue
+		}
+		if conf.Formatter == nil {
+			conf.Formatter = l.formatter
+		}
+
+		s := &sinkWriter{Sink: conf, out: conf.Output}
+		if conf.BufferSize > 0 {
+			interval := conf.FlushInterval
+			if interval <= 0 {
+				interval = l.Config.FlushInterval
+			}
+			name := conf.Name
+			s.buffer = writer.NewBuffered(conf.Output, conf.BufferSize, interval, func(err error) {
+				l.handleError(newErrorf("sink %s: %w", name, err))
+			}, l.Config.BatchSize, l.Config.BatchTimeout)
+			s.buffer.SetOverflow(l.overflowConfig())
+			s.out = s.buffer
+		}
+		l.sinks = append(l.sinks, s)
+	}
+}
+
+// writeSinks formats the entry for every sink that accepts it and writes it
+func (l *Logger) writeSinks(entry *core.LogEntry) {
+	buf := util.GetBuffer()
+	defer util.PutBuffer(buf)
+
+	for _, s := range l.sinks {
+		if err := l.writeSink(s, entry, buf); err != nil {
+			l.handleError(newErrorf("sink %s: %w", s.Name, err))
+		}
+	}
+}
+
+// writeSink formats and writes the entry to one sink, turning a panic in its
+// filter, formatter or output into an error so the other sinks still get it
+func (l *Logger) writeSink(s *sinkWriter, entry *core.LogEntry, buf *bytes.Buffer) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newErrorf("panic: %v", r)
+		}
+	}()
+
+	if entry.Level < s.Level || (s.Filter != nil && !s.Filter(entry)) {
+		return nil
+	}
+	buf.Reset()
+	if err := s.Formatter.Format(buf, entry); err != nil {
+		return err
+	}
+	return s.write(entry.Level, buf.Bytes(), l.Config.NoLocking)
+}
+
+// write writes a formatted entry to the sink
+func (s *sinkWriter) write(level core.Level, p []byte, noLocking bool) error {
+	if s.buffer != nil {
+		_, err := s.buffer.WriteLevel(level, p)
+		return err
+	}
+	if !noLocking {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+	_, err := s.out.Write(p)
+	return err
+}
+
+// syncSinks flushes the sink buffers and syncs the sink outputs
+func (l *Logger) syncSinks(ctx context.Context) error {
+	for _, s := range l.sinks {
+		var err error
+		if s.buffer != nil {
+			err = s.buffer.Sync(ctx)
+		} else {
+			err = writer.SyncWriter(s.Output)
+		}
+		if err != nil {
+			return newErrorf("sync sink %s: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+// closeSinks flushes and closes the sink outputs
+func (l *Logger) closeSinks() {
+	for _, s := range l.sinks {
+		var err error
+		if s.buffer != nil {
+			err = s.buffer.Close() // Also closes the output
+		} else if s.Output != os.Stdout && s.Output != os.Stderr {
+			if closer, ok := s.Output.(io.Closer); ok {
+				err = closer.Close()
+			}
+		}
+		if err != nil {
+			l.handleError(newErrorf("error closing sink %s: %v", s.Name, err))
+		}
+	}
+}
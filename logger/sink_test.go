@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/formatter"
+)
+
+// closeRecorder records whether it was closed
+type closeRecorder struct {
+	lockedBuffer
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+// TestLoggerSinks tests writing entries to the output and to sinks with their own formatter and level
+func TestLoggerSinks(t *testing.T) {
+	var text, errs bytes.Buffer
+	jsonSink := &lockedBuffer{}
+	l := New(LoggerConfig{
+		Level:     core.DEBUG,
+		Output:    &text,
+		Formatter: &formatter.TextFormatter{},
+		Sinks: []Sink{
+			{Name: "json", Output: jsonSink, Formatter: formatter.NewJSON(), BufferSize: 10, FlushInterval: time.Hour},
+			{Name: "errors", Output: &errs, Formatter: formatter.NewJSON(), Level: core.ERROR},
+		},
+	})
+
+	l.Debug("starting")
+	l.Error("failed")
+	if err := l.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	if !strings.Contains(text.String(), "starting") || !strings.Contains(text.String(), "failed") {
+		t.Errorf("Expected both entries on the output, got %q", text.String())
+	}
+	if lines := strings.Count(jsonSink.String(), "\n"); lines != 2 || !strings.HasPrefix(jsonSink.String(), "{") {
+		t.Errorf("Expected two JSON entries on the buffered sink, got %q", jsonSink.String())
+	}
+	if strings.Contains(errs.String(), "starting") || !strings.Contains(errs.String(), `"failed"`) {
+		t.Errorf("Expected only the ERROR entry on the error sink, got %q", errs.String())
+	}
+	l.Close()
+}
+
+// TestLoggerSinksOnly tests a logger without Output that writes to its sinks only
+func TestLoggerSinksOnly(t *testing.T) {
+	out := &closeRecorder{}
+	l := New(LoggerConfig{
+		Level:     core.INFO,
+		Formatter: &formatter.TextFormatter{},
+		Sinks: []Sink{{
+			Output: out,
+			Filter: func(e *core.LogEntry) bool { return !bytes.HasPrefix(e.Message, []byte("health")) },
+		}},
+	})
+
+	l.Info("health check")
+	l.Info("request served")
+	l.Close()
+
+	if strings.Contains(out.String(), "health") || !strings.Contains(out.String(), "request served") {
+		t.Errorf("Expected the filter to skip health entries, got %q", out.String())
+	}
+	if !out.closed {
+		t.Error("Expected Close to close the sink output")
+	}
+}
+
+// TestLoggerSinkFailureIsolation tests that a failing sink does not stop the others
+func TestLoggerSinkFailureIsolation(t *testing.T) {
+	var good bytes.Buffer
+	var reported []error
+	l := New(LoggerConfig{
+		Level:        core.INFO,
+		Formatter:    &formatter.TextFormatter{},
+		ErrorHandler: func(err error) { reported = append(reported, err) },
+		Sinks: []Sink{
+			{Name: "broken", Output: &failingWriter{}},
+			{Name: "good", Output: &good},
+		},
+	})
+	defer l.Close()
+
+	l.Info("delivered")
+	if !strings.Contains(good.String(), "delivered") {
+		t.Errorf("Expected the healthy sink to get the entry, got %q", good.String())
+	}
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "sink broken") {
+		t.Errorf("Expected one error naming the broken sink, got %v", reported)
+	}
+}
+
+// panickingFormatter panics on every entry
+type panickingFormatter struct{}
+
+func (panickingFormatter) Format(*bytes.Buffer, *core.LogEntry) error {
+	panic("formatter bug")
+}
+
+// TestLoggerSinkPanicIsolation tests that a panicking sink formatter is
+// reported and does not stop the other sinks
+func TestLoggerSinkPanicIsolation(t *testing.T) {
+	var good bytes.Buffer
+	var reported []error
+	l := New(LoggerConfig{
+		Level:        core.INFO,
+		Formatter:    &formatter.TextFormatter{},
+		ErrorHandler: func(err error) { reported = append(reported, err) },
+		Sinks: []Sink{
+			{Name: "panicking", Output: &bytes.Buffer{}, Formatter: panickingFormatter{}},
+			{Name: "good", Output: &good},
+		},
+	})
+	defer l.Close()
+
+	l.Info("delivered")
+	if !strings.Contains(good.String(), "delivered") {
+		t.Errorf("Expected the healthy sink to get the entry, got %q", good.String())
+	}
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "sink panicking: panic: formatter bug") {
+		t.Errorf("Expected one error reporting the panic, got %v", reported)
+	}
+}
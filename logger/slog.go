@@ -0,0 +1,183 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/util"
+)
+
+// slog levels for mire levels that have no standard slog equivalent.
+// Records at SlogLevelFatal and above trigger the logger's fatal and panic handling.
+const (
+	SlogLevelTrace  = slog.Level(-8)
+	SlogLevelNotice = slog.Level(2)
+	SlogLevelFatal  = slog.Level(12)
+	SlogLevelPanic  = slog.Level(16)
+)
+
+// SlogHandler is a slog.Handler that routes records through a Logger,
+// so its formatter, hooks, sampling and async mode all apply.
+// Attributes become typed fields in record order; groups are flattened into
+// dotted keys. The time and the caller of the record are those of the entry.
+type SlogHandler struct {
+	logger *Logger
+	attrs  []Field // Fields added with WithAttrs (read-only once created)
+	prefix string  // Group prefix for new attribute keys, e.g. "request."
+}
+
+// slogRecordKey is the context key carrying the time and program counter of a
+// slog record through the async workers to the entry
+type slogRecordKey struct{}
+
+// slogRecord holds the parts of a slog record that are not fields
+type slogRecord struct {
+	time time.Time
+	pc   uintptr
+}
+
+// NewSlogHandler creates a slog.Handler backed by the given logger
+func NewSlogHandler(l *Logger) *SlogHandler {
+	return &SlogHandler{logger: l}
+}
+
+// NewSlogLogger creates a slog.Logger backed by the given logger
+func NewSlogLogger(l *Logger) *slog.Logger {
+	return slog.New(NewSlogHandler(l))
+}
+
+// SlogLevel converts a slog level to the nearest mire level at or below it
+func SlogLevel(level slog.Level) core.Level {
+	switch {
+	case level < slog.LevelDebug:
+		return core.TRACE
+	case level < slog.LevelInfo:
+		return core.DEBUG
+	case level < SlogLevelNotice:
+		return core.INFO
+	case level < slog.LevelWarn:
+		return core.NOTICE
+	case level < slog.LevelError:
+		return core.WARN
+	case level < SlogLevelFatal:
+		return core.ERROR
+	case level < SlogLevelPanic:
+		return core.FATAL
+	default:
+		return core.PANIC
+	}
+}
+
+// Enabled reports whether the logger would write a record at the given level
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return !h.logger.closed.Load() && SlogLevel(level) >= h.logger.minLevel()
+}
+
+// Handle converts the record into a log entry and writes it through the logger
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := make([]Field, len(h.attrs), len(h.attrs)+r.NumAttrs())
+	copy(fields, h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendSlogAttr(fields, h.prefix, a)
+		return true
+	})
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = context.WithValue(ctx, slogRecordKey{}, slogRecord{time: r.Time, pc: r.PC})
+	h.logger.logTyped(ctx, SlogLevel(r.Level), core.StringToBytes(r.Message), nil, fields)
+	return nil
+}
+
+// WithAttrs returns a handler that adds the given attributes to every record
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	clone := *h
+	clone.attrs = append(make([]Field, 0, len(h.attrs)+len(attrs)), h.attrs...)
+	for _, a := range attrs {
+		clone.attrs = appendSlogAttr(clone.attrs, h.prefix, a)
+	}
+	return &clone
+}
+
+// WithGroup returns a handler that nests subsequent attributes under name
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// appendSlogAttr appends an attribute to fields, flattening groups into dotted keys
+func appendSlogAttr(fields []Field, prefix string, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() == slog.KindGroup {
+		group := a.Value.Group()
+		if len(group) == 0 {
+			return fields
+		}
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range group {
+			fields = appendSlogAttr(fields, prefix, ga)
+		}
+		return fields
+	}
+
+	if a.Key == "" {
+		return fields
+	}
+	return append(fields, slogField(prefix+a.Key, a.Value))
+}
+
+// slogField converts a resolved slog value into a typed field
+func slogField(key string, v slog.Value) Field {
+	switch v.Kind() {
+	case slog.KindString:
+		return String(key, v.String())
+	case slog.KindInt64:
+		return Int64(key, v.Int64())
+	case slog.KindUint64:
+		return Uint64(key, v.Uint64())
+	case slog.KindFloat64:
+		return Float64(key, v.Float64())
+	case slog.KindBool:
+		return Bool(key, v.Bool())
+	case slog.KindDuration:
+		return Duration(key, v.Duration())
+	case slog.KindTime:
+		return Time(key, v.Time())
+	default:
+		return Any(key, v.Any())
+	}
+}
+
+// applySlogRecord sets the timestamp and caller of the entry when ctx comes
+// from a slog record
+func (l *Logger) applySlogRecord(ctx context.Context, entry *core.LogEntry) {
+	if ctx == nil {
+		return
+	}
+	rec, ok := ctx.Value(slogRecordKey{}).(slogRecord)
+	if !ok {
+		return
+	}
+	if !rec.time.IsZero() {
+		entry.Timestamp = rec.time
+	}
+	if l.Config.ShowCaller && rec.pc != 0 {
+		if entry.Caller != nil {
+			core.PutCallerToPool(entry.Caller)
+		}
+		entry.Caller = util.GetCallerInfoForPC(rec.pc)
+	}
+}
@@ -0,0 +1,275 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/formatter"
+	"github.com/Lunar-Chipter/mire/hook"
+)
+
+// captureHook records the fields and typed fields of every entry it receives
+type captureHook struct {
+	mu         sync.Mutex
+	levels     []core.Level
+	entries    []map[string]string
+	keys       [][]string // Typed field keys in order
+	timestamps []time.Time
+	callers    []*core.Caller
+}
+
+func (h *captureHook) Fire(entry *core.LogEntry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fields := make(map[string]string, len(entry.Fields)+len(entry.TypedFields))
+	for k, v := range entry.Fields {
+		fields[k] = string(v)
+	}
+	var keys []string
+	for _, f := range entry.TypedFields {
+		fields[f.Key] = string(f.AppendText(nil))
+		keys = append(keys, f.Key)
+	}
+	var caller *core.Caller
+	if entry.Caller != nil {
+		c := *entry.Caller
+		caller = &c
+	}
+	h.levels = append(h.levels, entry.Level)
+	h.entries = append(h.entries, fields)
+	h.keys = append(h.keys, keys)
+	h.timestamps = append(h.timestamps, entry.Timestamp)
+	h.callers = append(h.callers, caller)
+	return nil
+}
+
+func (h *captureHook) Close() error { return nil }
+
+// newSlogTestLogger creates a logger writing text output with a capturing hook
+func newSlogTestLogger(level core.Level) (*Logger, *bytes.Buffer, *captureHook) {
+	var buf bytes.Buffer
+	capture := &captureHook{}
+	l := New(LoggerConfig{
+		Level:     level,
+		Output:    &buf,
+		Formatter: &formatter.TextFormatter{},
+		Hooks:     []hook.Hook{capture},
+	})
+	return l, &buf, capture
+}
+
+// TestSlogLevel tests the mapping of slog levels onto mire levels
+func TestSlogLevel(t *testing.T) {
+	tests := []struct {
+		level slog.Level
+		want  core.Level
+	}{
+		{SlogLevelTrace, core.TRACE},
+		{slog.LevelDebug, core.DEBUG},
+		{slog.LevelDebug + 1, core.DEBUG},
+		{slog.LevelInfo, core.INFO},
+		{SlogLevelNotice, core.NOTICE},
+		{slog.LevelWarn, core.WARN},
+		{slog.LevelError, core.ERROR},
+		{slog.LevelError + 2, core.ERROR},
+		{SlogLevelFatal, core.FATAL},
+		{SlogLevelPanic, core.PANIC},
+		{SlogLevelPanic + 10, core.PANIC},
+	}
+	for _, tt := range tests {
+		if got := SlogLevel(tt.level); got != tt.want {
+			t.Errorf("SlogLevel(%v) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+// TestSlogHandlerEnabled tests level filtering
+func TestSlogHandlerEnabled(t *testing.T) {
+	l, _, _ := newSlogTestLogger(core.NOTICE)
+	h := NewSlogHandler(l)
+	ctx := context.Background()
+
+	if h.Enabled(ctx, slog.LevelInfo) {
+		t.Error("INFO should be disabled at NOTICE")
+	}
+	if !h.Enabled(ctx, SlogLevelNotice) {
+		t.Error("NOTICE should be enabled at NOTICE")
+	}
+
+	l.Close()
+	if h.Enabled(ctx, slog.LevelError) {
+		t.Error("A closed logger should not be enabled")
+	}
+}
+
+// TestSlogHandlerAttrsAndGroups tests attribute conversion and group flattening
+func TestSlogHandlerAttrsAndGroups(t *testing.T) {
+	l, buf, capture := newSlogTestLogger(core.TRACE)
+	defer l.Close()
+
+	sl := NewSlogLogger(l).With("service", "billing").WithGroup("req")
+	sl.Log(context.Background(), SlogLevelNotice, "handled",
+		slog.Int("status", 200),
+		slog.Group("user", slog.String("id", "u1"), slog.Bool("admin", true)),
+		slog.Group("empty"),
+		slog.Duration("took", 1500*time.Millisecond),
+		slog.Float64("ratio", 0.5),
+		slog.Uint64("bytes", 42),
+		slog.Any("err", errors.New("boom")),
+		slog.Attr{}, // Ignored per slog.Handler rules
+	)
+
+	if !strings.Contains(buf.String(), "handled") {
+		t.Errorf("Expected message in output, got %q", buf.String())
+	}
+
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+	if len(capture.entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(capture.entries))
+	}
+	if capture.levels[0] != core.NOTICE {
+		t.Errorf("Expected NOTICE level, got %v", capture.levels[0])
+	}
+
+	want := map[string]string{
+		"service":        "billing",
+		"req.status":     "200",
+		"req.user.id":    "u1",
+		"req.user.admin": "true",
+		"req.took":       "1.5s",
+		"req.ratio":      "0.5",
+		"req.bytes":      "42",
+		"req.err":        "boom",
+	}
+	got := capture.entries[0]
+	if len(got) != len(want) {
+		t.Errorf("Expected fields %v, got %v", want, got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("Field %q: expected %q, got %q", k, v, got[k])
+		}
+	}
+}
+
+// TestSlogHandlerRecord tests that entries keep the attribute order, time and
+// caller of the record
+func TestSlogHandlerRecord(t *testing.T) {
+	var buf bytes.Buffer
+	capture := &captureHook{}
+	l := New(LoggerConfig{
+		Level:      core.INFO,
+		Output:     &buf,
+		Formatter:  &formatter.TextFormatter{},
+		Hooks:      []hook.Hook{capture},
+		ShowCaller: true,
+	})
+	defer l.Close()
+
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	var pcs [1]uintptr
+	runtime.Callers(1, pcs[:])
+	_, _, line, _ := runtime.Caller(0)
+	r := slog.NewRecord(ts, slog.LevelInfo, "ordered", pcs[0])
+	r.AddAttrs(slog.String("z", "1"), slog.Int("a", 2), slog.Time("m", ts), slog.Bool("b", true))
+	h := NewSlogHandler(l).WithAttrs([]slog.Attr{slog.String("svc", "api")})
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+	if got := strings.Join(capture.keys[0], ","); got != "svc,z,a,m,b" {
+		t.Errorf("Expected fields in record order, got %s", got)
+	}
+	if got := capture.entries[0]["m"]; got != "2024-05-06T07:08:09Z" {
+		t.Errorf("Expected time attribute, got %q", got)
+	}
+	if !capture.timestamps[0].Equal(ts) {
+		t.Errorf("Expected record time %v, got %v", ts, capture.timestamps[0])
+	}
+	if c := capture.callers[0]; c == nil || c.File != "slog_test.go" || c.Line != line-1 || c.Function != "TestSlogHandlerRecord" {
+		t.Errorf("Expected caller slog_test.go:%d, got %+v", line-1, c)
+	}
+}
+
+// tokenValuer is a slog.LogValuer that hides its secret
+type tokenValuer struct{ secret string }
+
+func (tokenValuer) LogValue() slog.Value { return slog.StringValue("[REDACTED]") }
+
+// TestSlogHandlerLogValuer tests that LogValuer values are resolved
+func TestSlogHandlerLogValuer(t *testing.T) {
+	l, _, capture := newSlogTestLogger(core.INFO)
+	defer l.Close()
+
+	NewSlogLogger(l).Info("login", "token", tokenValuer{secret: "s3cr3t"})
+
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+	if got := capture.entries[0]["token"]; got != "[REDACTED]" {
+		t.Errorf("Expected resolved LogValuer, got %q", got)
+	}
+}
+
+// TestSlogHandlerWithAttrsIsolation tests that derived handlers do not share attributes
+func TestSlogHandlerWithAttrsIsolation(t *testing.T) {
+	l, _, capture := newSlogTestLogger(core.INFO)
+	defer l.Close()
+
+	base := NewSlogLogger(l).With("a", 1)
+	base.With("b", 2).Info("child")
+	base.Info("parent")
+
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+	if _, ok := capture.entries[1]["b"]; ok {
+		t.Errorf("Parent handler should not see child attributes, got %v", capture.entries[1])
+	}
+	if capture.entries[0]["a"] != "1" || capture.entries[0]["b"] != "2" {
+		t.Errorf("Child handler should carry both attributes, got %v", capture.entries[0])
+	}
+}
+
+// TestSlogHandlerFiltered tests that filtered records are not written
+func TestSlogHandlerFiltered(t *testing.T) {
+	l, buf, _ := newSlogTestLogger(core.WARN)
+	defer l.Close()
+
+	sl := NewSlogLogger(l)
+	sl.Info("dropped")
+	sl.Warn("kept")
+
+	if strings.Contains(buf.String(), "dropped") || !strings.Contains(buf.String(), "kept") {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
+
+// TestSlogHandlerAsync tests that records pass through async mode
+func TestSlogHandlerAsync(t *testing.T) {
+	var buf syncBuffer
+	l := New(LoggerConfig{
+		Level:       core.INFO,
+		Output:      &buf,
+		Formatter:   &formatter.TextFormatter{},
+		AsyncMode:   true,
+		WorkerCount: 1,
+		ChannelSize: 16,
+	})
+
+	NewSlogLogger(l).Info("async record", "k", "v")
+	l.Close()
+
+	if !strings.Contains(buf.String(), "async record") || !strings.Contains(buf.String(), "k=v") {
+		t.Errorf("Expected async record in output, got %q", buf.String())
+	}
+}
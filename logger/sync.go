@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/writer"
+)
+
+// Sync flushes every entry logged so far: it drains the async queue, writes out
+// the buffered writer and syncs log files to stable storage. It returns an error
+// if ctx is done first or if a file cannot be synced.
+func (l *Logger) Sync(ctx context.Context) error {
+	if l.closed.Load() {
+		return nil
+	}
+
+	if l.asyncLogger != nil {
+		if err := l.asyncLogger.Sync(ctx); err != nil {
+			return newErrorf("sync async queue: %w", err)
+		}
+	}
+
+	if l.buffer != nil {
+		if err := l.buffer.Sync(ctx); err != nil {
+			return newErrorf("sync buffered writer: %w", err)
+		}
+	} else if err := writer.SyncWriter(l.out); err != nil {
+		return newErrorf("sync output: %w", err)
+	}
+
+	if err := l.syncSinks(ctx); err != nil {
+		return err
+	}
+
+	if l.errorFileHook != nil {
+		if err := l.errorFileHook.Sync(); err != nil {
+			return newErrorf("sync error file hook: %w", err)
+		}
+	}
+	return nil
+}
+
+// syncBeforeExit flushes logs within SyncTimeout so that the final FATAL or
+// PANIC entry is not lost when the process exits
+func (l *Logger) syncBeforeExit() {
+	ctx, cancel := context.WithTimeout(context.Background(), l.Config.SyncTimeout)
+	defer cancel()
+	if err := l.Sync(ctx); err != nil {
+		l.handleError(err)
+	}
+}
+
+// queueAsync reports whether an entry goes through the async queue. FATAL and
+// PANIC entries are written by the caller after the queue has been drained, so
+// they keep their order and are written before the process exits.
+func (l *Logger) queueAsync(level core.Level) bool {
+	if l.asyncLogger == nil {
+		return false
+	}
+	if level < core.FATAL {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), l.Config.SyncTimeout)
+	defer cancel()
+	if err := l.asyncLogger.Sync(ctx); err != nil {
+		l.handleError(newErrorf("sync async queue: %w", err))
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/formatter"
+	"github.com/Lunar-Chipter/mire/util"
+)
+
+// lockedBuffer is a bytes.Buffer safe for the buffered writer goroutine
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// newSyncTestLogger creates an async logger writing through a buffered writer that never flushes on its own
+func newSyncTestLogger(out *lockedBuffer, exit func(int)) *Logger {
+	return New(LoggerConfig{
+		Level:         core.INFO,
+		Output:        out,
+		Formatter:     &formatter.TextFormatter{},
+		BufferSize:    100,
+		FlushInterval: time.Hour,
+		BatchSize:     1000,
+		AsyncMode:     true,
+		WorkerCount:   1,
+		ChannelSize:   100,
+		NoTimeout:     true,
+		ExitFunc:      exit,
+	})
+}
+
+// TestLoggerSync tests that Sync drains the async queue and the buffered writer
+func TestLoggerSync(t *testing.T) {
+	out := &lockedBuffer{}
+	l := newSyncTestLogger(out, nil)
+	defer l.Close()
+
+	for i := 0; i < 10; i++ {
+		l.Info("queued")
+	}
+	if err := l.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync returned error: %v", err)
+	}
+	if n := strings.Count(out.String(), "queued"); n != 10 {
+		t.Errorf("Expected 10 lines after Sync, got %d:\n%s", n, out.String())
+	}
+}
+
+// blockingWriter blocks every write until release is closed
+type blockingWriter struct {
+	release chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	return len(p), nil
+}
+
+// TestLoggerSyncDeadline tests that Sync reports a timeout
+func TestLoggerSyncDeadline(t *testing.T) {
+	out := &blockingWriter{release: make(chan struct{})}
+	l := New(LoggerConfig{
+		Level:       core.INFO,
+		Output:      out,
+		Formatter:   &formatter.TextFormatter{},
+		AsyncMode:   true,
+		WorkerCount: 1,
+		ChannelSize: 10,
+		NoTimeout:   true,
+	})
+
+	l.Info("stuck")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Sync(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	close(out.release)
+	l.Close()
+	if err := l.Sync(context.Background()); err != nil {
+		t.Errorf("Sync after Close returned error: %v", err)
+	}
+}
+
+// TestLoggerFatalSyncs tests that the fatal entry and everything before it is written before exiting
+func TestLoggerFatalSyncs(t *testing.T) {
+	out := &lockedBuffer{}
+	var written string
+	l := newSyncTestLogger(out, func(int) { written = out.String() })
+	defer l.Close()
+
+	l.Info("before")
+	l.Fatal("crashed")
+
+	if !strings.Contains(written, "before") || !strings.Contains(written, "crashed") {
+		t.Fatalf("Expected both lines before exit, got %q", written)
+	}
+	if strings.Index(written, "before") > strings.Index(written, "crashed") {
+		t.Errorf("Expected the fatal line last, got %q", written)
+	}
+}
+
+// TestLoggerAsyncChildLogger tests that queued entries of a child logger keep
+// its fields, tags and error, the trace context and run the hooks
+func TestLoggerAsyncChildLogger(t *testing.T) {
+	out := &lockedBuffer{}
+	jf := formatter.NewJSON()
+	jf.ShowTrace = true
+	l := New(LoggerConfig{
+		Level:       core.INFO,
+		Output:      out,
+		Formatter:   jf,
+		AsyncMode:   true,
+		WorkerCount: 1,
+		ChannelSize: 100,
+		NoTimeout:   true,
+	})
+	defer l.Close()
+	var fired atomic.Int32
+	l.AddHook(&fieldHook{fire: func(*core.LogEntry) { fired.Add(1) }})
+
+	child := l.WithFields(map[string]interface{}{"svc": "billing"}).WithTags("payments").WithError(errors.New("declined"))
+	sc, _ := util.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := util.WithSpanContext(context.Background(), sc)
+	child.InfoC(ctx, "map")
+	child.Log(ctx, core.INFO, []byte("zero"), []byte("k"), []byte("v"))
+	child.LogT(ctx, core.INFO, "typed", String("k", "v"))
+	if err := l.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %q", out.String())
+	}
+	for _, line := range lines {
+		for _, want := range []string{`"svc":"billing"`, `"trace_id":"` + sc.TraceID + `"`, `"payments"`, `"declined"`} {
+			if !strings.Contains(line, want) {
+				t.Errorf("Expected %s in queued entry, got %s", want, line)
+			}
+		}
+	}
+	if n := fired.Load(); n != 3 {
+		t.Errorf("Expected hooks to run for every queued entry, ran %d times", n)
+	}
+}
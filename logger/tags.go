@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"github.com/Lunar-Chipter/mire/core"
+)
+
+// WithTags creates a new logger that adds the given tags to every entry.
+// Tags already present on the logger are not repeated.
+func (l *Logger) WithTags(tags ...string) *Logger {
+	if len(tags) == 0 {
+		return l
+	}
+	newLogger := l.clone()
+	merged := make([][]byte, len(l.tags), len(l.tags)+len(tags))
+	copy(merged, l.tags)
+	for _, tag := range tags {
+		if tag == "" || hasTag(merged, tag) {
+			continue
+		}
+		merged = append(merged, []byte(tag))
+	}
+	newLogger.tags = merged
+	return newLogger
+}
+
+// WithMetric creates a new logger that adds a named numeric metric to every entry.
+// Setting a metric the logger already carries replaces its value.
+func (l *Logger) WithMetric(name string, value float64) *Logger {
+	newLogger := l.clone()
+	metrics := make(map[string]float64, len(l.customMetrics)+1)
+	for k, v := range l.customMetrics {
+		metrics[k] = v
+	}
+	metrics[name] = value
+	newLogger.customMetrics = metrics
+	return newLogger
+}
+
+// applyTags copies the logger's tags and metrics into the entry. The entry owns
+// its slice and map so that pooling never touches the logger's copies.
+func (l *Logger) applyTags(entry *core.LogEntry) {
+	if len(l.tags) > 0 {
+		entry.Tags = append(entry.Tags, l.tags...)
+	}
+	if len(l.customMetrics) > 0 {
+		if entry.CustomMetrics == nil {
+			entry.CustomMetrics = make(map[string]float64, len(l.customMetrics))
+		}
+		for k, v := range l.customMetrics {
+			entry.CustomMetrics[k] = v
+		}
+	}
+}
+
+// hasTag reports whether tags contains tag
+func hasTag(tags [][]byte, tag string) bool {
+	for _, t := range tags {
+		if string(t) == tag {
+			return true
+		}
+	}
+	return false
+}
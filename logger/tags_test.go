@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/formatter"
+)
+
+// decodeTagsLine returns the tags and custom metrics of a JSON log line
+func decodeTagsLine(t *testing.T, buf *bytes.Buffer) ([]string, map[string]float64) {
+	t.Helper()
+	var decoded struct {
+		Tags          []string           `json:"tags"`
+		CustomMetrics map[string]float64 `json:"custom_metrics"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	return decoded.Tags, decoded.CustomMetrics
+}
+
+// TestLoggerWithTags tests that tags and metrics are added to entries and inherited by clones
+func TestLoggerWithTags(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(LoggerConfig{Level: core.INFO, Output: &buf, Formatter: formatter.NewJSON()})
+	defer l.Close()
+
+	if l.WithTags() != l {
+		t.Error("WithTags() without tags should return the same logger")
+	}
+
+	billing := l.WithTags("billing").WithMetric("amount", 12.5)
+	child := billing.WithTags("migration", "billing").WithFields(map[string]interface{}{"step": 2})
+	child.Info("migrated")
+
+	tags, metrics := decodeTagsLine(t, &buf)
+	if len(tags) != 2 || tags[0] != "billing" || tags[1] != "migration" {
+		t.Errorf("Expected tags [billing migration], got %v", tags)
+	}
+	if metrics["amount"] != 12.5 {
+		t.Errorf("Expected amount metric, got %v", metrics)
+	}
+
+	buf.Reset()
+	billing.WithMetric("amount", 3).InfoC(context.Background(), "refund")
+	if _, metrics := decodeTagsLine(t, &buf); metrics["amount"] != 3 {
+		t.Errorf("Expected overridden amount metric, got %v", metrics)
+	}
+
+	buf.Reset()
+	billing.Info("sent")
+	if tags, metrics := decodeTagsLine(t, &buf); len(tags) != 1 || metrics["amount"] != 12.5 {
+		t.Errorf("Children should not modify the parent, got tags %v metrics %v", tags, metrics)
+	}
+
+	buf.Reset()
+	l.Info("plain")
+	if tags, metrics := decodeTagsLine(t, &buf); tags != nil || metrics != nil {
+		t.Errorf("Root logger should carry no tags or metrics, got %s", buf.String())
+	}
+}
+
+// TestLoggerWithTagsTyped tests tags on entries written through the typed field path
+func TestLoggerWithTagsTyped(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(LoggerConfig{Level: core.INFO, Output: &buf, Formatter: formatter.NewJSON()})
+	defer l.Close()
+
+	l.WithTags("billing").InfoT("charged", Int("cents", 1250))
+
+	if tags, _ := decodeTagsLine(t, &buf); len(tags) != 1 || tags[0] != "billing" {
+		t.Errorf("Expected billing tag, got %s", buf.String())
+	}
+}
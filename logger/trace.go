@@ -0,0 +1,27 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/Lunar-Chipter/mire/core"
+)
+
+// applySpanContext fills trace_id, span_id and trace_flags from the span context of ctx.
+// IDs set explicitly with util.WithTraceID and util.WithSpanID take precedence.
+func (l *Logger) applySpanContext(ctx context.Context, entry *core.LogEntry) {
+	if ctx == nil || l.spanProvider == nil {
+		return
+	}
+	sc, ok := l.spanProvider.SpanContext(ctx)
+	if !ok || !sc.IsValid() {
+		return
+	}
+
+	if entry.TraceID == nil {
+		entry.TraceID = core.StringToBytes(sc.TraceID)
+		entry.TraceFlags = sc.TraceFlagsHex()
+	}
+	if entry.SpanID == nil {
+		entry.SpanID = core.StringToBytes(sc.SpanID)
+	}
+}
@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/formatter"
+	"github.com/Lunar-Chipter/mire/util"
+)
+
+// newTraceTestLogger creates a JSON logger with trace output enabled
+func newTraceTestLogger(provider util.SpanContextProvider) (*Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	jf := formatter.NewJSON()
+	jf.ShowTrace = true
+	l := New(LoggerConfig{
+		Level:               core.INFO,
+		Output:              &buf,
+		Formatter:           jf,
+		SpanContextProvider: provider,
+	})
+	return l, &buf
+}
+
+// decodeTraceFields returns the trace members of a JSON log line
+func decodeTraceFields(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	t.Helper()
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	return decoded
+}
+
+// TestLoggerSpanContext tests that a propagated span lands on the entry
+func TestLoggerSpanContext(t *testing.T) {
+	l, buf := newTraceTestLogger(nil)
+	defer l.Close()
+
+	sc, _ := util.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	l.InfoC(util.WithSpanContext(context.Background(), sc), "traced")
+
+	decoded := decodeTraceFields(t, buf)
+	if decoded["trace_id"] != sc.TraceID || decoded["span_id"] != sc.SpanID || decoded["trace_flags"] != "01" {
+		t.Errorf("Expected trace fields from span context, got %s", buf.String())
+	}
+}
+
+// TestLoggerSpanContextExplicitIDs tests that explicit context IDs take precedence
+func TestLoggerSpanContextExplicitIDs(t *testing.T) {
+	l, buf := newTraceTestLogger(nil)
+	defer l.Close()
+
+	sc := util.SpanContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}
+	ctx := util.WithSpanContext(context.Background(), sc)
+	ctx = util.WithTraceID(ctx, "explicit-trace")
+	l.InfoC(ctx, "traced")
+
+	decoded := decodeTraceFields(t, buf)
+	if decoded["trace_id"] != "explicit-trace" || decoded["span_id"] != sc.SpanID {
+		t.Errorf("Expected explicit trace ID and propagated span ID, got %s", buf.String())
+	}
+	if _, ok := decoded["trace_flags"]; ok {
+		t.Errorf("Expected no trace flags with an explicit trace ID, got %s", buf.String())
+	}
+}
+
+// TestLoggerCustomSpanProvider tests plugging in a span context provider
+func TestLoggerCustomSpanProvider(t *testing.T) {
+	provider := util.SpanContextProviderFunc(func(ctx context.Context) (util.SpanContext, bool) {
+		return util.SpanContext{
+			TraceID:    "0af7651916cd43dd8448eb211c80319c",
+			SpanID:     "b7ad6b7169203331",
+			TraceFlags: 0x00,
+		}, true
+	})
+	l, buf := newTraceTestLogger(provider)
+	defer l.Close()
+
+	l.InfoC(context.Background(), "traced")
+
+	decoded := decodeTraceFields(t, buf)
+	if decoded["trace_id"] != "0af7651916cd43dd8448eb211c80319c" || decoded["trace_flags"] != "00" {
+		t.Errorf("Expected trace fields from custom provider, got %s", buf.String())
+	}
+}
+
+// TestLoggerTraceMiddleware tests logging with the context of a traced HTTP request
+func TestLoggerTraceMiddleware(t *testing.T) {
+	l, buf := newTraceTestLogger(nil)
+	defer l.Close()
+
+	handler := util.TraceContextMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l.InfoC(r.Context(), "handled")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	decoded := decodeTraceFields(t, buf)
+	if decoded["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected trace ID from request headers, got %s", buf.String())
+	}
+}
+
+// TestLoggerZeroSpanContext tests that the zero-allocation methods apply the
+// span context and the context values of ctx
+func TestLoggerZeroSpanContext(t *testing.T) {
+	l, buf := newTraceTestLogger(nil)
+	defer l.Close()
+
+	sc, _ := util.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := util.WithUserID(util.WithSpanContext(context.Background(), sc), "user-42")
+	for _, log := range []func(){
+		func() { l.LogZC(ctx, core.INFO, []byte("zero")) },
+		func() { l.Log(ctx, core.INFO, []byte("zero"), []byte("k"), []byte("v")) },
+	} {
+		buf.Reset()
+		log()
+		decoded := decodeTraceFields(t, buf)
+		if decoded["trace_id"] != sc.TraceID || decoded["span_id"] != sc.SpanID || decoded["trace_flags"] != "01" || decoded["user_id"] != "user-42" {
+			t.Errorf("Expected trace fields and user ID from ctx, got %s", buf.String())
+		}
+	}
+}
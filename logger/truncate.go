@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/Lunar-Chipter/mire/core"
+)
+
+// applyLimits truncates the message and the field values of an entry that
+// exceed MaxMessageSize and MaxFieldSize, counting each truncation in the
+// stats. Key-value pairs and typed fields belong to the caller, so they are
+// copied before a value is replaced. Typed fields other than strings and byte
+// slices are resolved by the formatter and not limited.
+func (l *Logger) applyLimits(entry *core.LogEntry) {
+	if max := l.Config.MaxMessageSize; max > 0 && len(entry.Message) > max {
+		entry.Message = l.truncate(entry.Message, max)
+	}
+
+	max := l.Config.MaxFieldSize
+	if max <= 0 {
+		return
+	}
+	for k, v := range entry.Fields {
+		if len(v) > max {
+			entry.Fields[k] = l.truncate(v, max)
+		}
+	}
+
+	copied := false
+	for i := 1; i < len(entry.KeyVals); i += 2 {
+		if len(entry.KeyVals[i]) <= max {
+			continue
+		}
+		if !copied {
+			entry.KeyVals = append([][]byte(nil), entry.KeyVals...)
+			copied = true
+		}
+		entry.KeyVals[i] = l.truncate(entry.KeyVals[i], max)
+	}
+
+	copied = false
+	for i := range entry.TypedFields {
+		field := &entry.TypedFields[i]
+		var value []byte
+		switch field.Type {
+		case core.StringField:
+			value = core.StringToBytes(field.Str)
+		case core.BytesField:
+			value, _ = field.Iface.([]byte)
+		}
+		if len(value) <= max {
+			continue
+		}
+		if !copied {
+			entry.TypedFields = append([]core.Field(nil), entry.TypedFields...)
+			field = &entry.TypedFields[i]
+			copied = true
+		}
+		value = l.truncate(value, max)
+		if field.Type == core.StringField {
+			field.Str = core.BytesToString(value)
+		} else {
+			field.Iface = value
+		}
+	}
+}
+
+// truncate cuts value to max bytes and counts the truncation
+func (l *Logger) truncate(value []byte, max int) []byte {
+	l.stats.AddTruncation()
+	return truncateValue(value, max, l.Config.TruncationMarker)
+}
+
+// truncateValue cuts value at a rune boundary and appends the marker and the
+// original size, as in "abc...[truncated](5000 bytes)", so that the result
+// fits in max bytes. When the marker alone is longer than max, only the marker
+// and size are kept.
+func truncateValue(value []byte, max int, marker string) []byte {
+	if len(value) <= max {
+		return value
+	}
+
+	var size [20]byte
+	n := strconv.AppendInt(size[:0], int64(len(value)), 10)
+	tail := len(marker) + 1 + len(n) + len(" bytes)")
+
+	cut := max - tail
+	if cut < 0 {
+		cut = 0
+	}
+	// Do not split a multi-byte character
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+
+	out := make([]byte, 0, cut+tail)
+	out = append(out, value[:cut]...)
+	out = append(out, marker...)
+	out = append(out, '(')
+	out = append(out, n...)
+	out = append(out, " bytes)"...)
+	return out
+}
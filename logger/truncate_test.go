@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/formatter"
+)
+
+// TestTruncateValue tests cutting values at rune boundaries with the marker and original size
+func TestTruncateValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		max   int
+		want  string
+	}{
+		{"within limit", "hello", 5, "hello"},
+		{"ascii", strings.Repeat("a", 40), 30, "aaaaaa...[truncated](40 bytes)"},
+		{"multi-byte", "aéééééééééééééé", 28, "aé...[truncated](29 bytes)"},
+		{"marker longer than max", strings.Repeat("a", 40), 5, "...[truncated](40 bytes)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateValue([]byte(tt.value), tt.max, DEFAULT_TRUNCATION_MARKER)
+			if string(got) != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+			if !utf8.Valid(got) {
+				t.Errorf("Expected valid UTF-8, got %q", got)
+			}
+			if len(tt.want) <= tt.max && len(got) > tt.max {
+				t.Errorf("Expected at most %d bytes, got %d", tt.max, len(got))
+			}
+		})
+	}
+}
+
+// TestLoggerSizeLimits tests truncation on the map, key-value, typed and legacy paths
+func TestLoggerSizeLimits(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(LoggerConfig{
+		Level:            core.DEBUG,
+		Output:           &buf,
+		Formatter:        &formatter.TextFormatter{},
+		MaxMessageSize:   40,
+		MaxFieldSize:     30,
+		TruncationMarker: "~",
+	})
+	defer l.Close()
+
+	long := strings.Repeat("x", 100)
+	l.LogCF(context.Background(), core.INFO, []byte(long), map[string][]byte{"body": []byte(long), "id": []byte("7")})
+	out := buf.String()
+	if !strings.Contains(out, strings.Repeat("x", 28)+"~(100 bytes) ") {
+		t.Errorf("Expected a truncated message, got %q", out)
+	}
+	if !strings.Contains(out, "body="+strings.Repeat("x", 18)+"~(100 bytes)") || !strings.Contains(out, "id=7") {
+		t.Errorf("Expected a truncated field, got %q", out)
+	}
+
+	buf.Reset()
+	keyvals := [][]byte{[]byte("body"), []byte(long)}
+	l.LogZ(context.Background(), core.INFO, []byte("zero"), keyvals...)
+	if !strings.Contains(buf.String(), "body="+strings.Repeat("x", 18)+"~(100 bytes)") {
+		t.Errorf("Expected a truncated key-value pair, got %q", buf.String())
+	}
+	if len(keyvals[1]) != 100 {
+		t.Error("Expected the caller's key-value pairs to be left unchanged")
+	}
+
+	buf.Reset()
+	fields := []Field{String("body", long), Int("n", 1)}
+	l.InfoT("typed", fields...)
+	if !strings.Contains(buf.String(), "body="+strings.Repeat("x", 18)+"~(100 bytes)") {
+		t.Errorf("Expected a truncated typed field, got %q", buf.String())
+	}
+	if fields[0].Str != long {
+		t.Error("Expected the caller's typed fields to be left unchanged")
+	}
+
+	buf.Reset()
+	l.WithFields(map[string]interface{}{"body": long}).Info("legacy")
+	if !strings.Contains(buf.String(), "body="+strings.Repeat("x", 18)+"~(100 bytes)") {
+		t.Errorf("Expected a truncated logger field, got %q", buf.String())
+	}
+
+	if got := l.Stats().GetStats()["truncations"]; got != int64(5) {
+		t.Errorf("Expected 5 truncations, got %v", got)
+	}
+}
@@ -0,0 +1,327 @@
+package metric
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Defaults for HistogramConfig
+const (
+	DefaultRelativeAccuracy = 0.01 // Quantile estimates are within 1% of the true value
+	DefaultMaxBins          = 2048 // Enough to cover 10µs..10s at 1% accuracy many times over
+)
+
+// HistogramConfig configures how Metrics stores histogram observations.
+// Memory per series is bounded by the number of buckets and MaxBins,
+// independent of the number of observations.
+type HistogramConfig struct {
+	Buckets          []float64     // Upper bounds of the exported buckets (defaults to DefaultBuckets)
+	RelativeAccuracy float64       // Relative error of quantile estimates, between 0 and 1 (defaults to DefaultRelativeAccuracy)
+	MaxBins          int           // Maximum sketch bins per sign; the smallest magnitudes are merged beyond it (defaults to DefaultMaxBins)
+	Window           time.Duration // Quantiles, min and max cover the last one to two windows (0 keeps every observation)
+}
+
+// HistogramSnapshot summarizes the observations of a histogram.
+// Count, Sum, Min, Max and the quantiles cover the current window when
+// HistogramConfig.Window is set.
+type HistogramSnapshot struct {
+	Count int64
+	Sum   float64
+	Min   float64
+	Max   float64
+	P50   float64
+	P90   float64
+	P95   float64
+	P99   float64
+	P999  float64
+}
+
+// Avg returns the mean of the observations, or 0 when there are none
+func (s HistogramSnapshot) Avg() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / float64(s.Count)
+}
+
+// histogram is a fixed-memory histogram for one series.
+// Bucket counts, count and sum are cumulative and feed the exposition format;
+// the sketches are windowed and feed quantile queries.
+type histogram struct {
+	buckets []int64 // Non-cumulative counts per bucket in HistogramConfig.Buckets
+	count   int64   // Total observations since creation
+	sum     float64 // Total of observations since creation
+
+	cur         *sketch   // Observations of the current window
+	prev        *sketch   // Observations of the previous window, nil if none
+	windowStart time.Time // Start of the current window
+}
+
+// newHistogram creates an empty histogram for the given configuration
+func newHistogram(conf *HistogramConfig, now time.Time) *histogram {
+	return &histogram{
+		buckets:     make([]int64, len(conf.Buckets)),
+		cur:         newSketch(conf.RelativeAccuracy, conf.MaxBins),
+		windowStart: now,
+	}
+}
+
+// observe records a value. Must be called with the Metrics write lock held.
+func (h *histogram) observe(conf *HistogramConfig, value float64, now time.Time) {
+	if math.IsNaN(value) {
+		return
+	}
+	h.rotate(conf, now)
+
+	if i := sort.SearchFloat64s(conf.Buckets, value); i < len(h.buckets) {
+		h.buckets[i]++
+	}
+	h.count++
+	h.sum += value
+	h.cur.add(value)
+}
+
+// rotate starts a new window once the current one has elapsed
+func (h *histogram) rotate(conf *HistogramConfig, now time.Time) {
+	if conf.Window <= 0 {
+		return
+	}
+	elapsed := now.Sub(h.windowStart)
+	if elapsed < conf.Window {
+		return
+	}
+	if elapsed < 2*conf.Window {
+		h.prev = h.cur
+	} else {
+		h.prev = nil // Nothing recorded in the last full window
+	}
+	h.cur = newSketch(conf.RelativeAccuracy, conf.MaxBins)
+	h.windowStart = now.Add(-(elapsed % conf.Window))
+}
+
+// reset discards the windowed observations, keeping cumulative totals
+func (h *histogram) reset(conf *HistogramConfig, now time.Time) {
+	h.cur = newSketch(conf.RelativeAccuracy, conf.MaxBins)
+	h.prev = nil
+	h.windowStart = now
+}
+
+// mergeInto adds the windowed observations visible at now to dst
+func (h *histogram) mergeInto(dst *sketch, conf *HistogramConfig, now time.Time) {
+	if conf.Window > 0 {
+		elapsed := now.Sub(h.windowStart)
+		switch {
+		case elapsed >= 2*conf.Window:
+			return // Both windows have expired
+		case elapsed >= conf.Window:
+			dst.merge(h.cur) // The current window is now the previous one
+			return
+		}
+	}
+	if h.prev != nil {
+		dst.merge(h.prev)
+	}
+	dst.merge(h.cur)
+}
+
+// sketch is a logarithmic-bin quantile sketch with bounded relative error.
+// A positive value v falls in bin ceil(log(v)/log(gamma)); negative values
+// use the same scheme on their magnitude.
+type sketch struct {
+	gamma    float64
+	logGamma float64
+	maxBins  int
+
+	pos  map[int]int64
+	neg  map[int]int64
+	zero int64
+
+	count int64
+	sum   float64
+	min   float64
+	max   float64
+}
+
+// minIndexable is the smallest magnitude given its own bin; smaller values count as zero
+const minIndexable = 1e-300
+
+// newSketch creates an empty sketch
+func newSketch(accuracy float64, maxBins int) *sketch {
+	gamma := (1 + accuracy) / (1 - accuracy)
+	return &sketch{
+		gamma:    gamma,
+		logGamma: math.Log(gamma),
+		maxBins:  maxBins,
+		pos:      make(map[int]int64),
+		neg:      make(map[int]int64),
+	}
+}
+
+// add records a single value
+func (s *sketch) add(v float64) {
+	switch {
+	case v > minIndexable:
+		s.pos[s.index(v)]++
+		s.collapse(s.pos)
+	case v < -minIndexable:
+		s.neg[s.index(-v)]++
+		s.collapse(s.neg)
+	default:
+		s.zero++
+	}
+
+	if s.count == 0 || v < s.min {
+		s.min = v
+	}
+	if s.count == 0 || v > s.max {
+		s.max = v
+	}
+	s.count++
+	s.sum += v
+}
+
+// merge adds all observations of other to s
+func (s *sketch) merge(other *sketch) {
+	if other.count == 0 {
+		return
+	}
+	for i, c := range other.pos {
+		s.pos[i] += c
+	}
+	for i, c := range other.neg {
+		s.neg[i] += c
+	}
+	s.collapse(s.pos)
+	s.collapse(s.neg)
+	s.zero += other.zero
+
+	if s.count == 0 || other.min < s.min {
+		s.min = other.min
+	}
+	if s.count == 0 || other.max > s.max {
+		s.max = other.max
+	}
+	s.count += other.count
+	s.sum += other.sum
+}
+
+// index returns the bin of a positive magnitude
+func (s *sketch) index(v float64) int {
+	return int(math.Ceil(math.Log(v) / s.logGamma))
+}
+
+// value returns the representative magnitude of a bin
+func (s *sketch) value(index int) float64 {
+	return 2 * math.Pow(s.gamma, float64(index)) / (s.gamma + 1)
+}
+
+// collapse merges the smallest-magnitude bins until at most maxBins remain
+func (s *sketch) collapse(bins map[int]int64) {
+	for len(bins) > s.maxBins {
+		lowest, next := math.MaxInt, math.MaxInt
+		for i := range bins {
+			if i < lowest {
+				lowest, next = i, lowest
+			} else if i < next {
+				next = i
+			}
+		}
+		bins[next] += bins[lowest]
+		delete(bins, lowest)
+	}
+}
+
+// quantile returns the estimated value at quantile q in [0, 1].
+// The rank follows the nearest-rank method, and the extremes are exact.
+func (s *sketch) quantile(q float64) float64 {
+	if s.count == 0 {
+		return 0
+	}
+	rank := int64(math.Ceil(q*float64(s.count))) - 1
+	if rank <= 0 {
+		return s.min
+	}
+	if rank >= s.count-1 {
+		return s.max
+	}
+
+	result := s.max
+	var seen int64
+	found := false
+
+	// Negative values, most negative first
+	for _, i := range sortedBins(s.neg, true) {
+		seen += s.neg[i]
+		if seen > rank {
+			result, found = -s.value(i), true
+			break
+		}
+	}
+	if !found {
+		seen += s.zero
+		if seen > rank {
+			result, found = 0, true
+		}
+	}
+	if !found {
+		for _, i := range sortedBins(s.pos, false) {
+			seen += s.pos[i]
+			if seen > rank {
+				result = s.value(i)
+				break
+			}
+		}
+	}
+
+	// The representative value may lie slightly outside the observed range
+	return math.Max(s.min, math.Min(s.max, result))
+}
+
+// snapshot summarizes the sketch
+func (s *sketch) snapshot() HistogramSnapshot {
+	return HistogramSnapshot{
+		Count: s.count,
+		Sum:   s.sum,
+		Min:   s.min,
+		Max:   s.max,
+		P50:   s.quantile(0.5),
+		P90:   s.quantile(0.9),
+		P95:   s.quantile(0.95),
+		P99:   s.quantile(0.99),
+		P999:  s.quantile(0.999),
+	}
+}
+
+// sortedBins returns the bin indexes in ascending or descending order
+func sortedBins(bins map[int]int64, descending bool) []int {
+	indexes := make([]int, 0, len(bins))
+	for i := range bins {
+		indexes = append(indexes, i)
+	}
+	if descending {
+		sort.Sort(sort.Reverse(sort.IntSlice(indexes)))
+	} else {
+		sort.Ints(indexes)
+	}
+	return indexes
+}
+
+// withDefaults returns a copy of conf with zero values replaced by defaults
+func (conf HistogramConfig) withDefaults() HistogramConfig {
+	if len(conf.Buckets) == 0 {
+		conf.Buckets = DefaultBuckets
+	}
+	buckets := make([]float64, len(conf.Buckets))
+	copy(buckets, conf.Buckets)
+	sort.Float64s(buckets)
+	conf.Buckets = buckets
+
+	if conf.RelativeAccuracy <= 0 || conf.RelativeAccuracy >= 1 {
+		conf.RelativeAccuracy = DefaultRelativeAccuracy
+	}
+	if conf.MaxBins <= 0 {
+		conf.MaxBins = DefaultMaxBins
+	}
+	return conf
+}
@@ -0,0 +1,200 @@
+package metric
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// within reports whether got is within the relative error of want
+func within(got, want, relErr float64) bool {
+	return math.Abs(got-want) <= math.Abs(want)*relErr
+}
+
+// TestHistogramQuantiles tests quantile accuracy on a uniform distribution
+func TestHistogramQuantiles(t *testing.T) {
+	m := NewMetrics()
+	for i := 1; i <= 10000; i++ {
+		m.RecordHistogram("latency", float64(i), nil)
+	}
+
+	s := m.GetHistogramSnapshot("latency")
+	if s.Count != 10000 {
+		t.Errorf("Expected count 10000, got %d", s.Count)
+	}
+	if s.Min != 1 || s.Max != 10000 {
+		t.Errorf("Expected exact min 1 and max 10000, got %v and %v", s.Min, s.Max)
+	}
+	if s.Avg() != 5000.5 {
+		t.Errorf("Expected avg 5000.5, got %v", s.Avg())
+	}
+
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"p50", s.P50, 5000},
+		{"p90", s.P90, 9000},
+		{"p95", s.P95, 9500},
+		{"p99", s.P99, 9900},
+		{"p999", s.P999, 9990},
+	}
+	for _, tt := range tests {
+		if !within(tt.got, tt.want, DefaultRelativeAccuracy) {
+			t.Errorf("%s: expected %v within 1%%, got %v", tt.name, tt.want, tt.got)
+		}
+	}
+}
+
+// TestHistogramNegativeAndZero tests ordering across negative, zero and positive values
+func TestHistogramNegativeAndZero(t *testing.T) {
+	m := NewMetrics()
+	for _, v := range []float64{-100, -10, 0, 0, 10, 100} {
+		m.RecordHistogram("mixed", v, nil)
+	}
+
+	s := m.GetHistogramSnapshot("mixed")
+	if s.Min != -100 || s.Max != 100 {
+		t.Errorf("Unexpected min/max %v/%v", s.Min, s.Max)
+	}
+	if s.P50 != 0 {
+		t.Errorf("Expected p50 0, got %v", s.P50)
+	}
+	if q := m.GetHistogramSnapshot("mixed"); !within(q.P90, 100, DefaultRelativeAccuracy) {
+		t.Errorf("Expected p90 near 100, got %v", q.P90)
+	}
+}
+
+// TestHistogramBoundedMemory tests that bins stay bounded for a wide range of values
+func TestHistogramBoundedMemory(t *testing.T) {
+	m := NewMetricsWithConfig(HistogramConfig{MaxBins: 64})
+	for i := 0; i < 100000; i++ {
+		m.RecordHistogram("wide", math.Pow(10, float64(i%200)-100), nil)
+	}
+
+	h := m.histograms["wide"]
+	if len(h.cur.pos) > 64 {
+		t.Errorf("Expected at most 64 bins, got %d", len(h.cur.pos))
+	}
+	if h.count != 100000 {
+		t.Errorf("Expected 100000 observations, got %d", h.count)
+	}
+
+	// Collapsing merges the smallest magnitudes, so high quantiles stay accurate
+	s := m.GetHistogramSnapshot("wide")
+	if !within(s.P99, 1e97, 0.05) {
+		t.Errorf("Expected p99 near 1e97, got %v", s.P99)
+	}
+}
+
+// TestHistogramSeriesKeys tests that series with different tags are kept apart
+func TestHistogramSeriesKeys(t *testing.T) {
+	m := NewMetrics()
+	fast := map[string]string{"sink": "memory"}
+	slow := map[string]string{"sink": "disk"}
+	for i := 0; i < 100; i++ {
+		m.RecordHistogram("write", 1, fast)
+		m.RecordHistogram("write", 100, slow)
+	}
+
+	if s := m.GetSeriesSnapshot("write", fast); s.Count != 100 || s.Max != 1 {
+		t.Errorf("Unexpected memory series %+v", s)
+	}
+	if s := m.GetSeriesSnapshot("write", slow); s.Count != 100 || s.Min != 100 {
+		t.Errorf("Unexpected disk series %+v", s)
+	}
+	if s := m.GetSeriesSnapshot("write", map[string]string{"sink": "other"}); s.Count != 0 {
+		t.Errorf("Unknown series should be empty, got %+v", s)
+	}
+	if s := m.GetHistogramSnapshot("write"); s.Count != 200 || s.Min != 1 || s.Max != 100 {
+		t.Errorf("Unexpected merged snapshot %+v", s)
+	}
+}
+
+// TestHistogramWindow tests that old observations expire after the window
+func TestHistogramWindow(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	m := NewMetricsWithConfig(HistogramConfig{Window: time.Minute})
+	m.now = func() time.Time { return now }
+
+	m.RecordHistogram("latency", 500, nil)
+
+	// Second window: the previous window is still visible
+	now = now.Add(70 * time.Second)
+	m.RecordHistogram("latency", 1, nil)
+	if s := m.GetHistogramSnapshot("latency"); s.Count != 2 || s.Max != 500 {
+		t.Errorf("Expected both windows, got %+v", s)
+	}
+
+	// Third window: the first observation has expired
+	now = now.Add(time.Minute)
+	if s := m.GetHistogramSnapshot("latency"); s.Count != 1 || s.Max != 1 {
+		t.Errorf("Expected only the previous window, got %+v", s)
+	}
+	m.RecordHistogram("latency", 2, nil)
+	if s := m.GetHistogramSnapshot("latency"); s.Count != 2 || s.Max != 2 {
+		t.Errorf("Expected the last two windows, got %+v", s)
+	}
+
+	// Idle for longer than two windows: everything has expired
+	now = now.Add(5 * time.Minute)
+	if s := m.GetHistogramSnapshot("latency"); s.Count != 0 {
+		t.Errorf("Expected an empty window, got %+v", s)
+	}
+
+	// Exposition totals are cumulative and never reset
+	h := m.histograms["latency"]
+	if h.count != 3 || h.sum != 503 {
+		t.Errorf("Expected cumulative count 3 and sum 503, got %d and %v", h.count, h.sum)
+	}
+}
+
+// TestResetHistograms tests manual reset of windowed observations
+func TestResetHistograms(t *testing.T) {
+	m := NewMetrics()
+	m.RecordHistogram("latency", 1, nil)
+	m.RecordHistogram("latency", 2, nil)
+
+	m.ResetHistograms()
+	if s := m.GetHistogramSnapshot("latency"); s.Count != 0 {
+		t.Errorf("Expected empty snapshot after reset, got %+v", s)
+	}
+
+	m.RecordHistogram("latency", 3, nil)
+	if s := m.GetHistogramSnapshot("latency"); s.Count != 1 || s.Min != 3 {
+		t.Errorf("Expected only new observations, got %+v", s)
+	}
+	if h := m.histograms["latency"]; h.count != 3 {
+		t.Errorf("Cumulative count should survive reset, got %d", h.count)
+	}
+}
+
+// TestHistogramConfigDefaults tests default and invalid configuration values
+func TestHistogramConfigDefaults(t *testing.T) {
+	conf := HistogramConfig{Buckets: []float64{5, 1}, RelativeAccuracy: 2}.withDefaults()
+	if conf.Buckets[0] != 1 || conf.Buckets[1] != 5 {
+		t.Errorf("Buckets should be sorted, got %v", conf.Buckets)
+	}
+	if conf.RelativeAccuracy != DefaultRelativeAccuracy {
+		t.Errorf("Invalid accuracy should fall back to default, got %v", conf.RelativeAccuracy)
+	}
+	if conf.MaxBins != DefaultMaxBins {
+		t.Errorf("Expected default max bins, got %d", conf.MaxBins)
+	}
+
+	if got := (HistogramConfig{}).withDefaults().Buckets; len(got) != len(DefaultBuckets) {
+		t.Errorf("Expected default buckets, got %v", got)
+	}
+}
+
+// TestHistogramIgnoresNaN tests that NaN observations are dropped
+func TestHistogramIgnoresNaN(t *testing.T) {
+	m := NewMetrics()
+	m.RecordHistogram("latency", math.NaN(), nil)
+	m.RecordHistogram("latency", 1, nil)
+
+	if s := m.GetHistogramSnapshot("latency"); s.Count != 1 || s.Sum != 1 {
+		t.Errorf("NaN should be ignored, got %+v", s)
+	}
+}
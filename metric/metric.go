@@ -1,10 +1,9 @@
 package metric
 
 import (
-	"math"
-	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Lunar-Chipter/mire/core"
 )
@@ -21,20 +20,31 @@ type Collector interface {
 	RecordGauge(metric string, value float64, tags map[string]string)
 }
 
-// Metrics is a simple in-memory metrics collector
+// Metrics is a simple in-memory metrics collector.
+// Each metric is stored per series, identified by its name and tags.
+// Histograms use fixed memory per series regardless of the number of observations.
 type Metrics struct {
-	counters   map[string]int64
-	histograms map[string][]float64
-	gauges     map[string]float64
+	counters   map[string]int64      // Counter values keyed by series key
+	histograms map[string]*histogram // Histograms keyed by series key
+	gauges     map[string]float64    // Gauge values keyed by series key
+	conf       HistogramConfig       // Histogram buckets, accuracy and window
+	now        func() time.Time      // Time source, replaceable in tests
 	mu         sync.RWMutex
 }
 
-// NewMetrics creates a new Metrics collector
+// NewMetrics creates a new Metrics collector with the default histogram configuration
 func NewMetrics() *Metrics {
+	return NewMetricsWithConfig(HistogramConfig{})
+}
+
+// NewMetricsWithConfig creates a new Metrics collector with the given histogram configuration
+func NewMetricsWithConfig(conf HistogramConfig) *Metrics {
 	return &Metrics{
 		counters:   make(map[string]int64),
-		histograms: make(map[string][]float64),
+		histograms: make(map[string]*histogram),
 		gauges:     make(map[string]float64),
+		conf:       conf.withDefaults(),
+		now:        time.Now,
 	}
 }
 
@@ -43,7 +53,7 @@ func (m *Metrics) IncrementCounter(level core.Level, tags map[string]string) {
 	if level < core.TRACE || level > core.PANIC {
 		return
 	}
-	key := "log." + strings.ToLower(level.String())
+	key := seriesKey("log."+strings.ToLower(level.String()), tags)
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.counters[key]++
@@ -51,50 +61,153 @@ func (m *Metrics) IncrementCounter(level core.Level, tags map[string]string) {
 
 // RecordHistogram records a histogram metric
 func (m *Metrics) RecordHistogram(metric string, value float64, tags map[string]string) {
+	key := seriesKey(metric, tags)
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.histograms[metric] = append(m.histograms[metric], value)
+
+	now := m.now()
+	h, ok := m.histograms[key]
+	if !ok {
+		h = newHistogram(&m.conf, now)
+		m.histograms[key] = h
+	}
+	h.observe(&m.conf, value, now)
 }
 
 // RecordGauge records a gauge metric
 func (m *Metrics) RecordGauge(metric string, value float64, tags map[string]string) {
+	key := seriesKey(metric, tags)
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.gauges[metric] = value
+	m.gauges[key] = value
 }
 
-// GetCounter returns the value of a counter metric
+// GetCounter returns the value of a counter metric summed over all tag sets
 func (m *Metrics) GetCounter(metric string) int64 {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	return m.counters[metric]
+
+	var total int64
+	for key, v := range m.counters {
+		if seriesName(key) == metric {
+			total += v
+		}
+	}
+	return total
 }
 
-// GetHistogram returns statistics for a histogram metric
+// GetHistogram returns statistics for a histogram metric over all tag sets
 func (m *Metrics) GetHistogram(metric string) (min, max, avg, p95 float64) {
+	s := m.GetHistogramSnapshot(metric)
+	return s.Min, s.Max, s.Avg(), s.P95
+}
+
+// GetHistogramSnapshot returns count, sum, min, max and quantiles
+// for a histogram metric merged over all tag sets
+func (m *Metrics) GetHistogramSnapshot(metric string) HistogramSnapshot {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	values := m.histograms[metric]
-	if len(values) == 0 {
-		return 0, 0, 0, 0
+	now := m.now()
+	merged := newSketch(m.conf.RelativeAccuracy, m.conf.MaxBins)
+	for key, h := range m.histograms {
+		if seriesName(key) == metric {
+			h.mergeInto(merged, &m.conf, now)
+		}
 	}
+	return merged.snapshot()
+}
 
-	sort.Float64s(values)
-	min = values[0]
-	max = values[len(values)-1]
+// GetSeriesSnapshot returns count, sum, min, max and quantiles
+// for the histogram series with exactly the given tags
+func (m *Metrics) GetSeriesSnapshot(metric string, tags map[string]string) HistogramSnapshot {
+	key := seriesKey(metric, tags)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 
-	sum := 0.0
-	for _, v := range values {
-		sum += v
+	merged := newSketch(m.conf.RelativeAccuracy, m.conf.MaxBins)
+	if h, ok := m.histograms[key]; ok {
+		h.mergeInto(merged, &m.conf, m.now())
 	}
-	avg = sum / float64(len(values))
+	return merged.snapshot()
+}
+
+// ResetHistograms discards the windowed observations of all histograms.
+// Cumulative bucket counts, sums and counts used for exposition are kept.
+func (m *Metrics) ResetHistograms() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	p95Index := int(math.Ceil(0.95*float64(len(values)))) - 1
-	if p95Index < 0 {
-		p95Index = 0
+	now := m.now()
+	for _, h := range m.histograms {
+		h.reset(&m.conf, now)
 	}
-	p95 = values[p95Index]
+}
+
+// seriesKey builds the storage key for a metric name and its tags.
+// Tags are rendered sorted as Prometheus labels, e.g. name{app="x",env="y"}.
+func seriesKey(name string, tags map[string]string) string {
+	if len(tags) == 0 {
+		return name
+	}
+	var b strings.Builder
+	b.WriteString(name)
+	writeLabels(&b, tags)
+	return b.String()
+}
 
-	return min, max, avg, p95
+// seriesName returns the metric name part of a series key
+func seriesName(key string) string {
+	if i := strings.IndexByte(key, '{'); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
+
+// seriesLabels returns the rendered label part of a series key, without braces
+func seriesLabels(key string) string {
+	if i := strings.IndexByte(key, '{'); i >= 0 {
+		return key[i+1 : len(key)-1]
+	}
+	return ""
+}
+
+// Metric names reported by the logger pipeline
+const (
+	FormatDuration  = "log.format_duration_seconds" // Histogram of time spent formatting an entry
+	WriteDuration   = "log.write_duration_seconds"  // Histogram of time spent writing an entry
+	BytesWritten    = "log.bytes_written"           // Counter of bytes written to the output
+	HookFailures    = "log.hook_failures"           // Counter of hook errors
+	AsyncQueueDepth = "log.async_queue_depth"       // Gauge of jobs waiting in the async queue
+	AsyncDropped    = "log.async_dropped"           // Counter of entries dropped by the async queue
+	BufferDropped   = "log.buffer_dropped"          // Counter of entries dropped by the buffered writer
+)
+
+// CounterCollector is an optional interface for collectors that support named counters.
+// Collectors that do not implement it receive cumulative totals through RecordGauge instead.
+type CounterCollector interface {
+	// AddCounter adds delta to a named counter metric
+	AddCounter(metric string, delta int64, tags map[string]string)
+}
+
+// AddCounter adds delta to a named counter metric
+func (m *Metrics) AddCounter(metric string, delta int64, tags map[string]string) {
+	key := seriesKey(metric, tags)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[key] += delta
+}
+
+// GetGauge returns the last recorded value of a gauge metric summed over all tag sets
+func (m *Metrics) GetGauge(metric string) float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	total := 0.0
+	for key, v := range m.gauges {
+		if seriesName(key) == metric {
+			total += v
+		}
+	}
+	return total
 }
@@ -383,3 +383,21 @@ func TestCollectorWithVariousDataTypes(t *testing.T) {
 		t.Errorf("Expected max %f, got %f", expectedMax, max)
 	}
 }
+
+// TestAddCounterAndGetGauge tests named counters and gauge reads
+func TestAddCounterAndGetGauge(t *testing.T) {
+	metricsCollector := NewMetrics()
+
+	metricsCollector.AddCounter(BytesWritten, 10, nil)
+	metricsCollector.AddCounter(BytesWritten, 5, nil)
+	if got := metricsCollector.GetCounter(BytesWritten); got != 15 {
+		t.Errorf("Expected counter 15, got %d", got)
+	}
+
+	metricsCollector.RecordGauge(AsyncQueueDepth, 7, nil)
+	if got := metricsCollector.GetGauge(AsyncQueueDepth); got != 7 {
+		t.Errorf("Expected gauge 7, got %v", got)
+	}
+
+	var _ CounterCollector = metricsCollector
+}
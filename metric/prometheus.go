@@ -0,0 +1,390 @@
+package metric
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/util"
+	"github.com/Lunar-Chipter/mire/writer"
+)
+
+// DefaultNamespace is the prefix added to every exported metric name
+const DefaultNamespace = "mire"
+
+// ContentType is the Prometheus text exposition format content type
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are the histogram bucket upper bounds used for exposition.
+// They are tuned for latencies in seconds, from 10µs up to 10s.
+var DefaultBuckets = []float64{
+	0.00001, 0.000025, 0.00005, 0.0001, 0.00025, 0.0005,
+	0.001, 0.0025, 0.005, 0.01, 0.025, 0.05,
+	0.1, 0.25, 0.5, 1, 2.5, 5, 10,
+}
+
+// Metric types used in the exposition format
+const (
+	TypeCounter = "counter"
+	TypeGauge   = "gauge"
+)
+
+// Sample is a single exported value provided by a Source
+type Sample struct {
+	Name   string            // Metric name without namespace, e.g. "logger_bytes_written_total"
+	Help   string            // Description written as # HELP
+	Type   string            // TypeCounter or TypeGauge
+	Labels map[string]string // Optional labels
+	Value  float64           // Sample value
+}
+
+// Source provides samples at scrape time.
+// Samples sharing a name form one metric family, even across sources; the
+// family takes the first Help and Type given. Samples named like a family of
+// the Metrics collector are skipped, as a family can only be written once.
+type Source func() []Sample
+
+// Exporter renders a Metrics collector and additional sources
+// in the Prometheus text exposition format.
+type Exporter struct {
+	Namespace string // Prefix for metric names (defaults to DefaultNamespace)
+	metrics   *Metrics
+	sources   []Source
+	mu        sync.RWMutex
+}
+
+// NewExporter creates an Exporter for the given collector and sources.
+// metrics may be nil when only sources should be exported.
+func NewExporter(metrics *Metrics, sources ...Source) *Exporter {
+	return &Exporter{
+		Namespace: DefaultNamespace,
+		metrics:   metrics,
+		sources:   sources,
+	}
+}
+
+// AddSource registers an additional source of samples
+func (e *Exporter) AddSource(source Source) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.sources = append(e.sources, source)
+}
+
+// WriteText writes all metrics in the Prometheus text exposition format
+func (e *Exporter) WriteText(buf *bytes.Buffer) {
+	e.mu.RLock()
+	sources := make([]Source, len(e.sources))
+	copy(sources, e.sources)
+	e.mu.RUnlock()
+
+	written := make(map[string]bool)
+	if e.metrics != nil {
+		e.metrics.writeText(buf, e.Namespace, written)
+	}
+	var samples []Sample
+	for _, source := range sources {
+		samples = append(samples, source()...)
+	}
+	writeSamples(buf, e.Namespace, samples, written)
+}
+
+// ServeHTTP implements http.Handler for the Prometheus scrape endpoint
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	buf := util.GetBuffer()
+	defer util.PutBuffer(buf)
+	e.WriteText(buf)
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(buf.Bytes())
+	}
+}
+
+// writeText renders counters, gauges and bucketed histograms and records the
+// family names in written
+func (m *Metrics) writeText(buf *bytes.Buffer, namespace string, written map[string]bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	lastName := ""
+	for _, s := range sortedSeries(m.counters, namespace, "_total") {
+		name, key := s.name, s.key
+		if name != lastName {
+			writeHeader(buf, name, "", TypeCounter)
+			written[name] = true
+			lastName = name
+		}
+		writeSeries(buf, name, seriesLabels(key), "", float64(m.counters[key]))
+	}
+
+	lastName = ""
+	for _, s := range sortedSeries(m.gauges, namespace, "") {
+		name, key := s.name, s.key
+		if name != lastName {
+			writeHeader(buf, name, "", TypeGauge)
+			written[name] = true
+			lastName = name
+		}
+		writeSeries(buf, name, seriesLabels(key), "", m.gauges[key])
+	}
+
+	lastName = ""
+	for _, s := range sortedSeries(m.histograms, namespace, "") {
+		name, key := s.name, s.key
+		if name != lastName {
+			writeHeader(buf, name, "", "histogram")
+			written[name] = true
+			lastName = name
+		}
+		labels := seriesLabels(key)
+		h := m.histograms[key]
+
+		var cumulative int64
+		for i, bound := range m.conf.Buckets {
+			cumulative += h.buckets[i]
+			writeSeries(buf, name+"_bucket", labels, formatFloat(bound), float64(cumulative))
+		}
+		writeSeries(buf, name+"_bucket", labels, "+Inf", float64(h.count))
+		writeSeries(buf, name+"_sum", labels, "", h.sum)
+		writeSeries(buf, name+"_count", labels, "", float64(h.count))
+	}
+}
+
+// writeSamples renders samples provided by the sources, grouped into families
+// in the order their names first appear. Families already in written are skipped.
+func writeSamples(buf *bytes.Buffer, namespace string, samples []Sample, written map[string]bool) {
+	var names []string
+	families := make(map[string][]Sample)
+	for _, s := range samples {
+		name := metricName(namespace, s.Name)
+		if written[name] {
+			continue
+		}
+		if _, ok := families[name]; !ok {
+			names = append(names, name)
+		}
+		families[name] = append(families[name], s)
+	}
+
+	var labels strings.Builder
+	for _, name := range names {
+		family := families[name]
+		help, typ := "", ""
+		for _, s := range family {
+			if help == "" {
+				help = s.Help
+			}
+			if typ == "" {
+				typ = s.Type
+			}
+		}
+		writeHeader(buf, name, help, typ)
+		written[name] = true
+
+		for _, s := range family {
+			labels.Reset()
+			if len(s.Labels) > 0 {
+				writeLabels(&labels, s.Labels)
+			}
+			writeSeries(buf, name, strings.TrimSuffix(strings.TrimPrefix(labels.String(), "{"), "}"), "", s.Value)
+		}
+	}
+}
+
+// writeHeader writes the # HELP and # TYPE lines of a metric family
+func writeHeader(buf *bytes.Buffer, name, help, typ string) {
+	if help != "" {
+		buf.WriteString("# HELP ")
+		buf.WriteString(name)
+		buf.WriteByte(' ')
+		buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(help, `\`, `\\`), "\n", `\n`))
+		buf.WriteByte('\n')
+	}
+	if typ != "" {
+		buf.WriteString("# TYPE ")
+		buf.WriteString(name)
+		buf.WriteByte(' ')
+		buf.WriteString(typ)
+		buf.WriteByte('\n')
+	}
+}
+
+// writeSeries writes one sample line; le is added as a label when not empty
+func writeSeries(buf *bytes.Buffer, name, labels, le string, value float64) {
+	buf.WriteString(name)
+	if labels != "" || le != "" {
+		buf.WriteByte('{')
+		buf.WriteString(labels)
+		if le != "" {
+			if labels != "" {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(`le="`)
+			buf.WriteString(le)
+			buf.WriteByte('"')
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(formatFloat(value))
+	buf.WriteByte('\n')
+}
+
+// writeLabels renders tags as sorted, escaped Prometheus labels including braces
+func writeLabels(b *strings.Builder, tags map[string]string) {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(sanitizeName(k))
+		b.WriteString(`="`)
+		v := tags[k]
+		for j := 0; j < len(v); j++ {
+			switch v[j] {
+			case '\\':
+				b.WriteString(`\\`)
+			case '"':
+				b.WriteString(`\"`)
+			case '\n':
+				b.WriteString(`\n`)
+			default:
+				b.WriteByte(v[j])
+			}
+		}
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+}
+
+// metricName joins namespace and name and replaces characters not allowed by Prometheus
+func metricName(namespace, name string) string {
+	if namespace != "" {
+		name = namespace + "_" + name
+	}
+	return sanitizeName(name)
+}
+
+// sanitizeName replaces characters outside [a-zA-Z0-9_] with underscores
+func sanitizeName(name string) string {
+	b := []byte(name)
+	for i, c := range b {
+		valid := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (i > 0 && c >= '0' && c <= '9')
+		if !valid {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+// formatFloat formats a sample value as expected by the exposition format
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// series is a collector key with the metric name it is exported under
+type series struct {
+	name, key string
+}
+
+// sortedSeries returns the keys of a collector map with their exported metric
+// names, ending with suffix, sorted by name and then by key. Sorting by the
+// sanitized name keeps every series of a family together, even when keys such
+// as "log.x_y" sort between keys of the same family ("log.x", "log.x{...}").
+func sortedSeries[V any](m map[string]V, namespace, suffix string) []series {
+	list := make([]series, 0, len(m))
+	for k := range m {
+		name := metricName(namespace, seriesName(k))
+		if !strings.HasSuffix(name, suffix) {
+			name += suffix
+		}
+		list = append(list, series{name: name, key: k})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].name != list[j].name {
+			return list[i].name < list[j].name
+		}
+		return list[i].key < list[j].key
+	})
+	return list
+}
+
+// CoreMetricsSource exports entry, buffer and error counters from core.CoreMetrics
+func CoreMetricsSource(cm *core.CoreMetrics) Source {
+	return func() []Sample {
+		return []Sample{
+			{Name: "core_entries_created_total", Help: "Log entries allocated by the entry pool.", Type: TypeCounter, Value: float64(cm.EntryCreatedCount.Load())},
+			{Name: "core_entries_reused_total", Help: "Log entries reused from the entry pool.", Type: TypeCounter, Value: float64(cm.EntryReusedCount.Load())},
+			{Name: "core_entry_pool_misses_total", Help: "Entry pool misses.", Type: TypeCounter, Value: float64(cm.EntryPoolMissCount.Load())},
+			{Name: "core_entries_serialized_total", Help: "Log entries returned to the pool after use.", Type: TypeCounter, Value: float64(cm.EntrySerializedCount.Load())},
+			{Name: "core_buffer_gets_total", Help: "Buffers taken from the core buffer pool.", Type: TypeCounter, Value: float64(cm.BufferGetCount.Load())},
+			{Name: "core_buffer_puts_total", Help: "Buffers returned to the core buffer pool.", Type: TypeCounter, Value: float64(cm.BufferPutCount.Load())},
+			{Name: "core_buffer_misses_total", Help: "Core buffer pool misses.", Type: TypeCounter, Value: float64(cm.BufferMissCount.Load())},
+			{Name: "core_errors_total", Help: "Errors recorded by the core package.", Type: TypeCounter, Value: float64(cm.ErrorCount.Load())},
+			{Name: "core_processing_seconds_total", Help: "Total processing time recorded by the core package.", Type: TypeCounter, Value: float64(cm.ProcessingTime.Load()) / 1e9},
+		}
+	}
+}
+
+// PoolMetricsSource exports buffer, slice and map pool counters from util.PoolMetrics
+func PoolMetricsSource(pm *util.PoolMetrics) Source {
+	return func() []Sample {
+		return []Sample{
+			{Name: "pool_buffer_gets_total", Help: "Buffers taken from the util buffer pool.", Type: TypeCounter, Value: float64(pm.BufferGetCount())},
+			{Name: "pool_buffer_puts_total", Help: "Buffers returned to the util buffer pool.", Type: TypeCounter, Value: float64(pm.BufferPutCount())},
+			{Name: "pool_slice_gets_total", Help: "Byte slices taken from the util slice pools.", Type: TypeCounter, Value: float64(pm.SliceGetCount())},
+			{Name: "pool_slice_puts_total", Help: "Byte slices returned to the util slice pools.", Type: TypeCounter, Value: float64(pm.SlicePutCount())},
+			{Name: "pool_map_gets_total", Help: "Maps taken from the util map pool.", Type: TypeCounter, Value: float64(pm.MapGetCount())},
+			{Name: "pool_map_puts_total", Help: "Maps returned to the util map pool.", Type: TypeCounter, Value: float64(pm.MapPutCount())},
+			{Name: "pool_misses_total", Help: "Goroutine-local pool misses.", Type: TypeCounter, Value: float64(pm.PoolMissCount())},
+			{Name: "pool_discarded_total", Help: "Oversized items discarded instead of pooled.", Type: TypeCounter, Value: float64(pm.DiscardedCount())},
+		}
+	}
+}
+
+// BufferedSource exports the statistics reported by writer.Buffered.Stats
+func BufferedSource(bw *writer.Buffered) Source {
+	return func() []Sample {
+		stats := bw.Stats()
+		samples := make([]Sample, 0, 4)
+		if v, ok := stats["buffer_size"].(int); ok {
+			samples = append(samples, Sample{Name: "buffer_capacity", Help: "Capacity of the buffered writer queue.", Type: TypeGauge, Value: float64(v)})
+		}
+		if v, ok := stats["current_queue"].(int); ok {
+			samples = append(samples, Sample{Name: "buffer_queue_length", Help: "Writes waiting in the buffered writer queue.", Type: TypeGauge, Value: float64(v)})
+		}
+		if v, ok := stats["dropped_logs"].(int64); ok {
+			samples = append(samples, Sample{Name: "buffer_dropped_total", Help: "Writes dropped because the buffered writer queue was full.", Type: TypeCounter, Value: float64(v)})
+		}
+		if v, ok := stats["total_logs"].(int64); ok {
+			samples = append(samples, Sample{Name: "buffer_writes_total", Help: "Writes accepted by the buffered writer.", Type: TypeCounter, Value: float64(v)})
+		}
+		return samples
+	}
+}
@@ -0,0 +1,259 @@
+package metric
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/util"
+	"github.com/Lunar-Chipter/mire/writer"
+)
+
+// TestExporterCounters tests counter exposition with labels
+func TestExporterCounters(t *testing.T) {
+	m := NewMetrics()
+	m.IncrementCounter(core.INFO, map[string]string{"app": "api"})
+	m.IncrementCounter(core.INFO, map[string]string{"app": "api"})
+	m.IncrementCounter(core.INFO, map[string]string{"app": "worker"})
+	m.AddCounter(BytesWritten, 42, nil)
+
+	var buf bytes.Buffer
+	NewExporter(m).WriteText(&buf)
+	out := buf.String()
+
+	for _, want := range []string{
+		"# TYPE mire_log_info_total counter\n",
+		`mire_log_info_total{app="api"} 2` + "\n",
+		`mire_log_info_total{app="worker"} 1` + "\n",
+		"mire_log_bytes_written_total 42\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "# TYPE mire_log_info_total") != 1 {
+		t.Errorf("TYPE line should be written once per metric, got:\n%s", out)
+	}
+}
+
+// TestExporterFamilyGrouping tests that the series of a family are written
+// together under one TYPE line when other keys sort between them
+func TestExporterFamilyGrouping(t *testing.T) {
+	m := NewMetrics()
+	m.AddCounter("log.x", 1, nil)
+	m.AddCounter("log.x_y", 2, nil)
+	m.AddCounter("log.x", 3, map[string]string{"app": "api"})
+	m.RecordGauge("g.x", 1, nil)
+	m.RecordGauge("g.x_y", 2, nil)
+	m.RecordGauge("g.x", 3, map[string]string{"app": "api"})
+
+	var buf bytes.Buffer
+	NewExporter(m).WriteText(&buf)
+	out := buf.String()
+
+	for _, want := range []string{
+		"# TYPE mire_log_x_total counter\nmire_log_x_total 1\nmire_log_x_total{app=\"api\"} 3\n",
+		"# TYPE mire_log_x_y_total counter\nmire_log_x_y_total 2\n",
+		"# TYPE mire_g_x gauge\nmire_g_x 1\nmire_g_x{app=\"api\"} 3\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	for _, name := range []string{"mire_log_x_total", "mire_log_x_y_total", "mire_g_x", "mire_g_x_y"} {
+		if n := strings.Count(out, "# TYPE "+name+" "); n != 1 {
+			t.Errorf("Expected one TYPE line for %s, got %d:\n%s", name, n, out)
+		}
+	}
+}
+
+// TestExporterGauges tests gauge exposition
+func TestExporterGauges(t *testing.T) {
+	m := NewMetrics()
+	m.RecordGauge(AsyncQueueDepth, 7, nil)
+
+	var buf bytes.Buffer
+	NewExporter(m).WriteText(&buf)
+	out := buf.String()
+
+	if !strings.Contains(out, "# TYPE mire_log_async_queue_depth gauge\nmire_log_async_queue_depth 7\n") {
+		t.Errorf("Unexpected gauge output:\n%s", out)
+	}
+}
+
+// TestExporterHistogram tests cumulative buckets, sum and count
+func TestExporterHistogram(t *testing.T) {
+	m := NewMetricsWithConfig(HistogramConfig{Buckets: []float64{1, 0.1}})
+	tags := map[string]string{"env": "prod"}
+	m.RecordHistogram("latency", 0.05, tags)
+	m.RecordHistogram("latency", 0.5, tags)
+	m.RecordHistogram("latency", 2, tags)
+
+	var buf bytes.Buffer
+	NewExporter(m).WriteText(&buf)
+
+	expected := "# TYPE mire_latency histogram\n" +
+		`mire_latency_bucket{env="prod",le="0.1"} 1` + "\n" +
+		`mire_latency_bucket{env="prod",le="1"} 2` + "\n" +
+		`mire_latency_bucket{env="prod",le="+Inf"} 3` + "\n" +
+		`mire_latency_sum{env="prod"} 2.55` + "\n" +
+		`mire_latency_count{env="prod"} 3` + "\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+// TestExporterLabelEscaping tests label value escaping and name sanitizing
+func TestExporterLabelEscaping(t *testing.T) {
+	m := NewMetrics()
+	m.RecordGauge("queue.depth", 1, map[string]string{"path": "a\"b\\c\nd", "host-name": "x"})
+
+	var buf bytes.Buffer
+	NewExporter(m).WriteText(&buf)
+
+	want := `mire_queue_depth{host_name="x",path="a\"b\\c\nd"} 1`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("Expected %q, got:\n%s", want, buf.String())
+	}
+}
+
+// TestExporterNamespace tests a custom and an empty namespace
+func TestExporterNamespace(t *testing.T) {
+	m := NewMetrics()
+	m.RecordGauge("depth", 3, nil)
+
+	e := NewExporter(m)
+	e.Namespace = "app"
+	var buf bytes.Buffer
+	e.WriteText(&buf)
+	if !strings.Contains(buf.String(), "app_depth 3\n") {
+		t.Errorf("Expected custom namespace, got:\n%s", buf.String())
+	}
+
+	e.Namespace = ""
+	buf.Reset()
+	e.WriteText(&buf)
+	if !strings.Contains(buf.String(), "\ndepth 3\n") {
+		t.Errorf("Expected no namespace, got:\n%s", buf.String())
+	}
+}
+
+// TestExporterSources tests samples provided by sources
+func TestExporterSources(t *testing.T) {
+	e := NewExporter(nil, func() []Sample {
+		return []Sample{
+			{Name: "items_total", Help: "Items seen.", Type: TypeCounter, Labels: map[string]string{"kind": "a"}, Value: 1},
+			{Name: "items_total", Help: "Items seen.", Type: TypeCounter, Labels: map[string]string{"kind": "b"}, Value: 2},
+		}
+	})
+	e.AddSource(func() []Sample {
+		return []Sample{{Name: "ready", Type: TypeGauge, Value: 1}}
+	})
+
+	var buf bytes.Buffer
+	e.WriteText(&buf)
+
+	expected := "# HELP mire_items_total Items seen.\n" +
+		"# TYPE mire_items_total counter\n" +
+		`mire_items_total{kind="a"} 1` + "\n" +
+		`mire_items_total{kind="b"} 2` + "\n" +
+		"# TYPE mire_ready gauge\n" +
+		"mire_ready 1\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+// TestExporterDuplicateFamilies tests that a family shared by several sources,
+// or by a source and the collector, is written with a single header
+func TestExporterDuplicateFamilies(t *testing.T) {
+	m := NewMetrics()
+	m.IncrementCounter(core.INFO, nil)
+	queue := func(name string) Source {
+		return func() []Sample {
+			return []Sample{
+				{Name: "queue_length", Help: "Queued writes.", Type: TypeGauge, Labels: map[string]string{"queue": name}, Value: 1},
+				{Name: "log_info_total", Type: TypeCounter, Value: 99},
+			}
+		}
+	}
+	e := NewExporter(m, queue("a"), queue("b"))
+
+	var buf bytes.Buffer
+	e.WriteText(&buf)
+	out := buf.String()
+
+	for header, want := range map[string]int{
+		"# HELP mire_queue_length ":   1,
+		"# TYPE mire_queue_length ":   1,
+		"# TYPE mire_log_info_total ": 1,
+		"mire_log_info_total 99":      0,
+	} {
+		if got := strings.Count(out, header); got != want {
+			t.Errorf("Expected %q %d times, got %d in:\n%s", header, want, got, out)
+		}
+	}
+	expected := "# HELP mire_queue_length Queued writes.\n" +
+		"# TYPE mire_queue_length gauge\n" +
+		`mire_queue_length{queue="a"} 1` + "\n" +
+		`mire_queue_length{queue="b"} 1` + "\n"
+	if !strings.HasSuffix(out, expected) {
+		t.Errorf("Expected the merged family at the end:\n%s\ngot:\n%s", expected, out)
+	}
+}
+
+// TestBuiltinSources tests the core, pool and buffered writer sources
+func TestBuiltinSources(t *testing.T) {
+	bw := writer.NewBuffered(&bytes.Buffer{}, 8, time.Second, func(error) {}, 4, time.Second)
+	defer bw.Close()
+	_, _ = bw.Write([]byte("entry\n"))
+
+	var buf bytes.Buffer
+	NewExporter(nil,
+		CoreMetricsSource(core.GetCoreMetrics()),
+		PoolMetricsSource(util.GetPoolMetrics()),
+		BufferedSource(bw),
+	).WriteText(&buf)
+	out := buf.String()
+
+	for _, want := range []string{
+		"# TYPE mire_core_entries_created_total counter\n",
+		"# TYPE mire_pool_buffer_gets_total counter\n",
+		"mire_buffer_capacity 8\n",
+		"mire_buffer_writes_total 1\n",
+		"mire_buffer_dropped_total 0\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+// TestExporterServeHTTP tests the HTTP handler
+func TestExporterServeHTTP(t *testing.T) {
+	m := NewMetrics()
+	m.RecordGauge("depth", 5, nil)
+	e := NewExporter(m)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("Expected content type %q, got %q", ContentType, ct)
+	}
+	if !strings.Contains(rec.Body.String(), "mire_depth 5\n") {
+		t.Errorf("Unexpected body:\n%s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405 for POST, got %d", rec.Code)
+	}
+}
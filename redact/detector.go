@@ -0,0 +1,119 @@
+package redact
+
+import "regexp"
+
+// Detector finds one kind of sensitive data in text. When a candidate fails
+// Validate, its parts bounded by spaces and dashes are validated instead.
+type Detector struct {
+	Name     string                  // Name of the kind of data, used by Pseudonymize
+	Pattern  *regexp.Regexp          // Pattern of candidate matches
+	Group    int                     // Submatch that is redacted (0 for the whole match)
+	Validate func(match []byte) bool // Optional check of a candidate, such as a checksum
+}
+
+// Built-in detectors
+var (
+	// CreditCard finds payment card numbers of 13 to 19 digits, optionally
+	// grouped by spaces or dashes, that pass the Luhn check
+	CreditCard = &Detector{
+		Name:     "card",
+		Pattern:  regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		Validate: validLuhn,
+	}
+	// Email finds email addresses
+	Email = &Detector{
+		Name:    "email",
+		Pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`),
+	}
+	// IBAN finds international bank account numbers, optionally grouped by
+	// spaces, that pass the ISO 13616 mod-97 check
+	IBAN = &Detector{
+		Name:     "iban",
+		Pattern:  regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]){11,30}\b`),
+		Validate: validIBAN,
+	}
+	// JWT finds JSON Web Tokens
+	JWT = &Detector{
+		Name:    "jwt",
+		Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]+\.eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`),
+	}
+	// BearerToken finds the token of "Bearer" authorization credentials
+	BearerToken = &Detector{
+		Name:    "bearer",
+		Pattern: regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]+=*)`),
+		Group:   1,
+	}
+	// AWSAccessKey finds AWS access key IDs
+	AWSAccessKey = &Detector{
+		Name:    "aws_key",
+		Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA|AIPA)[A-Z0-9]{16}\b`),
+	}
+	// AWSSecretKey finds AWS secret access keys following their usual names
+	AWSSecretKey = &Detector{
+		Name:    "aws_secret",
+		Pattern: regexp.MustCompile(`(?i)aws_?secret_?(?:access_?)?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})\b`),
+		Group:   1,
+	}
+)
+
+// Detectors returns all built-in detectors. Tokens and keys come first, so
+// they are redacted as a whole, and IBANs before the card numbers they may contain.
+func Detectors() []*Detector {
+	return []*Detector{JWT, BearerToken, AWSAccessKey, AWSSecretKey, IBAN, CreditCard, Email}
+}
+
+// validLuhn reports whether the digits of s have a valid Luhn check digit
+func validLuhn(s []byte) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && n <= 19 && sum%10 == 0
+}
+
+// validIBAN reports whether s, without spaces, is 15 to 34 characters long
+// and passes the mod-97 check
+func validIBAN(s []byte) bool {
+	var iban [34]byte
+	n := 0
+	for _, c := range s {
+		if c == ' ' {
+			continue
+		}
+		if n == len(iban) {
+			return false
+		}
+		iban[n] = c
+		n++
+	}
+	if n < 15 {
+		return false
+	}
+
+	// Move the country code and check digits to the end and read letters as 10..35
+	rearranged := append(append(make([]byte, 0, n), iban[4:n]...), iban[:4]...)
+	rem := 0
+	for _, c := range rearranged {
+		switch {
+		case c >= '0' && c <= '9':
+			rem = (rem*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			rem = (rem*100 + int(c-'A') + 10) % 97
+		default:
+			return false
+		}
+	}
+	return rem == 1
+}
@@ -0,0 +1,321 @@
+package redact
+
+import (
+	"strings"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/errors"
+)
+
+// FieldRule redacts the values of the fields whose keys match Pattern.
+//
+// Patterns are matched case-insensitively against the whole key: '*' matches
+// any run of characters, dots included, and '?' any single byte. Nested keys
+// are dotted, as in "auth.token" for the "token" key of an "auth" group or
+// map, so "auth.*" matches everything under auth. A pattern without a dot
+// also matches the last element of a nested key: "password" matches both
+// "password" and "user.password".
+type FieldRule struct {
+	Pattern  string
+	Strategy Strategy
+}
+
+// PolicyConfig configures a Policy
+type PolicyConfig struct {
+	Rules    []FieldRule // Rules in priority order; the first rule matching a key applies
+	Mask     string      // Replacement written by Mask (default DefaultMask)
+	KeepLast int         // Characters PartialMask keeps (default DefaultKeepLast)
+	HMACKey  []byte      // Secret key of Pseudonymize; keep it out of the logs
+}
+
+// Policy redacts field values by key, whatever their content. It applies to
+// fields, key-value pairs, typed fields, the keys of maps held by Any fields
+// and the context values of an entry (trace_id, span_id, user_id, session_id
+// and request_id). It is safe for concurrent use.
+type Policy struct {
+	rules []FieldRule // Patterns lowercased
+	replacer
+}
+
+// NewPolicy creates a Policy. It returns errors.ErrRedactKeyRequired if a
+// rule uses Pseudonymize without an HMAC key.
+func NewPolicy(conf PolicyConfig) (*Policy, error) {
+	p := &Policy{
+		rules:    make([]FieldRule, len(conf.Rules)),
+		replacer: newReplacer(conf.Mask, conf.KeepLast, conf.HMACKey),
+	}
+	for i, rule := range conf.Rules {
+		if rule.Strategy == Pseudonymize && len(conf.HMACKey) == 0 {
+			return nil, errors.ErrRedactKeyRequired
+		}
+		p.rules[i] = FieldRule{Pattern: strings.ToLower(rule.Pattern), Strategy: rule.Strategy}
+	}
+	return p, nil
+}
+
+// Match returns the strategy of the first rule matching key
+func (p *Policy) Match(key string) (Strategy, bool) {
+	last := key[strings.LastIndexByte(key, '.')+1:]
+	for _, rule := range p.rules {
+		if globMatch(rule.Pattern, key) {
+			return rule.Strategy, true
+		}
+		if len(last) < len(key) && strings.IndexByte(rule.Pattern, '.') < 0 && globMatch(rule.Pattern, last) {
+			return rule.Strategy, true
+		}
+	}
+	return 0, false
+}
+
+// Apply redacts the values of the entry whose keys match the policy and
+// returns the number of values changed. Values are replaced, never modified
+// in place, and key-value pairs and typed fields are copied first, since they
+// belong to the caller. Fields, pairs and typed fields redacted with Drop are
+// removed.
+func (p *Policy) Apply(entry *core.LogEntry) int {
+	n := 0
+	for k, v := range entry.Fields {
+		if strategy, ok := p.Match(k); ok {
+			if strategy == Drop {
+				entry.DeleteField(k)
+			} else {
+				entry.Fields[k] = p.replace(nil, v, strategy, "")
+			}
+			n++
+		}
+	}
+	n += p.applyContext(&entry.TraceID, "trace_id")
+	n += p.applyContext(&entry.SpanID, "span_id")
+	n += p.applyContext(&entry.UserID, "user_id")
+	n += p.applyContext(&entry.SessionID, "session_id")
+	n += p.applyContext(&entry.RequestID, "request_id")
+	n += p.applyKeyVals(entry)
+	n += p.applyTypedFields(entry)
+	return n
+}
+
+// applyContext redacts a context value of the entry stored under key
+func (p *Policy) applyContext(value *[]byte, key string) int {
+	if len(*value) == 0 {
+		return 0
+	}
+	strategy, ok := p.Match(key)
+	if !ok {
+		return 0
+	}
+	if strategy == Drop {
+		*value = nil
+	} else {
+		*value = p.replace(nil, *value, strategy, "")
+	}
+	return 1
+}
+
+// applyKeyVals redacts the key-value pairs of the entry
+func (p *Policy) applyKeyVals(entry *core.LogEntry) int {
+	n := 0
+	var out [][]byte // Copy of KeyVals, made on the first match
+	for i := 0; i+1 < len(entry.KeyVals); i += 2 {
+		strategy, ok := p.Match(core.BytesToString(entry.KeyVals[i]))
+		if !ok {
+			if out != nil {
+				out = append(out, entry.KeyVals[i], entry.KeyVals[i+1])
+			}
+			continue
+		}
+		if out == nil {
+			out = append(make([][]byte, 0, len(entry.KeyVals)), entry.KeyVals[:i]...)
+		}
+		if strategy != Drop {
+			out = append(out, entry.KeyVals[i], p.replace(nil, entry.KeyVals[i+1], strategy, ""))
+		}
+		n++
+	}
+	if out != nil {
+		entry.KeyVals = out
+	}
+	return n
+}
+
+// applyTypedFields redacts the typed fields of the entry. A redacted field
+// becomes a string field; the maps of Any fields are redacted by nested key.
+func (p *Policy) applyTypedFields(entry *core.LogEntry) int {
+	n := 0
+	var out []core.Field // Copy of TypedFields, made on the first change
+	for i, field := range entry.TypedFields {
+		changed := false
+		if strategy, ok := p.Match(field.Key); ok {
+			if strategy == Drop {
+				field = core.Field{} // No type: left out of the copy
+			} else {
+				field = core.Field{Key: field.Key, Type: core.StringField, Str: string(p.replace(nil, typedValue(field), strategy, ""))}
+			}
+			changed = true
+		} else if field.Type == core.AnyField {
+			if value, m := p.redactNested(field.Iface, field.Key); m > 0 {
+				field.Iface = value
+				n += m - 1
+				changed = true
+			}
+		}
+		if changed && out == nil {
+			out = append(make([]core.Field, 0, len(entry.TypedFields)), entry.TypedFields[:i]...)
+		}
+		if changed {
+			n++
+		}
+		if out != nil && field.Type != 0 {
+			out = append(out, field)
+		}
+	}
+	if out != nil {
+		entry.TypedFields = out
+	}
+	return n
+}
+
+// redactNested returns a copy of a map (or of a slice holding maps) with the
+// values of the matching nested keys redacted, and the number of values
+// changed. Values without matching keys are returned as is.
+func (p *Policy) redactNested(value interface{}, prefix string) (interface{}, int) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		var out map[string]interface{}
+		n := 0
+		for k, elem := range v {
+			key := prefix + "." + k
+			drop := false
+			m := 1
+			if strategy, ok := p.Match(key); !ok {
+				elem, m = p.redactNested(elem, key)
+			} else if strategy == Drop {
+				drop = true
+			} else {
+				elem = string(p.replace(nil, nestedValue(elem), strategy, ""))
+			}
+			if m == 0 {
+				continue
+			}
+			if out == nil {
+				out = make(map[string]interface{}, len(v))
+				for k2, elem2 := range v {
+					out[k2] = elem2
+				}
+			}
+			if drop {
+				delete(out, k)
+			} else {
+				out[k] = elem
+			}
+			n += m
+		}
+		if out == nil {
+			return value, 0
+		}
+		return out, n
+	case map[string]string:
+		var out map[string]string
+		n := 0
+		for k, elem := range v {
+			strategy, ok := p.Match(prefix + "." + k)
+			if !ok {
+				continue
+			}
+			if out == nil {
+				out = make(map[string]string, len(v))
+				for k2, elem2 := range v {
+					out[k2] = elem2
+				}
+			}
+			if strategy == Drop {
+				delete(out, k)
+			} else {
+				out[k] = string(p.replace(nil, core.StringToBytes(elem), strategy, ""))
+			}
+			n++
+		}
+		if out == nil {
+			return value, 0
+		}
+		return out, n
+	case []interface{}:
+		var out []interface{}
+		n := 0
+		for i, elem := range v {
+			elem, m := p.redactNested(elem, prefix)
+			if m == 0 {
+				continue
+			}
+			if out == nil {
+				out = append([]interface{}(nil), v...)
+			}
+			out[i] = elem
+			n += m
+		}
+		if out == nil {
+			return value, 0
+		}
+		return out, n
+	}
+	return value, 0
+}
+
+// typedValue returns the text of a typed field, as the formatters write it
+func typedValue(field core.Field) []byte {
+	switch field.Type {
+	case core.StringField:
+		return core.StringToBytes(field.Str)
+	case core.BytesField:
+		if b, ok := field.Iface.([]byte); ok {
+			return b
+		}
+	}
+	return field.AppendText(nil)
+}
+
+// nestedValue returns the text of a value held by a map of an Any field
+func nestedValue(value interface{}) []byte {
+	if s, ok := value.(string); ok {
+		return core.StringToBytes(s)
+	}
+	return core.Field{Type: core.AnyField, Iface: value}.AppendText(nil)
+}
+
+// globMatch reports whether name matches a lowercase pattern, ignoring the
+// case of ASCII letters in name
+func globMatch(pattern, name string) bool {
+	p, n := 0, 0
+	star, next := -1, 0 // Position of the last '*' and of the name after it
+	for n < len(name) {
+		if p < len(pattern) {
+			switch c := pattern[p]; {
+			case c == '*':
+				star, next = p, n
+				p++
+				continue
+			case c == '?' || c == lower(name[n]):
+				p++
+				n++
+				continue
+			}
+		}
+		if star < 0 {
+			return false
+		}
+		// Let the last '*' match one more byte and retry
+		next++
+		p, n = star+1, next
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// lower returns the lowercase of an ASCII letter and c otherwise
+func lower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
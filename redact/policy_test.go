@@ -0,0 +1,135 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/errors"
+)
+
+// TestGlobMatch tests case-insensitive glob matching
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"password", "Password", true},
+		{"password", "passwords", false},
+		{"*password*", "DB_PASSWORD_HASH", true},
+		{"*token", "access_token", true},
+		{"*token", "token_type", false},
+		{"auth.*", "auth.token", true},
+		{"auth.*", "auth.oauth.secret", true},
+		{"auth.*", "oauth.token", false},
+		{"a*b*c", "aXXbYYbc", true},
+		{"key?", "key1", true},
+		{"key?", "key", false},
+		{"*", "", true},
+	}
+	for _, tt := range tests {
+		if got := globMatch(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("globMatch(%q, %q) = %v, expected %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestPolicyMatch tests rule priority and matching of nested keys
+func TestPolicyMatch(t *testing.T) {
+	p, err := NewPolicy(PolicyConfig{Rules: []FieldRule{
+		{"auth.*", Drop},
+		{"Password", Mask},
+		{"user.email", PartialMask},
+	}})
+	if err != nil {
+		t.Fatalf("NewPolicy failed: %v", err)
+	}
+	tests := []struct {
+		key  string
+		want Strategy
+		ok   bool
+	}{
+		{"auth.password", Drop, true},
+		{"PASSWORD", Mask, true},
+		{"user.password", Mask, true},
+		{"user.email", PartialMask, true},
+		{"email", 0, false},
+		{"admin.user.email", 0, false},
+	}
+	for _, tt := range tests {
+		if got, ok := p.Match(tt.key); got != tt.want || ok != tt.ok {
+			t.Errorf("Match(%q) = %v, %v, expected %v, %v", tt.key, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if _, err := NewPolicy(PolicyConfig{Rules: []FieldRule{{"token", Pseudonymize}}}); err != errors.ErrRedactKeyRequired {
+		t.Errorf("Expected ErrRedactKeyRequired, got %v", err)
+	}
+}
+
+// TestPolicyApply tests redaction of every part of an entry, without
+// modifying the slices and maps owned by the caller
+func TestPolicyApply(t *testing.T) {
+	p, _ := NewPolicy(PolicyConfig{Rules: []FieldRule{
+		{"*password*", Mask},
+		{"*secret*", Drop},
+		{"card", PartialMask},
+		{"user_id", Mask},
+	}})
+
+	keyvals := [][]byte{[]byte("user"), []byte("jane"), []byte("client_secret"), []byte("s3"), []byte("Password"), []byte("pw")}
+	nested := map[string]interface{}{"name": "jane", "password": "pw", "keys": []interface{}{map[string]interface{}{"secret": "s3"}}}
+	typed := []core.Field{
+		{Key: "card", Type: core.StringField, Str: "4111111111111111"},
+		{Key: "attempts", Type: core.Int64Field, Int: 3},
+		{Key: "user", Type: core.AnyField, Iface: nested},
+		{Key: "api_secret", Type: core.StringField, Str: "s3"},
+	}
+	entry := &core.LogEntry{KeyVals: keyvals, TypedFields: typed, UserID: []byte("42")}
+	entry.SetField("db_password", []byte("pw"))
+	entry.SetField("shared_secret", []byte("s3"))
+	entry.SetField("host", []byte("db1"))
+
+	if n := p.Apply(entry); n != 9 {
+		t.Errorf("Expected 9 values changed, got %d", n)
+	}
+
+	if string(entry.Fields["db_password"]) != DefaultMask || string(entry.Fields["host"]) != "db1" {
+		t.Errorf("Unexpected fields %q", entry.Fields)
+	}
+	if _, ok := entry.Fields["shared_secret"]; ok || len(entry.FieldKeys) != 2 {
+		t.Errorf("Expected dropped field to be removed, got %q", entry.FieldKeys)
+	}
+	if string(entry.UserID) != DefaultMask {
+		t.Errorf("Expected user_id context value to be masked, got %q", entry.UserID)
+	}
+
+	if len(entry.KeyVals) != 4 || string(entry.KeyVals[1]) != "jane" || string(entry.KeyVals[3]) != DefaultMask {
+		t.Errorf("Unexpected key-value pairs %q", entry.KeyVals)
+	}
+	if string(keyvals[3]) != "s3" || string(keyvals[5]) != "pw" {
+		t.Error("Expected caller key-value pairs to be left untouched")
+	}
+
+	if len(entry.TypedFields) != 3 || entry.TypedFields[0].Str != "************1111" || entry.TypedFields[1].Int != 3 {
+		t.Errorf("Unexpected typed fields %+v", entry.TypedFields)
+	}
+	user := entry.TypedFields[2].Iface.(map[string]interface{})
+	keys := user["keys"].([]interface{})[0].(map[string]interface{})
+	if user["password"] != DefaultMask || user["name"] != "jane" || len(keys) != 0 {
+		t.Errorf("Unexpected nested value %v", user)
+	}
+	if typed[0].Str != "4111111111111111" || nested["password"] != "pw" || len(nested["keys"].([]interface{})[0].(map[string]interface{})) != 1 {
+		t.Error("Expected caller typed fields and maps to be left untouched")
+	}
+}
+
+// TestPolicyApplyTypedValues tests the redaction of typed fields that are not strings
+func TestPolicyApplyTypedValues(t *testing.T) {
+	p, _ := NewPolicy(PolicyConfig{Rules: []FieldRule{{"pin", Pseudonymize}}, HMACKey: []byte("secret")})
+	entry := &core.LogEntry{TypedFields: []core.Field{{Key: "pin", Type: core.Int64Field, Int: 1234}}}
+	p.Apply(entry)
+	field := entry.TypedFields[0]
+	if field.Type != core.StringField || len(field.Str) != 2+2*pseudonymSize || field.Str[0] != '[' {
+		t.Errorf("Unexpected pseudonymized field %+v", field)
+	}
+}
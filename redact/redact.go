@@ -0,0 +1,323 @@
+// Package redact finds and redacts sensitive data, such as payment card
+// numbers, email addresses and credentials, in log messages and field values.
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"unicode/utf8"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/errors"
+)
+
+// Strategy selects how detected data is replaced
+type Strategy int
+
+const (
+	// Mask replaces the data with Config.Mask
+	Mask Strategy = iota
+	// PartialMask replaces the letters and digits of the data with '*',
+	// except the last Config.KeepLast (at most half of them), and keeps separators
+	PartialMask
+	// Pseudonymize replaces the data with a keyed HMAC-SHA256 of it, so that
+	// equal values can be correlated across entries without being revealed
+	Pseudonymize
+	// Drop removes the data, or the whole field for a Policy
+	Drop
+)
+
+const (
+	// DefaultMask is the replacement used by Mask when Config.Mask is empty
+	DefaultMask = "[REDACTED]"
+	// DefaultKeepLast is the number of characters PartialMask keeps when Config.KeepLast is 0
+	DefaultKeepLast = 4
+
+	pseudonymSize = 8 // Bytes of the HMAC written as hex by Pseudonymize
+)
+
+// Rule redacts the data found by a detector with a strategy
+type Rule struct {
+	Detector *Detector
+	Strategy Strategy
+}
+
+// Config configures a Redactor
+type Config struct {
+	Rules    []Rule // Rules in priority order; data found by an earlier rule is not matched again (default: Detectors with Mask)
+	Mask     string // Replacement written by Mask (default DefaultMask)
+	KeepLast int    // Characters PartialMask keeps (default DefaultKeepLast)
+	HMACKey  []byte // Secret key of Pseudonymize; keep it out of the logs
+}
+
+// Redactor redacts sensitive data in text and log entries.
+// It is safe for concurrent use.
+type Redactor struct {
+	conf Config
+	replacer
+}
+
+// replacer writes the replacement of redacted data
+type replacer struct {
+	mask     string
+	keepLast int
+	hmacKey  []byte
+}
+
+// span is a part of a text to redact
+type span struct {
+	start, end int
+	rule       *Rule
+}
+
+// New creates a Redactor. It returns errors.ErrRedactKeyRequired if a rule
+// uses Pseudonymize without an HMAC key.
+func New(conf Config) (*Redactor, error) {
+	if conf.Rules == nil {
+		for _, d := range Detectors() {
+			conf.Rules = append(conf.Rules, Rule{Detector: d, Strategy: Mask})
+		}
+	}
+	for _, rule := range conf.Rules {
+		if rule.Strategy == Pseudonymize && len(conf.HMACKey) == 0 {
+			return nil, errors.ErrRedactKeyRequired
+		}
+	}
+	return &Redactor{conf: conf, replacer: newReplacer(conf.Mask, conf.KeepLast, conf.HMACKey)}, nil
+}
+
+// newReplacer creates a replacer, applying the defaults of Config
+func newReplacer(mask string, keepLast int, hmacKey []byte) replacer {
+	if mask == "" {
+		mask = DefaultMask
+	}
+	if keepLast <= 0 {
+		keepLast = DefaultKeepLast
+	}
+	return replacer{mask: mask, keepLast: keepLast, hmacKey: hmacKey}
+}
+
+// Redact returns text with the sensitive data it contains replaced.
+// Text without sensitive data is returned as is.
+func (r *Redactor) Redact(text []byte) []byte {
+	out, _ := r.redact(text)
+	return out
+}
+
+// RedactString is Redact for strings
+func (r *Redactor) RedactString(s string) string {
+	out, changed := r.redact(core.StringToBytes(s))
+	if !changed {
+		return s
+	}
+	return string(out)
+}
+
+// RedactEntry redacts the message, the field values, the key-value pairs and
+// the string and byte slice typed fields of an entry, and returns the number
+// of values changed. Values are replaced, never modified in place, and
+// key-value pairs and typed fields are copied first, since they belong to the
+// caller. Error messages and typed fields resolved by the formatter, such as
+// Stringer and Any fields, are not inspected.
+func (r *Redactor) RedactEntry(entry *core.LogEntry) int {
+	n := 0
+	if out, changed := r.redact(entry.Message); changed {
+		entry.Message = out
+		n++
+	}
+	for k, v := range entry.Fields {
+		if out, changed := r.redact(v); changed {
+			entry.Fields[k] = out
+			n++
+		}
+	}
+
+	copied := false
+	for i := 1; i < len(entry.KeyVals); i += 2 {
+		out, changed := r.redact(entry.KeyVals[i])
+		if !changed {
+			continue
+		}
+		if !copied {
+			entry.KeyVals = append([][]byte(nil), entry.KeyVals...)
+			copied = true
+		}
+		entry.KeyVals[i] = out
+		n++
+	}
+
+	copied = false
+	for i := range entry.TypedFields {
+		field := &entry.TypedFields[i]
+		var value []byte
+		switch field.Type {
+		case core.StringField:
+			value = core.StringToBytes(field.Str)
+		case core.BytesField:
+			value, _ = field.Iface.([]byte)
+		default:
+			continue
+		}
+		out, changed := r.redact(value)
+		if !changed {
+			continue
+		}
+		if !copied {
+			entry.TypedFields = append([]core.Field(nil), entry.TypedFields...)
+			field = &entry.TypedFields[i]
+			copied = true
+		}
+		if field.Type == core.StringField {
+			field.Str = core.BytesToString(out)
+		} else {
+			field.Iface = out
+		}
+		n++
+	}
+	return n
+}
+
+// redact replaces the data found by the rules and reports whether text changed
+func (r *Redactor) redact(text []byte) ([]byte, bool) {
+	if len(text) == 0 {
+		return text, false
+	}
+
+	var stack [8]span
+	spans := stack[:0]
+	for i := range r.conf.Rules {
+		rule := &r.conf.Rules[i]
+		d := rule.Detector
+		for _, m := range d.Pattern.FindAllSubmatchIndex(text, -1) {
+			start, end := m[2*d.Group], m[2*d.Group+1]
+			if start < 0 || start == end || overlaps(spans, start, end) {
+				continue
+			}
+			start, end, ok := validPart(d, text, start, end)
+			if !ok {
+				continue
+			}
+			spans = append(spans, span{start: start, end: end, rule: rule})
+		}
+	}
+	if len(spans) == 0 {
+		return text, false
+	}
+
+	// Spans are few, so an insertion sort by position is enough
+	for i := 1; i < len(spans); i++ {
+		for j := i; j > 0 && spans[j].start < spans[j-1].start; j-- {
+			spans[j], spans[j-1] = spans[j-1], spans[j]
+		}
+	}
+
+	out := make([]byte, 0, len(text)+len(r.mask))
+	prev := 0
+	for _, s := range spans {
+		out = append(out, text[prev:s.start]...)
+		out = r.replace(out, text[s.start:s.end], s.rule.Strategy, s.rule.Detector.Name)
+		prev = s.end
+	}
+	return append(out, text[prev:]...), true
+}
+
+// validPart returns the part of the candidate text[start:end] that passes the
+// validation of the detector. When the whole candidate fails, the parts
+// bounded by spaces and dashes are tried, prefixes and longest first, since a
+// pattern may run on into the digits that follow, as in "4111111111111111 123".
+func validPart(d *Detector, text []byte, start, end int) (int, int, bool) {
+	if d.Validate == nil || d.Validate(text[start:end]) {
+		return start, end, true
+	}
+	var stack [32]int
+	seps := stack[:0] // Separators inside the candidate
+	for i := start + 1; i < end-1; i++ {
+		if text[i] == ' ' || text[i] == '-' {
+			seps = append(seps, i)
+		}
+	}
+	for i := -1; i < len(seps); i++ {
+		from := start
+		if i >= 0 {
+			from = seps[i] + 1
+		}
+		if d.Validate(text[from:end]) {
+			return from, end, true
+		}
+		for j := len(seps) - 1; j > i; j-- {
+			if d.Validate(text[from:seps[j]]) {
+				return from, seps[j], true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// replace appends the replacement of data to out. Pseudonyms are prefixed
+// with name, the kind of data, when it is not empty.
+func (r *replacer) replace(out, data []byte, strategy Strategy, name string) []byte {
+	switch strategy {
+	case PartialMask:
+		// Keep at most half of the characters, so short values are not revealed
+		alnum := 0
+		for _, c := range data {
+			if isAlnum(c) {
+				alnum++
+			}
+		}
+		keep := min(r.keepLast, alnum/2)
+		from := len(data) // Index from which characters are kept
+		for kept := 0; kept < keep; {
+			from--
+			if isAlnum(data[from]) {
+				kept++
+			}
+		}
+		for i := 0; i < from; {
+			c := data[i]
+			if c >= utf8.RuneSelf {
+				_, size := utf8.DecodeRune(data[i:])
+				out = append(out, '*')
+				i += size
+				continue
+			}
+			if isAlnum(c) {
+				c = '*'
+			}
+			out = append(out, c)
+			i++
+		}
+		return append(out, data[from:]...)
+	case Pseudonymize:
+		mac := hmac.New(sha256.New, r.hmacKey)
+		mac.Write(data)
+		var sum [sha256.Size]byte
+		out = append(out, '[')
+		if name != "" {
+			out = append(out, name...)
+			out = append(out, ':')
+		}
+		out = hex.AppendEncode(out, mac.Sum(sum[:0])[:pseudonymSize])
+		return append(out, ']')
+	case Drop:
+		return out
+	default:
+		return append(out, r.mask...)
+	}
+}
+
+// overlaps reports whether [start, end) overlaps one of the spans
+func overlaps(spans []span, start, end int) bool {
+	for _, s := range spans {
+		if start < s.end && s.start < end {
+			return true
+		}
+	}
+	return false
+}
+
+// isAlnum reports whether c is an ASCII letter or digit
+func isAlnum(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -17,25 +17,43 @@ func GetCallerInfo(skip int) *core.Caller {
 	ci.File = filepath.Base(file)
 	ci.Line = line
 
-	fn := runtime.FuncForPC(pc)
-	var fullName string
-	if fn != nil {
-		fullName = fn.Name()
-		lastSlash := strings.LastIndex(fullName, "/")
-		if lastSlash > 0 {
-			pkgNameEnd := strings.Index(fullName[lastSlash+1:], ".")
-			if pkgNameEnd > 0 {
-				ci.Package = fullName[lastSlash+1 : lastSlash+1+pkgNameEnd]
-				ci.Function = fullName[lastSlash+1+pkgNameEnd+1:]
-			} else {
-				ci.Function = fullName
-			}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		setCallerFunction(ci, fn.Name())
+	}
+
+	return ci
+}
+
+// GetCallerInfoForPC returns the caller information of a program counter,
+// such as the one recorded by log/slog, or nil if pc is zero
+func GetCallerInfoForPC(pc uintptr) *core.Caller {
+	if pc == 0 {
+		return nil
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+
+	ci := core.GetCallerFromPool()
+	ci.File = filepath.Base(frame.File)
+	ci.Line = frame.Line
+	setCallerFunction(ci, frame.Function)
+	return ci
+}
+
+// setCallerFunction splits a fully qualified function name into the package
+// and function of ci
+func setCallerFunction(ci *core.Caller, fullName string) {
+	lastSlash := strings.LastIndex(fullName, "/")
+	if lastSlash > 0 {
+		pkgNameEnd := strings.Index(fullName[lastSlash+1:], ".")
+		if pkgNameEnd > 0 {
+			ci.Package = fullName[lastSlash+1 : lastSlash+1+pkgNameEnd]
+			ci.Function = fullName[lastSlash+1+pkgNameEnd+1:]
 		} else {
 			ci.Function = fullName
 		}
+	} else {
+		ci.Function = fullName
 	}
-
-	return ci
 }
 
 // GetStackTrace returns a stack trace as a []byte slice from a pooled buffer,
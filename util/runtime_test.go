@@ -38,6 +38,26 @@ func TestGetCallerInfo(t *testing.T) {
 	}
 }
 
+// TestGetCallerInfoForPC tests resolving the caller of a program counter
+func TestGetCallerInfoForPC(t *testing.T) {
+	if GetCallerInfoForPC(0) != nil {
+		t.Error("Expected nil caller for a zero pc")
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(1, pcs[:])
+	_, _, line, _ := runtime.Caller(0)
+	callerInfo := GetCallerInfoForPC(pcs[0])
+	defer core.PutCallerToPool(callerInfo)
+
+	if callerInfo.File != "runtime_test.go" || callerInfo.Line != line-1 {
+		t.Errorf("Expected runtime_test.go:%d, got %s:%d", line-1, callerInfo.File, callerInfo.Line)
+	}
+	if callerInfo.Package != "util" || callerInfo.Function != "TestGetCallerInfoForPC" {
+		t.Errorf("Expected util.TestGetCallerInfoForPC, got %s.%s", callerInfo.Package, callerInfo.Function)
+	}
+}
+
 // TestGetCallerInfoSkip2 tests the GetCallerInfo function with skip=2
 func TestGetCallerInfoSkip2(t *testing.T) {
 	// Create a helper function that calls GetCallerInfo
@@ -0,0 +1,211 @@
+package util
+
+import (
+	"context"
+	"net/http"
+)
+
+// W3C Trace Context header names
+const (
+	TraceparentHeader = "traceparent"
+	TracestateHeader  = "tracestate"
+)
+
+// TraceFlagsSampled is the W3C trace flag marking a sampled trace
+const TraceFlagsSampled byte = 0x01
+
+// SpanContext identifies a span as propagated by W3C Trace Context.
+// IDs are lowercase hex strings: 32 digits for TraceID and 16 for SpanID.
+type SpanContext struct {
+	TraceID    string
+	SpanID     string
+	TraceFlags byte
+	TraceState string
+	Remote     bool // Set when the span context was received from another process
+}
+
+// IsValid reports whether the trace and span IDs are well-formed and not all zero
+func (sc SpanContext) IsValid() bool {
+	return isTraceHex(sc.TraceID, 32) && isTraceHex(sc.SpanID, 16)
+}
+
+// IsSampled reports whether the sampled flag is set
+func (sc SpanContext) IsSampled() bool {
+	return sc.TraceFlags&TraceFlagsSampled != 0
+}
+
+// TraceFlagsHex returns the trace flags as two lowercase hex digits without allocating
+func (sc SpanContext) TraceFlagsHex() []byte {
+	return traceFlagsHex[sc.TraceFlags][:]
+}
+
+// Traceparent formats the span context as a version 00 traceparent header value
+func (sc SpanContext) Traceparent() string {
+	b := make([]byte, 0, 55)
+	b = append(b, "00-"...)
+	b = append(b, sc.TraceID...)
+	b = append(b, '-')
+	b = append(b, sc.SpanID...)
+	b = append(b, '-')
+	b = append(b, sc.TraceFlagsHex()...)
+	return string(b)
+}
+
+// traceFlagsHex holds the hex form of every flags byte
+var traceFlagsHex = func() (t [256][2]byte) {
+	const digits = "0123456789abcdef"
+	for i := range t {
+		t[i] = [2]byte{digits[i>>4], digits[i&0x0f]}
+	}
+	return t
+}()
+
+// ParseTraceparent parses a W3C traceparent header value such as
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+// Versions above 00 are accepted as long as they start with the version 00 fields.
+func ParseTraceparent(value string) (SpanContext, bool) {
+	// version(2) - trace-id(32) - parent-id(16) - flags(2)
+	if len(value) < 55 || value[2] != '-' || value[35] != '-' || value[52] != '-' {
+		return SpanContext{}, false
+	}
+
+	version, ok := parseHexByte(value[0:2])
+	if !ok || version == 0xff || (version == 0 && len(value) != 55) {
+		return SpanContext{}, false
+	}
+	if len(value) > 55 && value[55] != '-' {
+		return SpanContext{}, false
+	}
+
+	flags, ok := parseHexByte(value[53:55])
+	if !ok {
+		return SpanContext{}, false
+	}
+
+	sc := SpanContext{
+		TraceID:    value[3:35],
+		SpanID:     value[36:52],
+		TraceFlags: flags,
+		Remote:     true,
+	}
+	if !sc.IsValid() {
+		return SpanContext{}, false
+	}
+	return sc, true
+}
+
+// isTraceHex reports whether s is n lowercase hex digits, not all zero
+func isTraceHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	nonZero := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '0':
+		case c >= '1' && c <= '9', c >= 'a' && c <= 'f':
+			nonZero = true
+		default:
+			return false
+		}
+	}
+	return nonZero
+}
+
+// parseHexByte parses two lowercase hex digits
+func parseHexByte(s string) (byte, bool) {
+	hi, ok1 := hexDigit(s[0])
+	lo, ok2 := hexDigit(s[1])
+	return hi<<4 | lo, ok1 && ok2
+}
+
+func hexDigit(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	}
+	return 0, false
+}
+
+// spanContextKey is the context key for the propagated SpanContext
+type spanContextKey struct{}
+
+// WithSpanContext adds a span context to ctx
+func WithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// SpanContextFromContext returns the span context stored with WithSpanContext
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	if ctx == nil {
+		return SpanContext{}, false
+	}
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc, ok && sc.IsValid()
+}
+
+// SpanContextProvider returns the span context active in ctx.
+// Implement it to bridge a tracing library, for example by converting
+// the OpenTelemetry span context of ctx, without the logger depending on it.
+type SpanContextProvider interface {
+	SpanContext(ctx context.Context) (SpanContext, bool)
+}
+
+// SpanContextProviderFunc adapts a function to SpanContextProvider
+type SpanContextProviderFunc func(ctx context.Context) (SpanContext, bool)
+
+// SpanContext calls f(ctx)
+func (f SpanContextProviderFunc) SpanContext(ctx context.Context) (SpanContext, bool) {
+	return f(ctx)
+}
+
+// ContextSpanProvider reads span contexts stored with WithSpanContext
+var ContextSpanProvider SpanContextProvider = SpanContextProviderFunc(SpanContextFromContext)
+
+// ExtractTraceContext reads the traceparent and tracestate headers
+func ExtractTraceContext(header http.Header) (SpanContext, bool) {
+	sc, ok := ParseTraceparent(header.Get(TraceparentHeader))
+	if !ok {
+		return SpanContext{}, false
+	}
+	// Multiple tracestate headers are combined as a comma-separated list
+	if states := header.Values(TracestateHeader); len(states) == 1 {
+		sc.TraceState = states[0]
+	} else if len(states) > 1 {
+		b := make([]byte, 0, 64)
+		for i, state := range states {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = append(b, state...)
+		}
+		sc.TraceState = string(b)
+	}
+	return sc, true
+}
+
+// InjectTraceContext writes the traceparent and tracestate headers for sc
+func InjectTraceContext(header http.Header, sc SpanContext) {
+	if !sc.IsValid() {
+		return
+	}
+	header.Set(TraceparentHeader, sc.Traceparent())
+	if sc.TraceState != "" {
+		header.Set(TracestateHeader, sc.TraceState)
+	}
+}
+
+// TraceContextMiddleware parses incoming traceparent and tracestate headers into the
+// request context, so loggers called with r.Context() add trace_id, span_id and
+// trace_flags to their entries. Requests without a valid traceparent pass through unchanged.
+func TraceContextMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sc, ok := ExtractTraceContext(r.Header); ok {
+			r = r.WithContext(WithSpanContext(r.Context(), sc))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
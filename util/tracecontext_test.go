@@ -0,0 +1,123 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const (
+	testTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testSpanID      = "00f067aa0ba902b7"
+	testTraceparent = "00-" + testTraceID + "-" + testSpanID + "-01"
+)
+
+// TestParseTraceparent tests parsing valid and invalid traceparent values
+func TestParseTraceparent(t *testing.T) {
+	sc, ok := ParseTraceparent(testTraceparent)
+	if !ok {
+		t.Fatal("Expected valid traceparent")
+	}
+	if sc.TraceID != testTraceID || sc.SpanID != testSpanID || sc.TraceFlags != 0x01 || !sc.Remote {
+		t.Errorf("Unexpected span context %+v", sc)
+	}
+	if !sc.IsSampled() || string(sc.TraceFlagsHex()) != "01" {
+		t.Errorf("Expected sampled flags 01, got %q", sc.TraceFlagsHex())
+	}
+	if sc.Traceparent() != testTraceparent {
+		t.Errorf("Expected round trip %s, got %s", testTraceparent, sc.Traceparent())
+	}
+
+	// Future versions may append fields
+	if _, ok := ParseTraceparent("01-" + testTraceID + "-" + testSpanID + "-00-extra"); !ok {
+		t.Error("Expected future version with extra fields to parse")
+	}
+
+	invalid := []string{
+		"",
+		"00-" + testTraceID + "-" + testSpanID,
+		"00-" + testTraceID + "-" + testSpanID + "-01-extra",
+		"ff-" + testTraceID + "-" + testSpanID + "-01",
+		"00-00000000000000000000000000000000-" + testSpanID + "-01",
+		"00-" + testTraceID + "-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-" + testSpanID + "-01",
+		"00-" + testTraceID + "-" + testSpanID + "-zz",
+		"00_" + testTraceID + "-" + testSpanID + "-01",
+		"01-" + testTraceID + "-" + testSpanID + "-01x",
+	}
+	for _, value := range invalid {
+		if _, ok := ParseTraceparent(value); ok {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+}
+
+// TestSpanContextInContext tests storing and reading span contexts
+func TestSpanContextInContext(t *testing.T) {
+	if _, ok := SpanContextFromContext(context.Background()); ok {
+		t.Error("Expected no span context in empty context")
+	}
+
+	sc := SpanContext{TraceID: testTraceID, SpanID: testSpanID}
+	ctx := WithSpanContext(context.Background(), sc)
+	got, ok := ContextSpanProvider.SpanContext(ctx)
+	if !ok || got.TraceID != testTraceID {
+		t.Errorf("Expected stored span context, got %+v", got)
+	}
+
+	ctx = WithSpanContext(context.Background(), SpanContext{TraceID: "bad"})
+	if _, ok := SpanContextFromContext(ctx); ok {
+		t.Error("Expected invalid span context to be ignored")
+	}
+}
+
+// TestTraceContextHeaders tests extracting and injecting trace headers
+func TestTraceContextHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set(TraceparentHeader, testTraceparent)
+	header.Add(TracestateHeader, "congo=t61rcWkgMzE")
+	header.Add(TracestateHeader, "rojo=00f067aa0ba902b7")
+
+	sc, ok := ExtractTraceContext(header)
+	if !ok {
+		t.Fatal("Expected trace context in headers")
+	}
+	if sc.TraceState != "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7" {
+		t.Errorf("Unexpected tracestate %q", sc.TraceState)
+	}
+
+	out := http.Header{}
+	InjectTraceContext(out, sc)
+	if out.Get(TraceparentHeader) != testTraceparent || out.Get(TracestateHeader) != sc.TraceState {
+		t.Errorf("Unexpected injected headers %v", out)
+	}
+
+	InjectTraceContext(out, SpanContext{})
+	if out.Get(TraceparentHeader) != testTraceparent {
+		t.Error("Invalid span context should not overwrite headers")
+	}
+}
+
+// TestTraceContextMiddleware tests that the middleware stores the span context on the request
+func TestTraceContextMiddleware(t *testing.T) {
+	var got SpanContext
+	var found bool
+	handler := TraceContextMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, found = SpanContextFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(TraceparentHeader, testTraceparent)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if !found || got.SpanID != testSpanID {
+		t.Errorf("Expected span context in request context, got %+v", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(TraceparentHeader, "garbage")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if found {
+		t.Error("Expected no span context for an invalid header")
+	}
+}
@@ -21,6 +21,19 @@ type LogProcessor interface {
 	ErrOutMu() *sync.Mutex
 }
 
+// TypedLogProcessor is an optional interface for processors that accept typed fields
+// and an attached error. Processors that do not implement it receive the fields and
+// the error message as text key-value pairs.
+type TypedLogProcessor interface {
+	LogFields(ctx context.Context, level core.Level, msg []byte, err error, fields []core.Field)
+}
+
+// MapLogProcessor is an optional interface for processors that accept map fields.
+// Processors that do not implement it receive the fields as key-value pairs.
+type MapLogProcessor interface {
+	LogMap(ctx context.Context, level core.Level, msg []byte, fields map[string][]byte)
+}
+
 // AsyncLogger provides asynchronous logging to reduce latency
 type AsyncLogger struct {
 	processor                   LogProcessor
@@ -28,17 +41,27 @@ type AsyncLogger struct {
 	wg                          sync.WaitGroup
 	workerCount                 int
 	closed                      atomic.Bool
+	dropped                     atomic.Int64
+	pending                     atomic.Int64 // Jobs queued or being processed
+	idleMu                      sync.Mutex
+	idle                        chan struct{} // Closed when pending drops to zero; nil while no Sync waits
+	closeMu                     sync.RWMutex  // Held for reading while sending, so Close never closes logChan under a blocked sender
+	overflow                    OverflowConfig
+	overflowCounters            overflowCounters
 	logProcessTimeout           time.Duration
 	disablePerLogContextTimeout bool
 }
 
 // logJob represents a logging job
 type logJob struct {
-	level   core.Level
-	msg     []byte
-	fields  map[string][]byte
-	keyvals [][]byte
-	ctx     context.Context
+	processor LogProcessor // Processor the job is handed to; the AsyncLogger's if nil
+	level     core.Level
+	msg       []byte
+	fields    map[string][]byte
+	keyvals   [][]byte
+	typed     []core.Field
+	err       error
+	ctx       context.Context
 }
 
 // NewAsyncLogger creates a new AsyncLogger
@@ -86,35 +109,67 @@ func (al *AsyncLogger) worker() {
 	}()
 
 	for job := range al.logChan {
-		var ctx context.Context
-		var cancel context.CancelFunc
+		al.process(job)
+	}
+}
 
-		if al.logProcessTimeout > 0 && !al.disablePerLogContextTimeout {
-			ctx, cancel = context.WithTimeout(job.ctx, al.logProcessTimeout)
-		} else {
-			ctx = job.ctx
-		}
+// process hands a job to the processor
+func (al *AsyncLogger) process(job *logJob) {
+	defer al.done()
+
+	var ctx context.Context
+	var cancel context.CancelFunc
 
-		// Handle both keyvals and fields
-		if job.keyvals != nil {
-			al.processor.Log(ctx, job.level, job.msg, job.keyvals...)
+	if al.logProcessTimeout > 0 && !al.disablePerLogContextTimeout {
+		ctx, cancel = context.WithTimeout(job.ctx, al.logProcessTimeout)
+	} else {
+		ctx = job.ctx
+	}
+
+	// Handle typed fields, keyvals and fields
+	processor := job.processor
+	if processor == nil {
+		processor = al.processor
+	}
+	if job.typed != nil || job.err != nil {
+		if tp, ok := processor.(TypedLogProcessor); ok {
+			tp.LogFields(ctx, job.level, job.msg, job.err, job.typed)
 		} else {
-			// Convert fields to keyvals for unified interface
-			keyvals := make([][]byte, 0, len(job.fields)*2)
-			for k, v := range job.fields {
-				keyvals = append(keyvals, []byte(k), v)
+			keyvals := make([][]byte, 0, len(job.typed)*2+2)
+			if job.err != nil {
+				keyvals = append(keyvals, []byte("error"), []byte(job.err.Error()))
+			}
+			for i := range job.typed {
+				keyvals = append(keyvals, []byte(job.typed[i].Key), job.typed[i].AppendText(nil))
 			}
-			al.processor.Log(ctx, job.level, job.msg, keyvals...)
+			processor.Log(ctx, job.level, job.msg, keyvals...)
 		}
-
-		if cancel != nil {
-			cancel()
+	} else if job.keyvals != nil {
+		processor.Log(ctx, job.level, job.msg, job.keyvals...)
+	} else if mp, ok := processor.(MapLogProcessor); ok {
+		mp.LogMap(ctx, job.level, job.msg, job.fields)
+	} else {
+		// Convert fields to keyvals for unified interface
+		keyvals := make([][]byte, 0, len(job.fields)*2)
+		for k, v := range job.fields {
+			keyvals = append(keyvals, []byte(k), v)
 		}
+		processor.Log(ctx, job.level, job.msg, keyvals...)
+	}
+
+	if cancel != nil {
+		cancel()
 	}
 }
 
 // LogZero queues a zero-allocation log job
 func (al *AsyncLogger) LogZero(level core.Level, msg []byte, ctx context.Context, keyvals ...[]byte) {
+	al.LogZeroFor(al.processor, level, msg, ctx, keyvals...)
+}
+
+// LogZeroFor is LogZero for a job handed to processor instead of the processor
+// of the AsyncLogger, such as a child logger sharing the queue of its parent
+func (al *AsyncLogger) LogZeroFor(processor LogProcessor, level core.Level, msg []byte, ctx context.Context, keyvals ...[]byte) {
 	if al.closed.Load() {
 		return
 	}
@@ -130,24 +185,50 @@ func (al *AsyncLogger) LogZero(level core.Level, msg []byte, ctx context.Context
 		keyvalsCopy[i] = kvCopy
 	}
 
-	select {
-	case al.logChan <- &logJob{level: level, msg: msgCopy, keyvals: keyvalsCopy, ctx: ctx}:
-		// Successfully sent
-	default:
-		// Channel full, handle error
-		if handler := al.processor.ErrorHandler(); handler != nil {
-			handler(errors.ErrAsyncBufferFull)
-		} else if errOut := al.processor.ErrOut(); errOut != nil {
-			mu := al.processor.ErrOutMu()
-			mu.Lock()
-			_, _ = al.processor.ErrOut().Write([]byte("async buffer full\n"))
-			mu.Unlock()
+	al.send(&logJob{processor: processor, level: level, msg: msgCopy, keyvals: keyvalsCopy, ctx: ctx})
+}
+
+// LogFields queues a log job with typed fields
+func (al *AsyncLogger) LogFields(level core.Level, msg []byte, ctx context.Context, fields ...core.Field) {
+	al.LogFieldsWithError(level, msg, ctx, nil, fields...)
+}
+
+// LogFieldsWithError queues a log job with an attached error and typed fields
+func (al *AsyncLogger) LogFieldsWithError(level core.Level, msg []byte, ctx context.Context, err error, fields ...core.Field) {
+	al.LogFieldsFor(al.processor, level, msg, ctx, err, fields...)
+}
+
+// LogFieldsFor is LogFieldsWithError for a job handed to processor instead of
+// the processor of the AsyncLogger
+func (al *AsyncLogger) LogFieldsFor(processor LogProcessor, level core.Level, msg []byte, ctx context.Context, err error, fields ...core.Field) {
+	if al.closed.Load() {
+		return
+	}
+
+	msgCopy := make([]byte, len(msg))
+	copy(msgCopy, msg)
+
+	// Copy fields; byte slices may be reused by the caller once this returns
+	fieldsCopy := make([]core.Field, len(fields))
+	copy(fieldsCopy, fields)
+	for i := range fieldsCopy {
+		if b, ok := fieldsCopy[i].Iface.([]byte); ok && fieldsCopy[i].Type == core.BytesField {
+			bCopy := make([]byte, len(b))
+			copy(bCopy, b)
+			fieldsCopy[i].Iface = bCopy
 		}
 	}
+
+	al.send(&logJob{processor: processor, level: level, msg: msgCopy, typed: fieldsCopy, err: err, ctx: ctx})
 }
 
 // Log queues a log job for asynchronous processing
 func (al *AsyncLogger) Log(level core.Level, msg []byte, fields map[string][]byte, ctx context.Context) {
+	al.LogFor(al.processor, level, msg, fields, ctx)
+}
+
+// LogFor is Log for a job handed to processor instead of the processor of the AsyncLogger
+func (al *AsyncLogger) LogFor(processor LogProcessor, level core.Level, msg []byte, fields map[string][]byte, ctx context.Context) {
 	// Don't try to log if logger is closed
 	if al.closed.Load() {
 		return
@@ -156,25 +237,105 @@ func (al *AsyncLogger) Log(level core.Level, msg []byte, fields map[string][]byt
 	msgCopy := make([]byte, len(msg))
 	copy(msgCopy, msg)
 
+	al.send(&logJob{processor: processor, level: level, msg: msgCopy, fields: fields, ctx: ctx})
+}
+
+// SetOverflow sets the policy applied when the queue is full. It must be called
+// before the logger is used.
+func (al *AsyncLogger) SetOverflow(conf OverflowConfig) {
+	al.overflow = conf
+}
+
+// OverflowStats returns the outcomes of jobs that found the queue full
+func (al *AsyncLogger) OverflowStats() OverflowStats {
+	return al.overflowCounters.stats()
+}
+
+// send queues a job, applying the overflow policy when the queue is full
+func (al *AsyncLogger) send(job *logJob) {
+	al.closeMu.RLock()
+	defer al.closeMu.RUnlock()
+	if al.closed.Load() {
+		return
+	}
+
+	al.pending.Add(1)
+	queued, lost := enqueue(al.logChan, job, job.level, &al.overflow, &al.overflowCounters, al.evict)
+	if !queued {
+		al.done()
+	}
+	if lost == 0 {
+		return
+	}
+
+	al.dropped.Add(lost)
+	if handler := al.processor.ErrorHandler(); handler != nil {
+		handler(errors.ErrAsyncBufferFull)
+	} else if errOut := al.processor.ErrOut(); errOut != nil {
+		mu := al.processor.ErrOutMu()
+		mu.Lock()
+		_, _ = errOut.Write([]byte("async buffer full\n"))
+		mu.Unlock()
+	}
+}
+
+// evict accounts for a queued job removed by DropOldest
+func (al *AsyncLogger) evict(*logJob) {
+	al.done()
+}
+
+// done accounts for a finished or discarded job and wakes Sync once
+// nothing is pending
+func (al *AsyncLogger) done() {
+	if al.pending.Add(-1) != 0 {
+		return
+	}
+	al.idleMu.Lock()
+	if al.idle != nil {
+		close(al.idle)
+		al.idle = nil
+	}
+	al.idleMu.Unlock()
+}
+
+// QueueDepth returns the number of jobs waiting to be processed
+func (al *AsyncLogger) QueueDepth() int {
+	return len(al.logChan)
+}
+
+// Dropped returns the number of jobs dropped because the queue was full
+func (al *AsyncLogger) Dropped() int64 {
+	return al.dropped.Load()
+}
+
+// Sync waits until every job queued so far has been processed.
+// It returns the context error if ctx is done first.
+func (al *AsyncLogger) Sync(ctx context.Context) error {
+	al.idleMu.Lock()
+	if al.pending.Load() == 0 {
+		al.idleMu.Unlock()
+		return nil
+	}
+	if al.idle == nil {
+		al.idle = make(chan struct{})
+	}
+	idle := al.idle
+	al.idleMu.Unlock()
+
 	select {
-	case al.logChan <- &logJob{level: level, msg: msgCopy, fields: fields, ctx: ctx}:
-		// Successfully sent
-	default:
-		// Channel full, handle error
-		if handler := al.processor.ErrorHandler(); handler != nil {
-			handler(errors.ErrAsyncBufferFull)
-		} else if errOut := al.processor.ErrOut(); errOut != nil {
-			mu := al.processor.ErrOutMu()
-			mu.Lock()
-			errOut.Write([]byte("Warning: Async log channel full, dropping log.\n"))
-			mu.Unlock()
-		}
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-idle:
+		return nil
 	}
 }
 
 // Close closes the async logger
 func (al *AsyncLogger) Close() {
-	if al.closed.CompareAndSwap(false, true) {
+	al.closeMu.Lock()
+	closing := al.closed.CompareAndSwap(false, true)
+	al.closeMu.Unlock()
+	if closing {
 		close(al.logChan)
 		al.wg.Wait()
 	}
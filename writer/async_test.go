@@ -434,3 +434,185 @@ func TestAsyncLoggerClosedBehavior(t *testing.T) {
 	// at
 	// but it should not cause a panic
 }
+
+// mockTypedLogProcessor implements TypedLogProcessor on top of mockLogProcessor
+type mockTypedLogProcessor struct {
+	mockLogProcessor
+	typed [][]core.Field
+	errs  []error
+}
+
+func (m *mockTypedLogProcessor) LogFields(ctx context.Context, level core.Level, msg []byte, err error, fields []core.Field) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.typed = append(m.typed, fields)
+	m.errs = append(m.errs, err)
+}
+
+// TestAsyncLoggerLogFields tests that typed fields reach a TypedLogProcessor intact
+func TestAsyncLoggerLogFields(t *testing.T) {
+	processor := &mockTypedLogProcessor{}
+	asyncLogger := NewAsyncLogger(processor, 1, 10, 0, true)
+
+	raw := []byte("abc")
+	asyncLogger.LogFields(core.INFO, []byte("typed"), context.Background(),
+		core.Field{Key: "n", Type: core.Int64Field, Int: 5},
+		core.Field{Key: "b", Type: core.BytesField, Iface: raw})
+	raw[0] = 'x' // The queued job must hold its own copy
+	asyncLogger.Close()
+
+	processor.mu.Lock()
+	defer processor.mu.Unlock()
+
+	if len(processor.typed) != 1 || len(processor.typed[0]) != 2 {
+		t.Fatalf("Expected one job with 2 typed fields, got %+v", processor.typed)
+	}
+	if f := processor.typed[0][0]; f.Key != "n" || f.Int != 5 {
+		t.Errorf("Unexpected first field %+v", f)
+	}
+	if b := processor.typed[0][1].Iface.([]byte); string(b) != "abc" {
+		t.Errorf("Expected bytes field to be copied, got %q", b)
+	}
+}
+
+// TestAsyncLoggerLogFieldsFallback tests that typed fields become keyvals for plain processors
+func TestAsyncLoggerLogFieldsFallback(t *testing.T) {
+	processor := &mockLogProcessor{}
+	asyncLogger := NewAsyncLogger(processor, 1, 10, 0, true)
+
+	asyncLogger.LogFields(core.INFO, []byte("typed"), context.Background(),
+		core.Field{Key: "ok", Type: core.BoolField, Int: 1})
+	asyncLogger.Close()
+
+	processor.mu.Lock()
+	defer processor.mu.Unlock()
+
+	if len(processor.loggedEntries) != 1 {
+		t.Fatalf("Expected 1 logged entry, got %d", len(processor.loggedEntries))
+	}
+	kv := processor.loggedEntries[0].keyvals
+	if len(kv) != 2 || string(kv[0]) != "ok" || string(kv[1]) != "true" {
+		t.Errorf("Expected keyvals [ok true], got %q", kv)
+	}
+}
+
+// TestAsyncLoggerLogFieldsWithError tests that errors reach both typed and plain processors
+func TestAsyncLoggerLogFieldsWithError(t *testing.T) {
+	boom := fmt.Errorf("boom")
+
+	typed := &mockTypedLogProcessor{}
+	asyncLogger := NewAsyncLogger(typed, 1, 10, 0, true)
+	asyncLogger.LogFieldsWithError(core.ERROR, []byte("failed"), context.Background(), boom)
+	asyncLogger.Close()
+
+	typed.mu.Lock()
+	if len(typed.errs) != 1 || typed.errs[0] != boom {
+		t.Errorf("Expected error to reach the typed processor, got %v", typed.errs)
+	}
+	typed.mu.Unlock()
+
+	plain := &mockLogProcessor{}
+	asyncLogger = NewAsyncLogger(plain, 1, 10, 0, true)
+	asyncLogger.LogFieldsWithError(core.ERROR, []byte("failed"), context.Background(), boom,
+		core.Field{Key: "n", Type: core.Int64Field, Int: 1})
+	asyncLogger.Close()
+
+	plain.mu.Lock()
+	defer plain.mu.Unlock()
+	if len(plain.loggedEntries) != 1 {
+		t.Fatalf("Expected 1 logged entry, got %d", len(plain.loggedEntries))
+	}
+	kv := plain.loggedEntries[0].keyvals
+	if len(kv) != 4 || string(kv[0]) != "error" || string(kv[1]) != "boom" || string(kv[2]) != "n" {
+		t.Errorf("Expected keyvals [error boom n 1], got %q", kv)
+	}
+}
+
+// slowLogProcessor delays every job
+type slowLogProcessor struct {
+	mockLogProcessor
+	delay time.Duration
+}
+
+func (m *slowLogProcessor) Log(ctx context.Context, level core.Level, msg []byte, keyvals ...[]byte) {
+	time.Sleep(m.delay)
+	m.mockLogProcessor.Log(ctx, level, msg, keyvals...)
+}
+
+// TestAsyncLoggerSync tests waiting for queued jobs and giving up at the deadline
+func TestAsyncLoggerSync(t *testing.T) {
+	processor := &slowLogProcessor{delay: 20 * time.Millisecond}
+	asyncLogger := NewAsyncLogger(processor, 1, 10, 0, true)
+	defer asyncLogger.Close()
+
+	asyncLogger.LogZero(core.INFO, []byte("first"), context.Background())
+	asyncLogger.LogZero(core.INFO, []byte("second"), context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if err := asyncLogger.Sync(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	if err := asyncLogger.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync returned error: %v", err)
+	}
+	processor.mu.Lock()
+	defer processor.mu.Unlock()
+	if len(processor.loggedEntries) != 2 {
+		t.Errorf("Expected 2 processed jobs after Sync, got %d", len(processor.loggedEntries))
+	}
+}
+
+// TestAsyncLoggerSyncWaiters tests that concurrent Sync calls all return
+// once the queue drains, and that Sync keeps working after a drain
+func TestAsyncLoggerSyncWaiters(t *testing.T) {
+	processor := &slowLogProcessor{delay: 10 * time.Millisecond}
+	asyncLogger := NewAsyncLogger(processor, 2, 10, 0, true)
+	defer asyncLogger.Close()
+
+	for round := 0; round < 2; round++ {
+		for i := 0; i < 4; i++ {
+			asyncLogger.LogZero(core.INFO, []byte("job"), context.Background())
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		errs := make(chan error, 3)
+		for i := 0; i < cap(errs); i++ {
+			go func() { errs <- asyncLogger.Sync(ctx) }()
+		}
+		for i := 0; i < cap(errs); i++ {
+			if err := <-errs; err != nil {
+				t.Errorf("Round %d: Sync returned error: %v", round, err)
+			}
+		}
+		cancel()
+	}
+	processor.mu.Lock()
+	defer processor.mu.Unlock()
+	if len(processor.loggedEntries) != 8 {
+		t.Errorf("Expected 8 processed jobs, got %d", len(processor.loggedEntries))
+	}
+}
+
+// TestAsyncLoggerOverflowDropOldest tests that a full queue evicts its oldest job
+func TestAsyncLoggerOverflowDropOldest(t *testing.T) {
+	processor := &slowLogProcessor{delay: 20 * time.Millisecond}
+	asyncLogger := NewAsyncLogger(processor, 1, 1, 0, true)
+	asyncLogger.SetOverflow(OverflowConfig{Policy: DropOldest})
+
+	for i := 0; i < 5; i++ {
+		asyncLogger.LogZero(core.INFO, []byte(fmt.Sprintf("msg%d", i)), context.Background())
+	}
+	asyncLogger.Close()
+
+	stats := asyncLogger.OverflowStats()
+	if stats.DroppedOldest == 0 || asyncLogger.Dropped() != stats.Dropped() {
+		t.Errorf("Expected evictions to be counted, got %+v dropped=%d", stats, asyncLogger.Dropped())
+	}
+	processor.mu.Lock()
+	defer processor.mu.Unlock()
+	last := processor.loggedEntries[len(processor.loggedEntries)-1]
+	if string(last.msg) != "msg4" {
+		t.Errorf("Expected the newest job to survive, got %s", last.msg)
+	}
+}
@@ -1,11 +1,15 @@
 package writer
 
 import (
+	"context"
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/errors"
 	"github.com/Lunar-Chipter/mire/util"
 	"io"
 	"os"
 	"sync"
 	"sync/atomic"
+	"syscall"
 	"time"
 )
 
@@ -33,6 +37,7 @@ type Buffered struct {
 	bufferSize    int
 	flushInterval time.Duration
 	done          chan struct{}
+	flushReq      chan chan struct{} // Sync requests, answered once everything queued before them is written
 	wg            sync.WaitGroup
 	mu            sync.Mutex
 	droppedLogs   int64
@@ -43,6 +48,11 @@ type Buffered struct {
 	batchTimeout  time.Duration
 	errorHandler  func(error)
 	closed        bool
+	closeMu       sync.RWMutex // Held for reading while sending, so the worker never closes buffer under a blocked sender
+	overflow      OverflowConfig
+	counters      overflowCounters
+	spill         *SpillQueue // Optional on-disk queue used when the buffer is full or the writer fails
+	spillMu       sync.Mutex  // Orders writes between the buffer and the spill queue
 }
 
 // NewBuffered creates a new Buffered
@@ -53,6 +63,7 @@ func NewBuffered(writer io.Writer, bufferSize int, flushInterval time.Duration,
 		bufferSize:    bufferSize,
 		flushInterval: flushInterval,
 		done:          make(chan struct{}),
+		flushReq:      make(chan chan struct{}),
 		lastFlush:     time.Now(),
 		batchSize:     batchSize,
 		batchTimeout:  batchTimeout,
@@ -71,8 +82,18 @@ func NewBuffered(writer io.Writer, bufferSize int, flushInterval time.Duration,
 	return bw
 }
 
-// Write writes data to the buffer. If the buffer is full, the log is dropped.
+// Write writes data to the buffer, applying the overflow policy when the buffer is full.
+// Data of unknown level is never dropped by DropBelowLevel.
 func (bw *Buffered) Write(p []byte) (n int, err error) {
+	return bw.WriteLevel(core.PANIC, p)
+}
+
+// WriteLevel writes a log entry of the given level to the buffer, applying the
+// overflow policy when the buffer is full
+func (bw *Buffered) WriteLevel(level core.Level, p []byte) (n int, err error) {
+	bw.closeMu.RLock()
+	defer bw.closeMu.RUnlock()
+
 	// Check if the writer is closed to avoid sending to closed channel
 	bw.mu.Lock()
 	if bw.closed {
@@ -92,18 +113,69 @@ func (bw *Buffered) Write(p []byte) (n int, err error) {
 	buf = buf[:len(p)]
 	copy(buf, p)
 
-	select {
-	case bw.buffer <- buf:
-		// The buffer was successfully queued.
+	if bw.spill != nil && bw.spillWrite(buf) {
 		return len(p), nil
-	default:
-		// The buffer channel is full. Drop the log to prevent blocking.
-		atomic.AddInt64(&bw.droppedLogs, 1)
+	}
+
+	queued, lost := enqueue(bw.buffer, buf, level, &bw.overflow, &bw.counters, bw.release)
+	if !queued {
 		// We must return the buffer to the pool since it was not sent.
-		//nolint:staticcheck
-		bw.bufferPool.Put(buf[:0])
-		return len(p), nil
+		bw.release(buf)
+	}
+	if lost > 0 {
+		atomic.AddInt64(&bw.droppedLogs, lost)
+	}
+	return len(p), nil
+}
+
+// release returns a buffer that will not be written to the pool
+func (bw *Buffered) release(buf []byte) {
+	//nolint:staticcheck
+	bw.bufferPool.Put(buf[:0])
+}
+
+// spillWrite queues buf in memory while nothing is spilled and appends it to the
+// spill queue otherwise, so that entries keep their order. It returns false if
+// the spill queue cannot take buf, leaving it to the overflow policy.
+func (bw *Buffered) spillWrite(buf []byte) bool {
+	bw.spillMu.Lock()
+	defer bw.spillMu.Unlock()
+
+	if bw.spill.Empty() {
+		select {
+		case bw.buffer <- buf:
+			return true
+		default:
+		}
 	}
+	if err := bw.spill.Append(buf); err != nil {
+		if err != errors.ErrSpillFull {
+			bw.reportError("failed to spill log entry", err)
+		}
+		return false
+	}
+	bw.counters.spilled.Add(1)
+	bw.release(buf)
+	return true
+}
+
+// SetSpill sets the on-disk queue that takes entries when the buffer is full or
+// the underlying writer fails. Spilled entries are replayed in order once the
+// writer accepts data again. It must be called before the writer is used, and
+// the writer closes q when it is closed.
+func (bw *Buffered) SetSpill(q *SpillQueue) {
+	bw.spill = q
+}
+
+// SetOverflow sets the policy applied when the buffer is full. It must be called
+// before the writer is used.
+func (bw *Buffered) SetOverflow(conf OverflowConfig) {
+	bw.overflow = conf
+}
+
+// OverflowStats returns the outcomes of writes that found the buffer full
+func (bw *Buffered) OverflowStats() OverflowStats {
+	return bw.counters.stats()
 }
 
 // flushWorker is the goroutine that flushes buffered logs
@@ -158,9 +230,16 @@ func (bw *Buffered) flushWorker() {
 			if len(batch) > 0 {
 				bw.flushBatch(batch)
 			}
+			bw.replaySpill()
 			return // EXIT the worker goroutine
 
 		case <-ticker.C:
+			if bw.spill != nil && !bw.spill.Empty() {
+				// Queued entries are older than spilled ones and must be written first
+				for n := len(bw.buffer); n > 0; n-- {
+					batch = append(batch, <-bw.buffer)
+				}
+			}
 			if len(batch) > 0 {
 				bw.flushBatch(batch)
 				batch = batch[:0]
@@ -172,6 +251,7 @@ func (bw *Buffered) flushWorker() {
 					}
 				}
 			}
+			bw.replaySpill()
 
 		case data := <-bw.buffer:
 			if len(batch) == 0 && batchTimer != nil {
@@ -191,6 +271,22 @@ func (bw *Buffered) flushWorker() {
 				}
 			}
 
+		case reply := <-bw.flushReq:
+			// Write the current batch and everything queued before the request
+			for n := len(bw.buffer); n > 0; n-- {
+				batch = append(batch, <-bw.buffer)
+			}
+			bw.flushBatch(batch)
+			batch = batch[:0]
+			if batchTimer != nil && !batchTimer.Stop() {
+				select {
+				case <-batchTimer.C:
+				default:
+				}
+			}
+			bw.replaySpill()
+			close(reply)
+
 		case <-batchTimeoutChan:
 			if len(batch) > 0 {
 				bw.flushBatch(batch)
@@ -222,45 +318,161 @@ func (bw *Buffered) flushBatch(batch [][]byte) {
 	}
 
 	if _, err := bw.writer.Write(combined); err != nil {
-		if bw.errorHandler != nil {
-			bw.errorHandler(&wrappedError{
-				msg:   "buffered writer error",
-				cause: err,
-			})
-		} else {
-			// Write error message manually without fmt
-			_, _ = os.Stderr.Write([]byte("Error writing from buffered writer: "))
-			_, _ = os.Stderr.Write(util.StringToBytes(err.Error()))
-			_, _ = os.Stderr.Write([]byte("\n"))
+		if bw.spill != nil && bw.spillBatch(combined, len(batch)) {
+			return
+		}
+		bw.reportError("buffered writer error", err)
+	}
+}
+
+// spillBatch moves a batch the writer rejected to the spill queue, together with
+// the entries still in the buffer, which are newer than the batch but older than
+// anything already spilled. entries is the number of entries of the batch. It
+// returns false if the batch could not be spilled; its entries and the buffered
+// ones are then counted as dropped.
+func (bw *Buffered) spillBatch(combined []byte, entries int) bool {
+	bw.spillMu.Lock()
+	defer bw.spillMu.Unlock()
+
+	// Writers see a non-empty spill queue from now on and stop using the buffer
+	n := len(bw.buffer)
+	for i := 0; i < n; i++ {
+		data := <-bw.buffer
+		combined = append(combined, data...)
+		bw.release(data)
+	}
+
+	var err error
+	if bw.spill.Empty() {
+		err = bw.spill.Append(combined)
+	} else {
+		err = bw.spill.Prepend(combined)
+	}
+	if err != nil {
+		if err != errors.ErrSpillFull {
+			bw.reportError("failed to spill log batch", err)
+		}
+		bw.counters.droppedNewest.Add(int64(entries + n))
+		atomic.AddInt64(&bw.droppedLogs, int64(entries+n))
+		return false
+	}
+	bw.counters.spilled.Add(1)
+	return true
+}
+
+// replaySpill writes spilled entries to the writer in order. It stops at the
+// first write error and leaves the rest for the next attempt.
+func (bw *Buffered) replaySpill() {
+	if bw.spill == nil {
+		return
+	}
+	for {
+		rec, err := bw.spill.Peek()
+		if err != nil {
+			if err != io.EOF {
+				bw.reportError("failed to read spill queue", err)
+			}
+			return
+		}
+
+		bw.mu.Lock()
+		_, err = bw.writer.Write(rec)
+		bw.mu.Unlock()
+		if err != nil {
+			bw.reportError("buffered writer error", err)
+			return
 		}
+		bw.spill.Commit()
 	}
 }
 
+// reportError passes err to the error handler, or writes it to stderr without one
+func (bw *Buffered) reportError(msg string, err error) {
+	if bw.errorHandler != nil {
+		bw.errorHandler(&wrappedError{
+			msg:   msg,
+			cause: err,
+		})
+		return
+	}
+	// Write error message manually without fmt
+	_, _ = os.Stderr.Write([]byte("Error writing from buffered writer: "))
+	_, _ = os.Stderr.Write(util.StringToBytes(err.Error()))
+	_, _ = os.Stderr.Write([]byte("\n"))
+}
+
 // Stats returns statistics about the buffered writer
 func (bw *Buffered) Stats() map[string]interface{} {
-	return map[string]interface{}{
+	stats := map[string]interface{}{
 		"buffer_size":   bw.bufferSize,
 		"current_queue": len(bw.buffer),
 		"dropped_logs":  atomic.LoadInt64(&bw.droppedLogs),
 		"total_logs":    atomic.LoadInt64(&bw.totalLogs),
 		"last_flush":    bw.lastFlush,
 	}
+	if bw.spill != nil {
+		stats["spilled_bytes"] = bw.spill.Size()
+	}
+	return stats
+}
+
+// DroppedLogs returns the number of writes dropped because the buffer was full
+func (bw *Buffered) DroppedLogs() int64 {
+	return atomic.LoadInt64(&bw.droppedLogs)
+}
+
+// Sync writes all buffered logs to the underlying writer and syncs it to stable
+// storage when it supports Sync. It returns the context error if ctx is done first.
+func (bw *Buffered) Sync(ctx context.Context) error {
+	bw.mu.Lock()
+	closed := bw.closed
+	bw.mu.Unlock()
+	if closed {
+		return nil
+	}
+
+	reply := make(chan struct{})
+	select {
+	case bw.flushReq <- reply:
+	case <-bw.done:
+		return nil // Close drains the buffer
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-reply:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if bw.spill != nil {
+		// Entries the writer did not take must at least be safe on disk
+		if err := bw.spill.Sync(); err != nil {
+			return err
+		}
+	}
+	return SyncWriter(bw.writer)
 }
 
 // Close closes the buffered writer, ensuring all logs are flushed.
 func (bw *Buffered) Close() error {
+	// Wait for writers blocked on a full buffer; the worker is still draining it
+	bw.closeMu.Lock()
+
 	// Use a mutex to make sure Close is thread-safe and only done once
 	bw.mu.Lock()
 
 	// Check if already closed using our new closed flag
 	if bw.closed {
 		bw.mu.Unlock() // Release the mutex before returning
-		return nil     // Already closed
+		bw.closeMu.Unlock()
+		return nil // Already closed
 	}
 
 	// Mark as closed
 	bw.closed = true
 	bw.mu.Unlock()
+	bw.closeMu.Unlock()
 
 	// Close the done channel to signal the worker
 	close(bw.done)
@@ -269,6 +481,13 @@ func (bw *Buffered) Close() error {
 	// The worker will close the buffer channel after processing remaining items.
 	bw.wg.Wait()
 
+	if bw.spill != nil {
+		// Entries not yet replayed stay on disk for the next process
+		if err := bw.spill.Close(); err != nil {
+			bw.reportError("failed to close spill queue", err)
+		}
+	}
+
 	// Only close the underlying writer if it's not os.Stdout or os.Stderr
 	// because these objects should not be closed by the application.
 	if bw.writer != os.Stdout && bw.writer != os.Stderr {
@@ -278,3 +497,25 @@ func (bw *Buffered) Close() error {
 	}
 	return nil
 }
+
+// SyncWriter commits the data written to w to stable storage when w supports
+// Sync, as *os.File and Rotator do. Files that cannot be synced, such as
+// terminals, pipes and /dev/null, are not an error.
+func SyncWriter(w io.Writer) error {
+	syncer, ok := w.(interface{ Sync() error })
+	if !ok {
+		return nil
+	}
+	err := syncer.Sync()
+	if _, isFile := w.(*os.File); isFile && unsyncable(err) {
+		return nil
+	}
+	return err
+}
+
+// unsyncable reports whether err is the error of syncing a file that does
+// not support it
+func unsyncable(err error) bool {
+	pathErr, ok := err.(*os.PathError)
+	return ok && (pathErr.Err == syscall.EINVAL || pathErr.Err == syscall.ENOTSUP)
+}
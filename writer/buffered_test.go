@@ -2,11 +2,14 @@ package writer
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/Lunar-Chipter/mire/core"
 )
 
 // mockWriteError implements io.Writer that returns an error on write
@@ -53,7 +56,7 @@ func TestNewBuffered(t *testing.T) {
 	if bufferedWriter == nil {
 		t.Fatal("NewBuffered returned nil")
 	}
-	defer func() { _, _ = bufferedWriter.Close() }()
+	defer func() { _ = bufferedWriter.Close() }()
 
 	// Check initial state
 	if bufferedWriter.writer != &output {
@@ -112,7 +115,7 @@ func TestBufferedFullBuffer(t *testing.T) {
 	if bufferedWriter == nil {
 		t.Fatal("NewBuffered returned nil")
 	}
-	defer func() { _, _ = bufferedWriter.Close() }()
+	defer func() { _ = bufferedWriter.Close() }()
 
 	// Write data to fill up the internal channel
 	for i := 0; i < 10; i++ {
@@ -155,7 +158,7 @@ func TestBufferedWriteError(t *testing.T) {
 	if bufferedWriter == nil {
 		t.Fatal("NewBuffered returned nil")
 	}
-	defer func() { _, _ = bufferedWriter.Close() }()
+	defer func() { _ = bufferedWriter.Close() }()
 
 	// Write some data
 	_, err := bufferedWriter.Write([]byte("test"))
@@ -222,7 +225,7 @@ func TestBufferedWithBatches(t *testing.T) {
 	if bufferedWriter == nil {
 		t.Fatal("NewBuffered returned nil")
 	}
-	defer func() { _, _ = bufferedWriter.Close() }()
+	defer func() { _ = bufferedWriter.Close() }()
 
 	// Write multiple small chunks that should be batched together
 	for i := 0; i < 5; i++ {
@@ -260,7 +263,7 @@ func TestBufferedWithTimeout(t *testing.T) {
 	if bufferedWriter == nil {
 		t.Fatal("NewBuffered returned nil")
 	}
-	defer func() { _, _ = bufferedWriter.Close() }()
+	defer func() { _ = bufferedWriter.Close() }()
 
 	// Write one chunk - it should be flushed by timeout since batch size won't be reached
 	_, err := bufferedWriter.Write([]byte("single chunk\n"))
@@ -287,7 +290,7 @@ func TestBufferedStats(t *testing.T) {
 	if bufferedWriter == nil {
 		t.Fatal("NewBuffered returned nil")
 	}
-	defer func() { _, _ = bufferedWriter.Close() }()
+	defer func() { _ = bufferedWriter.Close() }()
 
 	// Write some data to populate stats
 	for i := 0; i < 3; i++ {
@@ -339,7 +342,7 @@ func TestBufferedConcurrent(t *testing.T) {
 	if bufferedWriter == nil {
 		t.Fatal("NewBuffered returned nil")
 	}
-	defer func() { _, _ = bufferedWriter.Close() }()
+	defer func() { _ = bufferedWriter.Close() }()
 
 	// Run multiple goroutines that write concurrently
 	const numGoroutines = 5
@@ -478,3 +481,176 @@ func TestBufferedWithBytesBuffer(t *testing.T) {
 		t.Error("Not all lines were written to buffer")
 	}
 }
+
+// syncCounter is a writer that counts Sync calls
+type syncCounter struct {
+	mockWriteCounter
+	syncs int
+}
+
+func (s *syncCounter) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.syncs++
+	return nil
+}
+
+// TestBufferedSync tests that Sync writes pending data and syncs the underlying writer
+func TestBufferedSync(t *testing.T) {
+	out := &syncCounter{}
+	bufferedWriter := NewBuffered(out, 10, time.Hour, nil, 100, 0)
+	defer func() { _ = bufferedWriter.Close() }()
+
+	_, _ = bufferedWriter.Write([]byte("first\n"))
+	_, _ = bufferedWriter.Write([]byte("second\n"))
+
+	if err := bufferedWriter.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync returned error: %v", err)
+	}
+	if got := string(out.GetData()); got != "first\nsecond\n" {
+		t.Errorf("Expected both writes after Sync, got %q", got)
+	}
+	out.mu.Lock()
+	if out.syncs != 1 {
+		t.Errorf("Expected underlying writer to be synced once, got %d", out.syncs)
+	}
+	out.mu.Unlock()
+
+	_ = bufferedWriter.Close()
+	if err := bufferedWriter.Sync(context.Background()); err != nil {
+		t.Errorf("Sync after Close returned error: %v", err)
+	}
+}
+
+// TestBufferedOverflowDropBelowLevel tests that only low level writes are dropped
+func TestBufferedOverflowDropBelowLevel(t *testing.T) {
+	out := &syncCounter{}
+	bufferedWriter := NewBuffered(out, 1, time.Hour, nil, 100, 0)
+	bufferedWriter.SetOverflow(OverflowConfig{Policy: DropBelowLevel, MinLevel: core.WARN})
+
+	for i := 0; i < 50; i++ {
+		_, _ = bufferedWriter.WriteLevel(core.INFO, []byte("i"))
+		_, _ = bufferedWriter.WriteLevel(core.ERROR, []byte("E"))
+	}
+	_ = bufferedWriter.Close()
+
+	if n := strings.Count(string(out.GetData()), "E"); n != 50 {
+		t.Errorf("Expected all 50 ERROR writes, got %d", n)
+	}
+	stats := bufferedWriter.OverflowStats()
+	if stats.DroppedNewest != 0 || stats.DroppedBelowLevel != bufferedWriter.DroppedLogs() {
+		t.Errorf("Unexpected stats %+v dropped=%d", stats, bufferedWriter.DroppedLogs())
+	}
+}
+
+// flakyWriter fails every write until it is healed
+type flakyWriter struct {
+	mu     sync.Mutex
+	down   bool
+	output bytes.Buffer
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.down {
+		return 0, io.ErrClosedPipe
+	}
+	return w.output.Write(p)
+}
+
+func (w *flakyWriter) setDown(down bool) {
+	w.mu.Lock()
+	w.down = down
+	w.mu.Unlock()
+}
+
+func (w *flakyWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.output.String()
+}
+
+// TestBufferedSpill tests that entries rejected by the writer are spilled and replayed in order
+func TestBufferedSpill(t *testing.T) {
+	dir := t.TempDir()
+	spill, err := NewSpillQueue(SpillConfig{Dir: dir})
+	if err != nil {
+		t.Fatalf("NewSpillQueue failed: %v", err)
+	}
+
+	sink := &flakyWriter{down: true}
+	bw := NewBuffered(sink, 2, time.Hour, func(error) {}, 1, 0)
+	bw.SetSpill(spill)
+
+	for _, s := range []string{"1\n", "2\n", "3\n", "4\n", "5\n"} {
+		_, _ = bw.Write([]byte(s))
+	}
+	if err := bw.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if sink.String() != "" || spill.Empty() {
+		t.Fatalf("Expected entries to be spilled while the writer is down, got %q", sink.String())
+	}
+	if bw.OverflowStats().Dropped() != 0 || bw.OverflowStats().Spilled == 0 {
+		t.Errorf("Expected spills and no drops, got %+v", bw.OverflowStats())
+	}
+
+	sink.setDown(false)
+	_, _ = bw.Write([]byte("6\n"))
+	if err := bw.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if got := sink.String(); got != "1\n2\n3\n4\n5\n6\n" {
+		t.Errorf("Expected entries replayed in order, got %q", got)
+	}
+	_ = bw.Close()
+}
+
+// TestBufferedSpillFull tests that batches the full spill queue rejects are counted as dropped
+func TestBufferedSpillFull(t *testing.T) {
+	spill, err := NewSpillQueue(SpillConfig{Dir: t.TempDir(), MaxBytes: 1})
+	if err != nil {
+		t.Fatalf("NewSpillQueue failed: %v", err)
+	}
+	sink := &flakyWriter{down: true}
+	bw := NewBuffered(sink, 8, time.Hour, func(error) {}, 1, 0)
+	bw.SetSpill(spill)
+
+	for _, s := range []string{"1\n", "2\n", "3\n"} {
+		_, _ = bw.Write([]byte(s))
+	}
+	_ = bw.Sync(context.Background())
+	if got := bw.DroppedLogs(); got != 3 {
+		t.Errorf("Expected 3 dropped entries, got %d", got)
+	}
+	if stats := bw.OverflowStats(); stats.DroppedNewest != 3 || stats.Spilled != 0 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	_ = bw.Close()
+}
+
+// TestBufferedSpillRestart tests that entries spilled before Close are replayed by the next writer
+func TestBufferedSpillRestart(t *testing.T) {
+	dir := t.TempDir()
+	spill, _ := NewSpillQueue(SpillConfig{Dir: dir})
+	sink := &flakyWriter{down: true}
+	bw := NewBuffered(sink, 2, time.Hour, func(error) {}, 1, 0)
+	bw.SetSpill(spill)
+	_, _ = bw.Write([]byte("before restart\n"))
+	_ = bw.Close()
+
+	spill, err := NewSpillQueue(SpillConfig{Dir: dir})
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	healthy := &flakyWriter{}
+	bw = NewBuffered(healthy, 2, time.Hour, func(error) {}, 1, 0)
+	bw.SetSpill(spill)
+	_, _ = bw.Write([]byte("after restart\n"))
+	_ = bw.Close()
+
+	if got := healthy.String(); got != "before restart\nafter restart\n" {
+		t.Errorf("Expected spilled entry first, got %q", got)
+	}
+}
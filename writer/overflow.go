@@ -0,0 +1,146 @@
+package writer
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/errors"
+)
+
+// OverflowPolicy selects what a full queue does with a new entry
+type OverflowPolicy int
+
+const (
+	// DropNewest drops the entry that does not fit. This is the default.
+	DropNewest OverflowPolicy = iota
+	// DropOldest evicts the oldest queued entry to make room for the new one
+	DropOldest
+	// Block waits until there is room in the queue
+	Block
+	// BlockWithTimeout waits up to OverflowConfig.Timeout, then drops the entry
+	BlockWithTimeout
+	// DropBelowLevel drops entries below OverflowConfig.MinLevel and blocks for the rest
+	DropBelowLevel
+)
+
+// overflowPolicyNames holds the names returned by OverflowPolicy.String
+var overflowPolicyNames = [...]string{"drop-newest", "drop-oldest", "block", "block-with-timeout", "drop-below-level"}
+
+// String returns the policy name, for example "drop-oldest"
+func (p OverflowPolicy) String() string {
+	if p >= 0 && int(p) < len(overflowPolicyNames) {
+		return overflowPolicyNames[p]
+	}
+	return "unknown"
+}
+
+// ParseOverflowPolicy parses a policy name as returned by OverflowPolicy.String
+func ParseOverflowPolicy(name string) (OverflowPolicy, error) {
+	for i, n := range overflowPolicyNames {
+		if n == name {
+			return OverflowPolicy(i), nil
+		}
+	}
+	return DropNewest, errors.ErrInvalidOverflowPolicy
+}
+
+// OverflowConfig configures how a full queue is handled
+type OverflowConfig struct {
+	Policy   OverflowPolicy // What to do when the queue is full
+	Timeout  time.Duration  // How long BlockWithTimeout waits before dropping
+	MinLevel core.Level     // Lowest level DropBelowLevel never drops (e.g. core.WARN)
+}
+
+// OverflowStats counts the outcomes of writes that found the queue full
+type OverflowStats struct {
+	Blocked           int64 // Entries that waited for room and were queued
+	TimedOut          int64 // Entries dropped after waiting OverflowConfig.Timeout
+	DroppedNewest     int64 // Entries dropped because the queue was full
+	DroppedOldest     int64 // Queued entries evicted to make room
+	DroppedBelowLevel int64 // Entries below OverflowConfig.MinLevel that were dropped
+	Spilled           int64 // Entries or batches written to the spill queue instead of being dropped
+}
+
+// Dropped returns the total number of entries lost
+func (s OverflowStats) Dropped() int64 {
+	return s.TimedOut + s.DroppedNewest + s.DroppedOldest + s.DroppedBelowLevel
+}
+
+// overflowCounters holds the atomic counters behind OverflowStats
+type overflowCounters struct {
+	blocked           atomic.Int64
+	timedOut          atomic.Int64
+	droppedNewest     atomic.Int64
+	droppedOldest     atomic.Int64
+	droppedBelowLevel atomic.Int64
+	spilled           atomic.Int64
+}
+
+func (c *overflowCounters) stats() OverflowStats {
+	return OverflowStats{
+		Blocked:           c.blocked.Load(),
+		TimedOut:          c.timedOut.Load(),
+		DroppedNewest:     c.droppedNewest.Load(),
+		DroppedOldest:     c.droppedOldest.Load(),
+		DroppedBelowLevel: c.droppedBelowLevel.Load(),
+		Spilled:           c.spilled.Load(),
+	}
+}
+
+// enqueue sends item to ch, applying the overflow policy when ch is full.
+// Entries evicted by DropOldest are passed to evict. It returns whether item
+// was queued and how many entries, item or evicted ones, were lost.
+func enqueue[T any](ch chan T, item T, level core.Level, conf *OverflowConfig, c *overflowCounters, evict func(T)) (queued bool, lost int64) {
+	select {
+	case ch <- item:
+		return true, 0
+	default:
+	}
+
+	policy := conf.Policy
+	if policy == DropBelowLevel {
+		if level < conf.MinLevel {
+			c.droppedBelowLevel.Add(1)
+			return false, 1
+		}
+		policy = Block
+	}
+
+	switch policy {
+	case Block:
+		ch <- item
+		c.blocked.Add(1)
+		return true, 0
+	case BlockWithTimeout:
+		timer := time.NewTimer(conf.Timeout)
+		defer timer.Stop()
+		select {
+		case ch <- item:
+			c.blocked.Add(1)
+			return true, 0
+		case <-timer.C:
+			c.timedOut.Add(1)
+			return false, 1
+		}
+	case DropOldest:
+		// Other writers may refill the queue between eviction and send, so retry a few times
+		for i := 0; i < 3; i++ {
+			select {
+			case old := <-ch:
+				evict(old)
+				c.droppedOldest.Add(1)
+				lost++
+			default:
+			}
+			select {
+			case ch <- item:
+				return true, lost
+			default:
+			}
+		}
+	}
+
+	c.droppedNewest.Add(1)
+	return false, lost + 1
+}
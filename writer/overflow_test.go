@@ -0,0 +1,91 @@
+package writer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Lunar-Chipter/mire/core"
+	"github.com/Lunar-Chipter/mire/errors"
+)
+
+// TestOverflowPolicyNames tests formatting and parsing policy names
+func TestOverflowPolicyNames(t *testing.T) {
+	for _, p := range []OverflowPolicy{DropNewest, DropOldest, Block, BlockWithTimeout, DropBelowLevel} {
+		parsed, err := ParseOverflowPolicy(p.String())
+		if err != nil || parsed != p {
+			t.Errorf("ParseOverflowPolicy(%q) = %v, %v", p.String(), parsed, err)
+		}
+	}
+	if _, err := ParseOverflowPolicy("spill"); err != errors.ErrInvalidOverflowPolicy {
+		t.Errorf("Expected ErrInvalidOverflowPolicy, got %v", err)
+	}
+	if OverflowPolicy(42).String() != "unknown" {
+		t.Errorf("Expected unknown for an invalid policy, got %s", OverflowPolicy(42))
+	}
+}
+
+// fullChannel returns a channel of capacity 1 holding the value 1
+func fullChannel() chan int {
+	ch := make(chan int, 1)
+	ch <- 1
+	return ch
+}
+
+// TestEnqueueDropPolicies tests the policies that never wait
+func TestEnqueueDropPolicies(t *testing.T) {
+	var c overflowCounters
+	ch := fullChannel()
+	if queued, lost := enqueue(ch, 2, core.ERROR, &OverflowConfig{Policy: DropNewest}, &c, nil); queued || lost != 1 {
+		t.Errorf("DropNewest: queued=%v lost=%d", queued, lost)
+	}
+	if <-ch != 1 {
+		t.Error("DropNewest should keep the queued entry")
+	}
+
+	var evicted []int
+	ch = fullChannel()
+	queued, lost := enqueue(ch, 2, core.INFO, &OverflowConfig{Policy: DropOldest}, &c, func(v int) { evicted = append(evicted, v) })
+	if !queued || lost != 1 || len(evicted) != 1 || evicted[0] != 1 || <-ch != 2 {
+		t.Errorf("DropOldest: queued=%v lost=%d evicted=%v", queued, lost, evicted)
+	}
+
+	ch = fullChannel()
+	conf := &OverflowConfig{Policy: DropBelowLevel, MinLevel: core.WARN}
+	if queued, lost := enqueue(ch, 2, core.INFO, conf, &c, nil); queued || lost != 1 {
+		t.Errorf("DropBelowLevel INFO: queued=%v lost=%d", queued, lost)
+	}
+
+	stats := c.stats()
+	if stats.DroppedNewest != 1 || stats.DroppedOldest != 1 || stats.DroppedBelowLevel != 1 || stats.Dropped() != 3 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+// TestEnqueueBlockingPolicies tests the policies that wait for room
+func TestEnqueueBlockingPolicies(t *testing.T) {
+	var c overflowCounters
+
+	ch := fullChannel()
+	conf := &OverflowConfig{Policy: BlockWithTimeout, Timeout: 5 * time.Millisecond}
+	if queued, lost := enqueue(ch, 2, core.ERROR, conf, &c, nil); queued || lost != 1 {
+		t.Errorf("BlockWithTimeout: queued=%v lost=%d", queued, lost)
+	}
+
+	for _, conf := range []*OverflowConfig{
+		{Policy: Block},
+		{Policy: DropBelowLevel, MinLevel: core.WARN},
+	} {
+		ch := fullChannel()
+		go func() {
+			time.Sleep(5 * time.Millisecond)
+			<-ch
+		}()
+		if queued, lost := enqueue(ch, 2, core.ERROR, conf, &c, nil); !queued || lost != 0 {
+			t.Errorf("%s: queued=%v lost=%d", conf.Policy, queued, lost)
+		}
+	}
+
+	if stats := c.stats(); stats.TimedOut != 1 || stats.Blocked != 2 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
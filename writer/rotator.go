@@ -1,47 +1,398 @@
 package writer
 
 import (
+	"compress/gzip"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/Lunar-Chipter/mire/config"
 )
 
+// DefaultBackupTimeFormat is the time layout used for backup file names
+// when RotationConfig.FilenamePattern is empty.
+const DefaultBackupTimeFormat = "2006-01-02T15-04-05.000"
+
+// compressSuffix is appended to backups compressed with gzip
+const compressSuffix = ".gz"
+
 // Rotator provides a file writer that rotates logs.
+// The active file is rolled over when it would exceed MaxSize or when
+// RotateInterval has elapsed since it was opened. Rotated files are renamed
+// to <name>-<timestamp><ext>, optionally gzipped, and pruned by MaxBackups
+// and MaxAge in a background goroutine.
 type Rotator struct {
-	file   *os.File
-	closed bool
-	mu     sync.Mutex
+	filename string
+	conf     config.RotationConfig
+	file     *os.File
+	size     int64
+	openTime time.Time
+	closed   bool
+	mu       sync.Mutex
+
+	now     func() time.Time // Time source, replaceable in tests
+	millCh  chan struct{}    // Signals the mill goroutine to compress and prune
+	millWg  sync.WaitGroup
+	onError func(error) // Receives errors from background compression and pruning
 }
 
 // NewRotator creates a new rotating file writer.
 func NewRotator(filename string, conf *config.RotationConfig) (*Rotator, error) {
-	f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-	if err != nil {
+	w := &Rotator{
+		filename: filename,
+		now:      time.Now,
+	}
+	if conf != nil {
+		w.conf = *conf
+	}
+
+	if err := w.openExisting(); err != nil {
 		return nil, err
 	}
-	return &Rotator{
-		file:   f,
-		closed: false,
-	}, nil
+
+	if w.conf.Compress || w.conf.MaxBackups > 0 || w.conf.MaxAge > 0 {
+		w.millCh = make(chan struct{}, 1)
+		w.millWg.Add(1)
+		go w.millWorker()
+	}
+
+	return w, nil
+}
+
+// SetErrorHandler sets the function that receives errors from background
+// compression and pruning. Errors are discarded when no handler is set.
+func (w *Rotator) SetErrorHandler(handler func(error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onError = handler
 }
 
 // Write writes data to file, handling rotation if necessary.
 func (w *Rotator) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+
+	if w.shouldRotate(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
 	n, err = w.file.Write(p)
+	w.size += int64(n)
 	return n, err
 }
 
-// Close closes the underlying file.
-func (w *Rotator) Close() error {
+// Rotate forces the current file to be rotated regardless of size or age.
+func (w *Rotator) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return os.ErrClosed
+	}
+	return w.rotate()
+}
+
+// Sync commits the active file to stable storage.
+func (w *Rotator) Sync() error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
 	if w.closed {
+		return os.ErrClosed
+	}
+	return w.file.Sync()
+}
+
+// Close closes the underlying file.
+func (w *Rotator) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
 		return nil
 	}
 
 	err := w.file.Close()
 	w.closed = true
+	w.mu.Unlock()
+
+	// Let any pending compression and pruning finish
+	if w.millCh != nil {
+		close(w.millCh)
+		w.millWg.Wait()
+	}
 	return err
 }
+
+// shouldRotate reports whether the active file must be rotated before writing n bytes.
+// A single write larger than MaxSize is never split; it lands in a fresh file.
+func (w *Rotator) shouldRotate(n int64) bool {
+	if w.conf.MaxSize > 0 && w.size > 0 && w.size+n > w.conf.MaxSize {
+		return true
+	}
+	if w.conf.RotateInterval > 0 && w.now().Sub(w.openTime) >= w.conf.RotateInterval {
+		return true
+	}
+	return false
+}
+
+// openExisting opens the log file in append mode, picking up its current size.
+func (w *Rotator) openExisting() error {
+	f, err := os.OpenFile(w.filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	w.file = f
+	w.size = info.Size()
+	w.openTime = w.now()
+	return nil
+}
+
+// rotate moves the active file to a backup name and opens a fresh file.
+// Must be called with w.mu held.
+func (w *Rotator) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return &wrappedError{msg: "failed to close log file for rotation", cause: err}
+	}
+
+	backup := w.backupName(w.now())
+	if err := os.Rename(w.filename, backup); err != nil && !os.IsNotExist(err) {
+		// Keep logging into the old file rather than losing entries
+		if openErr := w.openExisting(); openErr != nil {
+			return &wrappedError{msg: "failed to reopen log file after failed rotation", cause: openErr}
+		}
+		return &wrappedError{msg: "failed to rename log file to " + backup, cause: err}
+	}
+
+	f, err := os.OpenFile(w.filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+	if err != nil {
+		return &wrappedError{msg: "failed to open new log file " + w.filename, cause: err}
+	}
+	w.file = f
+	w.size = 0
+	w.openTime = w.now()
+
+	if w.millCh != nil {
+		select {
+		case w.millCh <- struct{}{}:
+		default:
+			// A mill run is already pending and will see this backup too
+		}
+	}
+	return nil
+}
+
+// backupName returns an unused backup file name for a rotation at time t.
+func (w *Rotator) backupName(t time.Time) string {
+	if !w.conf.LocalTime {
+		t = t.UTC()
+	}
+	dir, prefix, ext := w.nameParts()
+	stamp := t.Format(w.timeFormat())
+	name := filepath.Join(dir, prefix+stamp+ext)
+
+	// Coarse patterns (e.g. daily) can produce the same name several times
+	for i := 1; backupExists(name); i++ {
+		name = filepath.Join(dir, prefix+stamp+"."+strconv.Itoa(i)+ext)
+	}
+	return name
+}
+
+// timeFormat returns the time layout of backup file names.
+func (w *Rotator) timeFormat() string {
+	if w.conf.FilenamePattern == "" {
+		return DefaultBackupTimeFormat
+	}
+	return w.conf.FilenamePattern
+}
+
+// isBackup reports whether name is a backup written by backupName: the
+// prefix, a timestamp in the backup time layout, an optional ".N" collision
+// suffix and the extension, optionally gzipped. Other files sharing the
+// prefix, such as app-access.log next to app.log, are not backups.
+func (w *Rotator) isBackup(name, prefix, ext string) bool {
+	name = strings.TrimSuffix(name, compressSuffix)
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) || len(name) < len(prefix)+len(ext) {
+		return false
+	}
+	stamp := name[len(prefix) : len(name)-len(ext)]
+	layout := w.timeFormat()
+	if _, err := time.Parse(layout, stamp); err == nil {
+		return true
+	}
+	i := strings.LastIndexByte(stamp, '.')
+	if i < 0 {
+		return false
+	}
+	if n, err := strconv.Atoi(stamp[i+1:]); err != nil || n < 1 {
+		return false
+	}
+	_, err := time.Parse(layout, stamp[:i])
+	return err == nil
+}
+
+// nameParts splits the log file name into directory, backup prefix and extension.
+func (w *Rotator) nameParts() (dir, prefix, ext string) {
+	dir = filepath.Dir(w.filename)
+	base := filepath.Base(w.filename)
+	ext = filepath.Ext(base)
+	prefix = strings.TrimSuffix(base, ext) + "-"
+	return dir, prefix, ext
+}
+
+// backupExists reports whether name or its compressed form already exists.
+func backupExists(name string) bool {
+	if _, err := os.Stat(name); err == nil {
+		return true
+	}
+	if _, err := os.Stat(name + compressSuffix); err == nil {
+		return true
+	}
+	return false
+}
+
+// backupFile describes a rotated log file on disk
+type backupFile struct {
+	path    string
+	modTime time.Time
+}
+
+// listBackups returns rotated files belonging to this writer, newest first.
+func (w *Rotator) listBackups() ([]backupFile, error) {
+	dir, prefix, ext := w.nameParts()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	active := filepath.Base(w.filename)
+	backups := make([]backupFile, 0, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || name == active || !w.isBackup(name, prefix, ext) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backupFile{path: filepath.Join(dir, name), modTime: info.ModTime()})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].modTime.Equal(backups[j].modTime) {
+			return backups[i].path > backups[j].path
+		}
+		return backups[i].modTime.After(backups[j].modTime)
+	})
+	return backups, nil
+}
+
+// millWorker compresses and prunes backups whenever a rotation happens.
+func (w *Rotator) millWorker() {
+	defer w.millWg.Done()
+	for range w.millCh {
+		w.reportError(w.mill())
+	}
+}
+
+// mill applies MaxBackups, MaxAge and Compress to the current set of backups.
+func (w *Rotator) mill() error {
+	backups, err := w.listBackups()
+	if err != nil {
+		return &wrappedError{msg: "failed to list log backups", cause: err}
+	}
+
+	var firstErr error
+	keep := backups[:0]
+	cutoff := w.now().Add(-w.conf.MaxAge)
+	for i, b := range backups {
+		expired := w.conf.MaxAge > 0 && b.modTime.Before(cutoff)
+		excess := w.conf.MaxBackups > 0 && i >= w.conf.MaxBackups
+		if expired || excess {
+			if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) && firstErr == nil {
+				firstErr = &wrappedError{msg: "failed to remove old log file " + b.path, cause: err}
+			}
+			continue
+		}
+		keep = append(keep, b)
+	}
+
+	if w.conf.Compress {
+		for _, b := range keep {
+			if strings.HasSuffix(b.path, compressSuffix) {
+				continue
+			}
+			if err := compressFile(b.path, b.modTime); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// reportError forwards a background error to the configured handler.
+func (w *Rotator) reportError(err error) {
+	if err == nil {
+		return
+	}
+	w.mu.Lock()
+	handler := w.onError
+	w.mu.Unlock()
+	if handler != nil {
+		handler(err)
+	}
+}
+
+// compressFile gzips src into src.gz, keeps its modification time and removes src.
+func compressFile(src string, modTime time.Time) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return &wrappedError{msg: "failed to open log file for compression", cause: err}
+	}
+	defer in.Close()
+
+	dst := src + compressSuffix
+	tmp := dst + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+	if err != nil {
+		return &wrappedError{msg: "failed to create compressed log file", cause: err}
+	}
+
+	gz := gzip.NewWriter(out)
+	gz.Name = filepath.Base(src)
+	gz.ModTime = modTime
+	_, err = io.Copy(gz, in)
+	if closeErr := gz.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return &wrappedError{msg: "failed to compress log file " + src, cause: err}
+	}
+
+	if err := os.Rename(tmp, dst); err != nil {
+		_ = os.Remove(tmp)
+		return &wrappedError{msg: "failed to finalize compressed log file", cause: err}
+	}
+	_ = os.Chtimes(dst, modTime, modTime)
+	return os.Remove(src)
+}
@@ -1,6 +1,8 @@
 package writer
 
 import (
+	"compress/gzip"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -177,7 +179,7 @@ func TestRotatorClose(t *testing.T) {
 	}
 }
 
-// TestRotatorWithLargeData tests writing large amounts of data
+// TestRotatorWithLargeData tests that exceeding MaxSize rolls the file over
 func TestRotatorWithLargeData(t *testing.T) {
 	tempDir := t.TempDir()
 	tempFile := filepath.Join(tempDir, "test_large.log")
@@ -192,7 +194,6 @@ func TestRotatorWithLargeData(t *testing.T) {
 	}
 	defer rotatingWriter.Close()
 
-	// Write larger data to potentially trigger rotation logic (though the current implementation doesn't actually rotate)
 	largeData := make([]byte, 400) // Close to max size
 	for i := range largeData {
 		largeData[i] = byte('A' + (i % 26))
@@ -212,7 +213,7 @@ func TestRotatorWithLargeData(t *testing.T) {
 		moreData[i] = byte('B' + (i % 26))
 	}
 
-	// This write might cause rotation in a full implementation
+	// This write exceeds MaxSize and must go to a fresh file
 	n2, err2 := rotatingWriter.Write(moreData)
 	if err2 != nil {
 		t.Errorf("Second write returned error: %v", err2)
@@ -221,12 +222,23 @@ func TestRotatorWithLargeData(t *testing.T) {
 		t.Errorf("Second write returned %d, expected %d", n2, len(moreData))
 	}
 
-	// Check final file size
 	content, err := os.ReadFile(tempFile)
 	if err != nil {
 		t.Errorf("Failed to read log file: %v", err)
-	} else if int64(len(content)) < int64(len(largeData)+len(moreData)) {
-		t.Errorf("File size %d is smaller than expected total %d", len(content), len(largeData)+len(moreData))
+	} else if string(content) != string(moreData) {
+		t.Errorf("Active file should only hold the second write, got %d bytes", len(content))
+	}
+
+	backups := listBackupNames(t, tempDir, "test_large-")
+	if len(backups) != 1 {
+		t.Fatalf("Expected 1 backup file, got %v", backups)
+	}
+	backupContent, err := os.ReadFile(filepath.Join(tempDir, backups[0]))
+	if err != nil {
+		t.Fatalf("Failed to read backup file: %v", err)
+	}
+	if string(backupContent) != string(largeData) {
+		t.Errorf("Backup file should hold the first write, got %d bytes", len(backupContent))
 	}
 }
 
@@ -313,3 +325,316 @@ func TestRotatorWithNonExistentDir(t *testing.T) {
 		rotatingWriter.Close()
 	}
 }
+
+// listBackupNames returns the names of files in dir starting with prefix
+func listBackupNames(t *testing.T, dir, prefix string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read dir: %v", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), prefix) {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+// TestRotatorPicksUpExistingSize tests that MaxSize accounts for data already in the file
+func TestRotatorPicksUpExistingSize(t *testing.T) {
+	tempDir := t.TempDir()
+	tempFile := filepath.Join(tempDir, "existing.log")
+	if err := os.WriteFile(tempFile, make([]byte, 90), 0644); err != nil {
+		t.Fatalf("Failed to seed log file: %v", err)
+	}
+
+	rotatingWriter, err := NewRotator(tempFile, &config.RotationConfig{MaxSize: 100})
+	if err != nil {
+		t.Fatalf("NewRotator returned error: %v", err)
+	}
+	defer rotatingWriter.Close()
+
+	if _, err := rotatingWriter.Write([]byte("0123456789ABCDEF")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	if backups := listBackupNames(t, tempDir, "existing-"); len(backups) != 1 {
+		t.Errorf("Expected rotation of pre-existing file, got backups %v", backups)
+	}
+}
+
+// TestRotatorRotateInterval tests time-based rotation and UTC backup naming
+func TestRotatorRotateInterval(t *testing.T) {
+	tempDir := t.TempDir()
+	tempFile := filepath.Join(tempDir, "interval.log")
+
+	now := time.Date(2024, 3, 10, 23, 30, 0, 0, time.FixedZone("UTC+2", 2*3600))
+	rotatingWriter, err := NewRotator(tempFile, &config.RotationConfig{
+		RotateInterval:  time.Hour,
+		FilenamePattern: "2006-01-02T15",
+	})
+	if err != nil {
+		t.Fatalf("NewRotator returned error: %v", err)
+	}
+	defer rotatingWriter.Close()
+	rotatingWriter.now = func() time.Time { return now }
+	rotatingWriter.openTime = now
+
+	_, _ = rotatingWriter.Write([]byte("first\n"))
+	now = now.Add(30 * time.Minute)
+	_, _ = rotatingWriter.Write([]byte("second\n"))
+	if backups := listBackupNames(t, tempDir, "interval-"); len(backups) != 0 {
+		t.Fatalf("Rotation should not happen before interval elapses, got %v", backups)
+	}
+
+	now = now.Add(31 * time.Minute)
+	_, _ = rotatingWriter.Write([]byte("third\n"))
+
+	backups := listBackupNames(t, tempDir, "interval-")
+	if len(backups) != 1 {
+		t.Fatalf("Expected 1 backup after interval, got %v", backups)
+	}
+	// 2024-03-11 00:31 at UTC+2 is 2024-03-10 22:31 UTC
+	if backups[0] != "interval-2024-03-10T22.log" {
+		t.Errorf("Unexpected backup name %q", backups[0])
+	}
+	content, _ := os.ReadFile(filepath.Join(tempDir, backups[0]))
+	if string(content) != "first\nsecond\n" {
+		t.Errorf("Unexpected backup content %q", string(content))
+	}
+}
+
+// TestRotatorLocalTimeAndCollisions tests LocalTime naming and unique names for coarse patterns
+func TestRotatorLocalTimeAndCollisions(t *testing.T) {
+	tempDir := t.TempDir()
+	tempFile := filepath.Join(tempDir, "daily.log")
+
+	zone := time.FixedZone("UTC+2", 2*3600)
+	rotatingWriter, err := NewRotator(tempFile, &config.RotationConfig{
+		LocalTime:       true,
+		FilenamePattern: "2006-01-02",
+	})
+	if err != nil {
+		t.Fatalf("NewRotator returned error: %v", err)
+	}
+	defer rotatingWriter.Close()
+	rotatingWriter.now = func() time.Time { return time.Date(2024, 3, 11, 0, 30, 0, 0, zone) }
+
+	for i := 0; i < 3; i++ {
+		_, _ = rotatingWriter.Write([]byte("entry\n"))
+		if err := rotatingWriter.Rotate(); err != nil {
+			t.Fatalf("Rotate returned error: %v", err)
+		}
+	}
+
+	backups := listBackupNames(t, tempDir, "daily-")
+	expected := []string{"daily-2024-03-11.1.log", "daily-2024-03-11.2.log", "daily-2024-03-11.log"}
+	if strings.Join(backups, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected backups %v, got %v", expected, backups)
+	}
+}
+
+// TestRotatorMaxBackups tests pruning by MaxBackups
+func TestRotatorMaxBackups(t *testing.T) {
+	tempDir := t.TempDir()
+	tempFile := filepath.Join(tempDir, "pruned.log")
+
+	rotatingWriter, err := NewRotator(tempFile, &config.RotationConfig{MaxSize: 10, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("NewRotator returned error: %v", err)
+	}
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	step := 0
+	rotatingWriter.now = func() time.Time { return base.Add(time.Duration(step) * time.Second) }
+
+	for step = 0; step < 5; step++ {
+		_, _ = rotatingWriter.Write([]byte("0123456789"))
+	}
+	if err := rotatingWriter.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	backups := listBackupNames(t, tempDir, "pruned-")
+	if len(backups) != 2 {
+		t.Fatalf("Expected 2 backups to be kept, got %v", backups)
+	}
+	if backups[0] != "pruned-2024-01-01T00-00-03.000.log" || backups[1] != "pruned-2024-01-01T00-00-04.000.log" {
+		t.Errorf("Newest backups should be kept, got %v", backups)
+	}
+}
+
+// TestRotatorMaxAge tests pruning by MaxAge
+func TestRotatorMaxAge(t *testing.T) {
+	tempDir := t.TempDir()
+	tempFile := filepath.Join(tempDir, "aged.log")
+
+	stale := filepath.Join(tempDir, "aged-2000-01-01T00-00-00.000.log")
+	if err := os.WriteFile(stale, []byte("old\n"), 0644); err != nil {
+		t.Fatalf("Failed to create stale backup: %v", err)
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(stale, old, old); err != nil {
+		t.Fatalf("Failed to age stale backup: %v", err)
+	}
+	unrelated := filepath.Join(tempDir, "other.log")
+	if err := os.WriteFile(unrelated, []byte("keep\n"), 0644); err != nil {
+		t.Fatalf("Failed to create unrelated file: %v", err)
+	}
+
+	rotatingWriter, err := NewRotator(tempFile, &config.RotationConfig{MaxAge: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("NewRotator returned error: %v", err)
+	}
+	_, _ = rotatingWriter.Write([]byte("fresh\n"))
+	if err := rotatingWriter.Rotate(); err != nil {
+		t.Fatalf("Rotate returned error: %v", err)
+	}
+	rotatingWriter.Close()
+
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("Backup older than MaxAge should be removed")
+	}
+	if _, err := os.Stat(unrelated); err != nil {
+		t.Error("Files not belonging to the rotator must not be touched")
+	}
+	if backups := listBackupNames(t, tempDir, "aged-"); len(backups) != 1 {
+		t.Errorf("Fresh backup should be kept, got %v", backups)
+	}
+}
+
+// TestRotatorIgnoresSiblings tests that files sharing the backup prefix but
+// not named like backups are neither pruned nor compressed
+func TestRotatorIgnoresSiblings(t *testing.T) {
+	tempDir := t.TempDir()
+	tempFile := filepath.Join(tempDir, "app.log")
+
+	old := time.Now().Add(-48 * time.Hour)
+	siblings := []string{"app-access.log", "app-2024.log", "app-2024-01-01T00-00-00.000.x.log"}
+	for _, name := range siblings {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte("keep\n"), 0644); err != nil {
+			t.Fatalf("Failed to create sibling: %v", err)
+		}
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatalf("Failed to age sibling: %v", err)
+		}
+	}
+
+	rotatingWriter, err := NewRotator(tempFile, &config.RotationConfig{MaxBackups: 1, MaxAge: time.Hour, Compress: true})
+	if err != nil {
+		t.Fatalf("NewRotator returned error: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		_, _ = rotatingWriter.Write([]byte("entry\n"))
+		if err := rotatingWriter.Rotate(); err != nil {
+			t.Fatalf("Rotate returned error: %v", err)
+		}
+	}
+	rotatingWriter.Close()
+
+	for _, name := range siblings {
+		if _, err := os.Stat(filepath.Join(tempDir, name)); err != nil {
+			t.Errorf("Sibling %s must not be touched: %v", name, err)
+		}
+	}
+	backups := listBackupNames(t, tempDir, "app-")
+	if len(backups) != len(siblings)+1 {
+		t.Errorf("Expected one backup next to the siblings, got %v", backups)
+	}
+}
+
+// TestRotatorCompress tests gzip compression of rotated files
+func TestRotatorCompress(t *testing.T) {
+	tempDir := t.TempDir()
+	tempFile := filepath.Join(tempDir, "zipped.log")
+
+	rotatingWriter, err := NewRotator(tempFile, &config.RotationConfig{Compress: true})
+	if err != nil {
+		t.Fatalf("NewRotator returned error: %v", err)
+	}
+	_, _ = rotatingWriter.Write([]byte("compress me\n"))
+	if err := rotatingWriter.Rotate(); err != nil {
+		t.Fatalf("Rotate returned error: %v", err)
+	}
+	rotatingWriter.Close()
+
+	backups := listBackupNames(t, tempDir, "zipped-")
+	if len(backups) != 1 || !strings.HasSuffix(backups[0], ".log.gz") {
+		t.Fatalf("Expected a single gzipped backup, got %v", backups)
+	}
+
+	f, err := os.Open(filepath.Join(tempDir, backups[0]))
+	if err != nil {
+		t.Fatalf("Failed to open compressed backup: %v", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("Backup is not valid gzip: %v", err)
+	}
+	content, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("Failed to decompress backup: %v", err)
+	}
+	if string(content) != "compress me\n" {
+		t.Errorf("Unexpected decompressed content %q", string(content))
+	}
+}
+
+// TestRotatorWriteAfterClose tests that writes after Close fail
+func TestRotatorWriteAfterClose(t *testing.T) {
+	tempFile := filepath.Join(t.TempDir(), "closed.log")
+	rotatingWriter, err := NewRotator(tempFile, nil)
+	if err != nil {
+		t.Fatalf("NewRotator returned error: %v", err)
+	}
+	rotatingWriter.Close()
+
+	if _, err := rotatingWriter.Write([]byte("late\n")); err == nil {
+		t.Error("Write after Close should return an error")
+	}
+}
+
+// TestRotatorSync tests syncing the active file
+func TestRotatorSync(t *testing.T) {
+	rotatingWriter, err := NewRotator(filepath.Join(t.TempDir(), "sync.log"), &config.RotationConfig{})
+	if err != nil {
+		t.Fatalf("NewRotator returned error: %v", err)
+	}
+
+	_, _ = rotatingWriter.Write([]byte("synced\n"))
+	if err := rotatingWriter.Sync(); err != nil {
+		t.Errorf("Sync returned error: %v", err)
+	}
+	if err := SyncWriter(rotatingWriter); err != nil {
+		t.Errorf("SyncWriter returned error: %v", err)
+	}
+
+	_ = rotatingWriter.Close()
+	if err := rotatingWriter.Sync(); err != os.ErrClosed {
+		t.Errorf("Expected os.ErrClosed after Close, got %v", err)
+	}
+	if err := SyncWriter(os.Stdout); err != nil {
+		t.Errorf("SyncWriter should skip stdout, got %v", err)
+	}
+
+	// Pipes and /dev/null cannot be synced
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Pipe failed: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+	if err := SyncWriter(w); err != nil {
+		t.Errorf("SyncWriter should skip pipes, got %v", err)
+	}
+	if null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0); err == nil {
+		if err := SyncWriter(null); err != nil {
+			t.Errorf("SyncWriter should skip %s, got %v", os.DevNull, err)
+		}
+		_ = null.Close()
+	}
+}
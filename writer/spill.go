@@ -0,0 +1,378 @@
+package writer
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Lunar-Chipter/mire/errors"
+)
+
+const (
+	// DefaultSpillSegmentSize is the size at which a new spill segment file is started
+	DefaultSpillSegmentSize = 16 << 20
+
+	spillSegmentPrefix = "spill-"
+	spillSegmentExt    = ".seg"
+	spillHeaderSize    = 8 // uint32 payload length + uint32 CRC-32 of the payload
+
+	// spillFirstSeq numbers the first segment so that Prepend has room below it
+	spillFirstSeq = 1 << 32
+)
+
+// SpillConfig configures a SpillQueue
+type SpillConfig struct {
+	Dir         string // Directory holding the segment files
+	SegmentSize int64  // Size at which a new segment is started (default DefaultSpillSegmentSize)
+	MaxBytes    int64  // Maximum size of unreplayed records; 0 means unlimited
+}
+
+// spillSegment is a segment file of the queue
+type spillSegment struct {
+	seq     uint64
+	path    string
+	size    int64
+	readOff int64 // Offset of the next unread record
+}
+
+// SpillQueue is an on-disk FIFO of log records stored in append-only segment files.
+// Each record carries a CRC-32 checksum; a torn or corrupt record ends its segment.
+// Records left in the directory are replayed after a restart, so delivery is
+// at least once: records of a partly replayed segment may be replayed again.
+type SpillQueue struct {
+	mu       sync.Mutex
+	conf     SpillConfig
+	segments []*spillSegment // Oldest first; the last one is written to
+	out      *os.File        // Active segment, opened for appending
+	in       *os.File        // Oldest segment, opened for reading
+	next     []byte          // Record returned by Peek, consumed by Commit
+	unread   int64           // Bytes of records not yet committed
+	corrupt  int64           // Segments cut short by a corrupt record
+	header   [spillHeaderSize]byte
+}
+
+// NewSpillQueue opens the queue in conf.Dir, creating the directory if needed.
+// Records spilled by a previous process are kept and replayed first.
+func NewSpillQueue(conf SpillConfig) (*SpillQueue, error) {
+	if conf.SegmentSize <= 0 {
+		conf.SegmentSize = DefaultSpillSegmentSize
+	}
+	if err := os.MkdirAll(conf.Dir, 0755); err != nil {
+		return nil, &wrappedError{msg: "failed to create spill directory", cause: err}
+	}
+
+	q := &SpillQueue{conf: conf}
+	if err := q.loadSegments(); err != nil {
+		return nil, err
+	}
+	// Never append after a possibly torn record of a previous process
+	if err := q.startSegment(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// loadSegments picks up the segment files left in the directory
+func (q *SpillQueue) loadSegments() error {
+	entries, err := os.ReadDir(q.conf.Dir)
+	if err != nil {
+		return &wrappedError{msg: "failed to read spill directory", cause: err}
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, spillSegmentPrefix) || !strings.HasSuffix(name, spillSegmentExt) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, spillSegmentPrefix), spillSegmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return &wrappedError{msg: "failed to stat spill segment " + name, cause: err}
+		}
+		q.segments = append(q.segments, &spillSegment{seq: seq, path: filepath.Join(q.conf.Dir, name), size: info.Size()})
+		q.unread += info.Size()
+	}
+	sort.Slice(q.segments, func(i, j int) bool { return q.segments[i].seq < q.segments[j].seq })
+	return nil
+}
+
+// startSegment closes the active segment and starts a new one
+func (q *SpillQueue) startSegment() error {
+	var seq uint64 = spillFirstSeq
+	if n := len(q.segments); n > 0 {
+		seq = q.segments[n-1].seq + 1
+	}
+	path := filepath.Join(q.conf.Dir, spillSegmentPrefix+padSeq(seq)+spillSegmentExt)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return &wrappedError{msg: "failed to create spill segment " + path, cause: err}
+	}
+	if q.out != nil {
+		_ = q.out.Close()
+	}
+	q.out = f
+	q.segments = append(q.segments, &spillSegment{seq: seq, path: path})
+	return nil
+}
+
+// padSeq formats a segment sequence number so that file names sort in order
+func padSeq(seq uint64) string {
+	s := strconv.FormatUint(seq, 10)
+	return strings.Repeat("0", 20-len(s)) + s
+}
+
+// Append adds a record to the end of the queue
+func (q *SpillQueue) Append(p []byte) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.out == nil {
+		return os.ErrClosed
+	}
+	n := int64(spillHeaderSize + len(p))
+	if q.conf.MaxBytes > 0 && q.unread+n > q.conf.MaxBytes {
+		return errors.ErrSpillFull
+	}
+
+	active := q.segments[len(q.segments)-1]
+	if active.size > 0 && active.size+n > q.conf.SegmentSize {
+		if err := q.startSegment(); err != nil {
+			return err
+		}
+		active = q.segments[len(q.segments)-1]
+	}
+
+	// Header and payload go out in a single write so readers never see half a record
+	if _, err := q.out.Write(encodeSpillRecord(p)); err != nil {
+		return &wrappedError{msg: "failed to write spill segment", cause: err}
+	}
+	active.size += n
+	q.unread += n
+	return nil
+}
+
+// Prepend puts a record in front of the queue, ahead of every unread record.
+// It writes the record to a new segment file and syncs it, so it is meant for
+// the rare case of returning data that was taken out of order. A record returned
+// by Peek but not yet committed is read again.
+func (q *SpillQueue) Prepend(p []byte) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.out == nil {
+		return os.ErrClosed
+	}
+	rec := encodeSpillRecord(p)
+	n := int64(len(rec))
+	if q.conf.MaxBytes > 0 && q.unread+n > q.conf.MaxBytes {
+		return errors.ErrSpillFull
+	}
+	head := q.segments[0]
+	if head.seq == 0 {
+		return &wrappedError{msg: "no room to prepend to spill queue"}
+	}
+
+	seq := head.seq - 1
+	path := filepath.Join(q.conf.Dir, spillSegmentPrefix+padSeq(seq)+spillSegmentExt)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0644)
+	if err != nil {
+		return &wrappedError{msg: "failed to create spill segment " + path, cause: err}
+	}
+	_, err = f.Write(rec)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return &wrappedError{msg: "failed to write spill segment", cause: err}
+	}
+
+	if q.in != nil {
+		_ = q.in.Close()
+		q.in = nil
+	}
+	q.next = nil
+	q.segments = append([]*spillSegment{{seq: seq, path: path, size: n}}, q.segments...)
+	q.unread += n
+	return nil
+}
+
+// encodeSpillRecord returns p framed with its length and checksum
+func encodeSpillRecord(p []byte) []byte {
+	rec := make([]byte, spillHeaderSize+len(p))
+	binary.LittleEndian.PutUint32(rec[0:4], uint32(len(p)))
+	binary.LittleEndian.PutUint32(rec[4:8], crc32.ChecksumIEEE(p))
+	copy(rec[spillHeaderSize:], p)
+	return rec
+}
+
+// Peek returns the oldest record without removing it. It returns io.EOF when the
+// queue is empty. The record stays valid until the next call on the queue.
+func (q *SpillQueue) Peek() ([]byte, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.next != nil {
+		return q.next, nil
+	}
+	for q.unread > 0 {
+		seg := q.segments[0]
+		if seg.readOff >= seg.size {
+			if len(q.segments) == 1 {
+				break // Caught up with the writer
+			}
+			q.dropOldest()
+			continue
+		}
+		if q.in == nil {
+			f, err := os.Open(seg.path)
+			if err != nil {
+				return nil, &wrappedError{msg: "failed to open spill segment " + seg.path, cause: err}
+			}
+			q.in = f
+		}
+
+		rec, ok := q.readRecord(seg)
+		if !ok {
+			// Torn or corrupt record: the rest of the segment cannot be trusted
+			q.corrupt++
+			q.unread -= seg.size - seg.readOff
+			seg.readOff = seg.size
+			continue
+		}
+		q.next = rec
+		return rec, nil
+	}
+	return nil, io.EOF
+}
+
+// readRecord reads and verifies the record at the read offset of seg
+func (q *SpillQueue) readRecord(seg *spillSegment) ([]byte, bool) {
+	if seg.size-seg.readOff < spillHeaderSize {
+		return nil, false
+	}
+	if _, err := q.in.ReadAt(q.header[:], seg.readOff); err != nil {
+		return nil, false
+	}
+	n := int64(binary.LittleEndian.Uint32(q.header[0:4]))
+	if n > seg.size-seg.readOff-spillHeaderSize {
+		return nil, false
+	}
+	rec := make([]byte, n)
+	if _, err := q.in.ReadAt(rec, seg.readOff+spillHeaderSize); err != nil {
+		return nil, false
+	}
+	if crc32.ChecksumIEEE(rec) != binary.LittleEndian.Uint32(q.header[4:8]) {
+		return nil, false
+	}
+	return rec, true
+}
+
+// Commit removes the record returned by the last Peek
+func (q *SpillQueue) Commit() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.next == nil {
+		return
+	}
+	n := int64(spillHeaderSize + len(q.next))
+	seg := q.segments[0]
+	seg.readOff += n
+	q.unread -= n
+	q.next = nil
+	if seg.readOff < seg.size {
+		return
+	}
+	if len(q.segments) > 1 {
+		q.dropOldest()
+		return
+	}
+	// Caught up with the writer: empty the active segment, so that the
+	// records are not replayed again after a restart
+	if q.out != nil && q.out.Truncate(0) == nil {
+		if q.in != nil {
+			_ = q.in.Close()
+			q.in = nil
+		}
+		seg.size, seg.readOff = 0, 0
+	}
+}
+
+// dropOldest removes the fully read oldest segment
+func (q *SpillQueue) dropOldest() {
+	if q.in != nil {
+		_ = q.in.Close()
+		q.in = nil
+	}
+	_ = os.Remove(q.segments[0].path)
+	q.segments = q.segments[1:]
+}
+
+// Empty reports whether every record has been committed
+func (q *SpillQueue) Empty() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.unread == 0
+}
+
+// Size returns the number of bytes of records not yet committed
+func (q *SpillQueue) Size() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.unread
+}
+
+// Corrupt returns the number of segments cut short by a torn or corrupt record
+func (q *SpillQueue) Corrupt() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.corrupt
+}
+
+// Sync commits the active segment to stable storage
+func (q *SpillQueue) Sync() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.out == nil {
+		return os.ErrClosed
+	}
+	return q.out.Sync()
+}
+
+// Close syncs and closes the segment files. Unreplayed records stay on disk.
+func (q *SpillQueue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.out == nil {
+		return nil
+	}
+	err := q.out.Sync()
+	if cerr := q.out.Close(); err == nil {
+		err = cerr
+	}
+	q.out = nil
+	if q.in != nil {
+		_ = q.in.Close()
+		q.in = nil
+	}
+	// Remove the segments holding no unreplayed records, the active one included
+	for _, seg := range q.segments {
+		if seg.readOff >= seg.size {
+			_ = os.Remove(seg.path)
+		}
+	}
+	return err
+}
@@ -0,0 +1,187 @@
+package writer
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Lunar-Chipter/mire/errors"
+)
+
+// drainSpill reads and commits every record of the queue
+func drainSpill(t *testing.T, q *SpillQueue) []string {
+	t.Helper()
+	var records []string
+	for {
+		rec, err := q.Peek()
+		if err == io.EOF {
+			return records
+		}
+		if err != nil {
+			t.Fatalf("Peek failed: %v", err)
+		}
+		records = append(records, string(rec))
+		q.Commit()
+	}
+}
+
+// TestSpillQueueOrder tests that records come back in order across segments
+func TestSpillQueueOrder(t *testing.T) {
+	dir := t.TempDir()
+	q, err := NewSpillQueue(SpillConfig{Dir: dir, SegmentSize: 32})
+	if err != nil {
+		t.Fatalf("NewSpillQueue failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	want := []string{"first entry", "second entry", "third entry", "fourth entry"}
+	for _, s := range want {
+		if err := q.Append([]byte(s)); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+	if segs, _ := filepath.Glob(filepath.Join(dir, "*.seg")); len(segs) < 2 {
+		t.Errorf("Expected records to span several segments, got %d", len(segs))
+	}
+
+	got := drainSpill(t, q)
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d records, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Record %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if !q.Empty() || q.Size() != 0 {
+		t.Errorf("Expected empty queue, size %d", q.Size())
+	}
+	if segs, _ := filepath.Glob(filepath.Join(dir, "*.seg")); len(segs) != 1 {
+		t.Errorf("Expected consumed segments to be removed, %d left", len(segs))
+	}
+}
+
+// TestSpillQueueReopen tests that unreplayed records survive a restart
+func TestSpillQueueReopen(t *testing.T) {
+	dir := t.TempDir()
+	q, err := NewSpillQueue(SpillConfig{Dir: dir})
+	if err != nil {
+		t.Fatalf("NewSpillQueue failed: %v", err)
+	}
+	_ = q.Append([]byte("a"))
+	_ = q.Append([]byte("b"))
+	if err := q.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	q, err = NewSpillQueue(SpillConfig{Dir: dir})
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	_ = q.Append([]byte("c"))
+	got := drainSpill(t, q)
+	_ = q.Close()
+
+	if len(got) != 3 || got[0] != "a" || got[1] != "b" || got[2] != "c" {
+		t.Errorf("Expected [a b c], got %v", got)
+	}
+}
+
+// TestSpillQueueReopenAfterReplay tests that committed records are not replayed after a restart
+func TestSpillQueueReopenAfterReplay(t *testing.T) {
+	dir := t.TempDir()
+	q, err := NewSpillQueue(SpillConfig{Dir: dir})
+	if err != nil {
+		t.Fatalf("NewSpillQueue failed: %v", err)
+	}
+	_ = q.Append([]byte("a"))
+	_ = q.Append([]byte("b"))
+	if got := drainSpill(t, q); len(got) != 2 {
+		t.Fatalf("Expected [a b], got %v", got)
+	}
+	_ = q.Append([]byte("c"))
+	if got := drainSpill(t, q); len(got) != 1 || got[0] != "c" {
+		t.Fatalf("Expected [c] after catching up, got %v", got)
+	}
+	if err := q.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	q, err = NewSpillQueue(SpillConfig{Dir: dir})
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+	if !q.Empty() || q.Size() != 0 {
+		t.Errorf("Expected empty queue after reopen, size %d", q.Size())
+	}
+	if got := drainSpill(t, q); len(got) != 0 {
+		t.Errorf("Expected no records replayed again, got %v", got)
+	}
+}
+
+// TestSpillQueueCorruptTail tests that a torn record ends its segment without losing earlier ones
+func TestSpillQueueCorruptTail(t *testing.T) {
+	dir := t.TempDir()
+	q, _ := NewSpillQueue(SpillConfig{Dir: dir})
+	_ = q.Append([]byte("intact"))
+	_ = q.Append([]byte("torn"))
+	_ = q.Close()
+
+	segs, _ := filepath.Glob(filepath.Join(dir, "*.seg"))
+	if len(segs) != 1 {
+		t.Fatalf("Expected one segment, got %d", len(segs))
+	}
+	info, _ := os.Stat(segs[0])
+	if err := os.Truncate(segs[0], info.Size()-2); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := NewSpillQueue(SpillConfig{Dir: dir})
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+	got := drainSpill(t, q)
+	if len(got) != 1 || got[0] != "intact" {
+		t.Errorf("Expected only the intact record, got %v", got)
+	}
+	if q.Corrupt() != 1 || !q.Empty() {
+		t.Errorf("Expected one corrupt segment and an empty queue, got %d and size %d", q.Corrupt(), q.Size())
+	}
+}
+
+// TestSpillQueueMaxBytes tests that appends beyond MaxBytes are refused
+func TestSpillQueueMaxBytes(t *testing.T) {
+	q, _ := NewSpillQueue(SpillConfig{Dir: t.TempDir(), MaxBytes: 20})
+	defer func() { _ = q.Close() }()
+
+	if err := q.Append([]byte("0123456789")); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if err := q.Append([]byte("0123456789")); err != errors.ErrSpillFull {
+		t.Errorf("Expected ErrSpillFull, got %v", err)
+	}
+}
+
+// TestSpillQueuePrepend tests that prepended records are read before older ones, also after a restart
+func TestSpillQueuePrepend(t *testing.T) {
+	dir := t.TempDir()
+	q, _ := NewSpillQueue(SpillConfig{Dir: dir})
+	_ = q.Append([]byte("b"))
+	_ = q.Append([]byte("c"))
+	if rec, _ := q.Peek(); string(rec) != "b" {
+		t.Fatalf("Expected b, got %q", rec)
+	}
+	if err := q.Prepend([]byte("a")); err != nil {
+		t.Fatalf("Prepend failed: %v", err)
+	}
+	_ = q.Close()
+
+	q, _ = NewSpillQueue(SpillConfig{Dir: dir})
+	defer func() { _ = q.Close() }()
+	if got := drainSpill(t, q); len(got) != 3 || got[0] != "a" || got[1] != "b" || got[2] != "c" {
+		t.Errorf("Expected [a b c], got %v", got)
+	}
+}
@@ -0,0 +1,186 @@
+package writer
+
+import (
+	"bytes"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SyslogFraming selects how messages are delimited on stream transports
+type SyslogFraming int
+
+const (
+	// NonTransparentFraming ends every message with a newline (RFC 6587 section 3.4.2)
+	NonTransparentFraming SyslogFraming = iota
+	// OctetCountingFraming prefixes every message with its length (RFC 6587 section 3.4.1)
+	OctetCountingFraming
+)
+
+const (
+	// DefaultSyslogDialTimeout is the default timeout for connecting to the syslog server
+	DefaultSyslogDialTimeout = 5 * time.Second
+	// DefaultSyslogReconnectDelay is the default minimum delay between connection attempts
+	DefaultSyslogReconnectDelay = time.Second
+)
+
+// syslogLocalPaths are the usual sockets of the local syslog daemon
+var syslogLocalPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogConfig configures a Syslog writer
+type SyslogConfig struct {
+	Network        string        // "udp", "tcp", "unix" or "unixgram"; empty selects the local syslog socket
+	Address        string        // Server address or socket path (ignored for the local socket)
+	Framing        SyslogFraming // Message delimiting on stream transports ("tcp" and "unix")
+	DialTimeout    time.Duration // Timeout for connecting (default DefaultSyslogDialTimeout)
+	WriteTimeout   time.Duration // Timeout for sending a message (0 means none)
+	ReconnectDelay time.Duration // Minimum delay between connection attempts (default DefaultSyslogReconnectDelay)
+}
+
+// Syslog is a writer that sends formatted syslog messages, such as those of
+// formatter.SyslogFormatter, to a syslog server. Every line written is sent as
+// one message. A broken connection is re-established on the next write.
+type Syslog struct {
+	conf     SyslogConfig
+	mu       sync.Mutex
+	conn     net.Conn
+	stream   bool      // Whether conn needs framing
+	nextDial time.Time // Earliest time of the next connection attempt
+	frame    []byte
+	closed   bool
+}
+
+// NewSyslog creates a Syslog writer and connects to the server
+func NewSyslog(conf SyslogConfig) (*Syslog, error) {
+	if conf.DialTimeout <= 0 {
+		conf.DialTimeout = DefaultSyslogDialTimeout
+	}
+	if conf.ReconnectDelay <= 0 {
+		conf.ReconnectDelay = DefaultSyslogReconnectDelay
+	}
+	s := &Syslog{conf: conf}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// connect dials the server. The caller must hold s.mu.
+func (s *Syslog) connect() error {
+	s.nextDial = time.Now().Add(s.conf.ReconnectDelay)
+
+	if s.conf.Network != "" {
+		conn, err := net.DialTimeout(s.conf.Network, s.conf.Address, s.conf.DialTimeout)
+		if err != nil {
+			return &wrappedError{msg: "failed to connect to syslog server " + s.conf.Address, cause: err}
+		}
+		s.conn = conn
+		s.stream = s.conf.Network != "udp" && s.conf.Network != "udp4" && s.conf.Network != "udp6" && s.conf.Network != "unixgram"
+		return nil
+	}
+
+	// The local daemon listens on a datagram socket on most systems, and on a stream socket on some
+	var lastErr error
+	for _, path := range syslogLocalPaths {
+		for _, network := range []string{"unixgram", "unix"} {
+			conn, err := net.DialTimeout(network, path, s.conf.DialTimeout)
+			if err == nil {
+				s.conn = conn
+				s.stream = network == "unix"
+				return nil
+			}
+			lastErr = err
+		}
+	}
+	return &wrappedError{msg: "failed to connect to local syslog", cause: lastErr}
+}
+
+// Write sends every line of p as a syslog message. On a write error the
+// connection is re-established and the message sent again once.
+func (s *Syslog) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, &wrappedError{msg: "syslog writer is closed"}
+	}
+
+	for start := 0; start < len(p); {
+		end := bytes.IndexByte(p[start:], '\n')
+		if end < 0 {
+			end = len(p)
+		} else {
+			end += start
+		}
+		if end > start {
+			if err := s.send(p[start:end]); err != nil {
+				return start, err
+			}
+		}
+		start = end + 1
+	}
+	return len(p), nil
+}
+
+// send frames and sends one message, reconnecting once if the connection is broken
+func (s *Syslog) send(msg []byte) error {
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			if time.Now().Before(s.nextDial) {
+				if err == nil {
+					err = &wrappedError{msg: "syslog server unavailable"}
+				}
+				return err
+			}
+			if err = s.connect(); err != nil {
+				return err
+			}
+		}
+
+		if s.conf.WriteTimeout > 0 {
+			_ = s.conn.SetWriteDeadline(time.Now().Add(s.conf.WriteTimeout))
+		}
+		if _, err = s.conn.Write(s.framed(msg)); err == nil {
+			return nil
+		}
+		err = &wrappedError{msg: "failed to send syslog message", cause: err}
+		_ = s.conn.Close()
+		s.conn = nil
+		s.nextDial = time.Time{} // Reconnect right away once
+	}
+	return err
+}
+
+// framed returns msg ready for the connection: as is for datagrams, and
+// delimited by the configured framing for streams
+func (s *Syslog) framed(msg []byte) []byte {
+	if !s.stream {
+		return msg
+	}
+	s.frame = s.frame[:0]
+	if s.conf.Framing == OctetCountingFraming {
+		s.frame = strconv.AppendInt(s.frame, int64(len(msg)), 10)
+		s.frame = append(s.frame, ' ')
+		s.frame = append(s.frame, msg...)
+	} else {
+		s.frame = append(s.frame, msg...)
+		s.frame = append(s.frame, '\n')
+	}
+	return s.frame
+}
+
+// Close closes the connection
+func (s *Syslog) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -0,0 +1,141 @@
+package writer
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestSyslogUDP tests that every line is sent as one datagram
+func TestSyslogUDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("UDP not available: %v", err)
+	}
+	defer pc.Close()
+
+	s, err := NewSyslog(SyslogConfig{Network: "udp", Address: pc.LocalAddr().String()})
+	if err != nil {
+		t.Fatalf("NewSyslog failed: %v", err)
+	}
+	defer s.Close()
+
+	if _, err := s.Write([]byte("<14>1 - - - - - - first\n<14>1 - - - - - - second\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	buf := make([]byte, 1024)
+	for _, want := range []string{"<14>1 - - - - - - first", "<14>1 - - - - - - second"} {
+		_ = pc.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("ReadFrom failed: %v", err)
+		}
+		if string(buf[:n]) != want {
+			t.Errorf("Expected datagram %q, got %q", want, buf[:n])
+		}
+	}
+}
+
+// TestSyslogUnixgram tests sending to a unix datagram socket
+func TestSyslogUnixgram(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.sock")
+	pc, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Skipf("unixgram not available: %v", err)
+	}
+	defer pc.Close()
+
+	s, err := NewSyslog(SyslogConfig{Network: "unixgram", Address: path})
+	if err != nil {
+		t.Fatalf("NewSyslog failed: %v", err)
+	}
+	defer s.Close()
+	_, _ = s.Write([]byte("<14>hello\n"))
+
+	buf := make([]byte, 64)
+	_ = pc.SetReadDeadline(time.Now().Add(time.Second))
+	if n, _, err := pc.ReadFrom(buf); err != nil || string(buf[:n]) != "<14>hello" {
+		t.Errorf("Expected <14>hello, got %q (%v)", buf[:n], err)
+	}
+}
+
+// TestSyslogTCPOctetCounting tests octet-counting framing on TCP
+func TestSyslogTCPOctetCounting(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("TCP not available: %v", err)
+	}
+	defer ln.Close()
+
+	s, err := NewSyslog(SyslogConfig{Network: "tcp", Address: ln.Addr().String(), Framing: OctetCountingFraming})
+	if err != nil {
+		t.Fatalf("NewSyslog failed: %v", err)
+	}
+	defer s.Close()
+
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	_, _ = s.Write([]byte("<14>one\n<14>two\n"))
+	want := "7 <14>one7 <14>two"
+	got := make([]byte, len(want))
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := io.ReadFull(conn, got); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+// TestSyslogTCPReconnect tests that the writer reconnects after the server drops the connection
+func TestSyslogTCPReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("TCP not available: %v", err)
+	}
+	defer ln.Close()
+
+	s, err := NewSyslog(SyslogConfig{Network: "tcp", Address: ln.Addr().String(), ReconnectDelay: time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewSyslog failed: %v", err)
+	}
+	defer s.Close()
+
+	first, _ := ln.Accept()
+	_ = first.Close()
+
+	lines := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		lines <- line
+	}()
+
+	// Writes into a connection the peer closed may appear to succeed until the reset arrives
+	deadline := time.After(5 * time.Second)
+	for {
+		_, _ = s.Write([]byte("<14>after reconnect\n"))
+		select {
+		case line := <-lines:
+			if line != "<14>after reconnect\n" {
+				t.Errorf("Unexpected line %q", line)
+			}
+			return
+		case <-deadline:
+			t.Fatal("Writer did not reconnect")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}